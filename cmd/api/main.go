@@ -1,14 +1,18 @@
 package main
 
 import (
+	"crypto/rand"
 	"database/sql"
 	"flag"
 	"log"
 	"os"
+	"time"
 
 	_ "github.com/famusovsky/VkTestTask/docs"
 	"github.com/famusovsky/VkTestTask/internal/filmoteka"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/auth"
 	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/storage"
 	"github.com/famusovsky/VkTestTask/pkg/database"
 	_ "github.com/lib/pq"
 )
@@ -18,10 +22,20 @@ import (
 // @title			Filemoteka API
 // @description	This is a Filmoteka API server, made for Vk Trainee Assignment 2024.
 // @securityDefinitions.basic  BasicAuth
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Type "Bearer" followed by a space and an access token from /auth/login.
 func main() {
 	addr := flag.String("addr", ":8080", "HTTP address")
 	overrideTables := flag.Bool("override_tables", false, "Override tables in database")
 	defaultAdmin := flag.Bool("default_admin", false, "Add default admin (admin|admin) to database")
+	registration := flag.String("registration", "disabled", "Self-registration mode: open, invite (INVITE_CODE env) or disabled")
+	flag.Bool("migrate_roles", false, "Deprecated: roles tables are created at every start")
+	accessTTL := flag.Duration("access_ttl", 15*time.Minute, "Lifetime of bearer access tokens")
+	refreshTTL := flag.Duration("refresh_ttl", 24*time.Hour, "Lifetime of refresh tokens and sessions")
+	mediaDir := flag.String("media_dir", "media", "Directory for uploaded posters and photos, empty to disable uploads")
+	mediaURL := flag.String("media_url", "/media/", "Base URL of uploaded files, served by the API under /media/")
 	flag.Parse()
 
 	infoLog := log.New(os.Stdout, "INFO\t", log.Ldate|log.Ltime)
@@ -37,8 +51,36 @@ func main() {
 	if err != nil {
 		errorLog.Fatal(err)
 	}
+	if err = postgres.MigrateRoles(db); err != nil {
+		errorLog.Fatal(err)
+	}
+	if err = postgres.MigrateUsers(db); err != nil {
+		errorLog.Fatal(err)
+	}
+
+	secret := []byte(os.Getenv("TOKEN_SECRET"))
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err = rand.Read(secret); err != nil {
+			errorLog.Fatal(err)
+		}
+		infoLog.Println("TOKEN_SECRET is not set, issued tokens will not survive restart")
+	}
+	signer := auth.NewSigner(secret, *accessTTL, *refreshTTL)
+
+	policy, err := filmoteka.ParseRegistrationPolicy(*registration, os.Getenv("INVITE_CODE"))
+	if err != nil {
+		errorLog.Fatal(err)
+	}
+
+	var store storage.Storage
+	if *mediaDir != "" {
+		if store, err = storage.NewFS(*mediaDir, *mediaURL); err != nil {
+			errorLog.Fatal(err)
+		}
+	}
 
-	app := filmoteka.CreateApp(*addr, infoLog, errorLog, dbHandler, *defaultAdmin)
+	app := filmoteka.CreateApp(*addr, infoLog, errorLog, dbHandler, *defaultAdmin, signer, policy, store)
 
 	app.Run()
 }
@@ -20,9 +20,12 @@ const docTemplate = `{
                 "security": [
                     {
                         "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Add actor to the System and get it's ID. User should be an admin. All fields are required.",
+                "description": "Add actor to the System and get it's ID. User should have the catalog:write permission. All fields are required.",
                 "consumes": [
                     "application/json"
                 ],
@@ -54,19 +57,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "403": {
-                        "description": "User not an admin",
+                        "description": "Permission denied",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
@@ -77,9 +86,12 @@ const docTemplate = `{
                 "security": [
                     {
                         "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Get actor from the System.",
+                "description": "Get actor from the System. User should have the catalog:read permission.",
                 "produces": [
                     "application/json"
                 ],
@@ -106,19 +118,31 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "403": {
-                        "description": "User does not exist",
+                        "description": "Permission denied",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Actor not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
@@ -127,9 +151,12 @@ const docTemplate = `{
                 "security": [
                     {
                         "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Update actor in the System. User should be an admin. All fields are not required.",
+                "description": "Update actor in the System. User should have the catalog:write permission. All fields are not required.",
                 "consumes": [
                     "application/json"
                 ],
@@ -168,19 +195,31 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "403": {
-                        "description": "User not an admin",
+                        "description": "Permission denied",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Actor not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
@@ -189,9 +228,12 @@ const docTemplate = `{
                 "security": [
                     {
                         "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Delete actor from the System. User should be an admin.",
+                "description": "Delete actor from the System together with the photo. User should have the catalog:write permission.",
                 "produces": [
                     "application/json"
                 ],
@@ -218,360 +260,540 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "403": {
-                        "description": "User not an admin",
+                        "description": "Permission denied",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Actor not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
             }
         },
-        "/actors": {
-            "get": {
+        "/actor/{id}/photo": {
+            "put": {
                 "security": [
                     {
                         "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Get actors from the System.",
+                "description": "Upload JPEG, PNG or GIF headshot up to 5 MB as the image field of multipart form, replacing the previous photo. A JPEG thumbnail is generated. User should have the catalog:write permission.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Actor"
                 ],
-                "summary": "Get actors from the System.",
+                "summary": "Uploads photo of the actor.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the actor",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Photo",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.ActorOut"
-                            }
+                            "$ref": "#/definitions/models.Image"
                         }
                     },
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "403": {
-                        "description": "User does not exist",
+                        "description": "Permission denied",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Actor not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Image is too large",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported image type",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "501": {
+                        "description": "Image uploads are disabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
-            }
-        },
-        "/movie": {
-            "post": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Add movie to the System and get it's ID. User should be an admin.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Delete photo of the actor together with its thumbnail. User should have the catalog:write permission.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Movie"
+                    "Actor"
                 ],
-                "summary": "Adds movie to the System.",
+                "summary": "Deletes photo of the actor.",
                 "parameters": [
                     {
-                        "description": "Movie to be added",
-                        "name": "movie",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.MovieIn"
-                        }
+                        "type": "integer",
+                        "description": "ID of the actor",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "ID of the added movie",
+                        "description": "OK",
                         "schema": {
-                            "type": "integer"
+                            "type": "string"
                         }
                     },
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "403": {
-                        "description": "User not an admin",
+                        "description": "Permission denied",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Actor not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "501": {
+                        "description": "Image uploads are disabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
             }
         },
-        "/movie/{id}": {
-            "put": {
+        "/actors": {
+            "get": {
                 "security": [
                     {
                         "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Update movie in the System. User should be an admin.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get actors from the System, optionally searching by a case-insensitive fragment of the name or a similar name in Cyrillic or Latin, ordered by similarity. User should have the catalog:read permission.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Movie"
+                    "Actor"
                 ],
-                "summary": "Updates movie in the System.",
+                "summary": "Get actors from the System.",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "default": "id",
+                        "description": "Comma separated sort fields (id, name, gender, date_of_birth), prefixed with - for descending order, ignored when searching by name",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fragment of the actor's name, Cyrillic or Latin",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.4,
+                        "description": "Minimal trigram similarity of names, (0, 1]",
+                        "name": "similarity",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "ID of the movie to be updated",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "default": 50,
+                        "description": "Page size, 1 - 500",
+                        "name": "limit",
+                        "in": "query"
                     },
                     {
-                        "description": "Movie data to be updated",
-                        "name": "movie",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.MovieIn"
-                        }
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ActorOut"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of items"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "403": {
-                        "description": "User not an admin",
+                        "description": "Permission denied",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BasicAuth": []
-                    }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Exchange nickname and password for a signed bearer access token and a refresh token.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Delete movie from the System. User should be an admin.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Movie"
+                    "Auth"
                 ],
-                "summary": "Deletes movie from the System.",
+                "summary": "Logs user in.",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID of the movie to be deleted",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "User credentials",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Tokens"
                         }
                     },
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
-                    "403": {
-                        "description": "User not an admin",
+                    "401": {
+                        "description": "Wrong credentials",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
             }
         },
-        "/movies": {
-            "get": {
+        "/auth/logout": {
+            "post": {
                 "security": [
                     {
-                        "BasicAuth": []
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Get movies from the System.",
+                "description": "Revoke the session of the bearer token, so its access and refresh tokens stop working.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Movie"
-                ],
-                "summary": "Get movies from the System.",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Sort movies by name, release date or rating",
-                        "name": "sort",
-                        "in": "query"
-                    }
+                    "Auth"
                 ],
+                "summary": "Logs user out.",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.MovieOut"
-                            }
+                            "type": "string"
                         }
                     },
-                    "403": {
-                        "description": "User does not exist",
+                    "401": {
+                        "description": "Invalid access token",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
             }
         },
-        "/movies/actor/{actor}": {
-            "get": {
-                "security": [
-                    {
-                        "BasicAuth": []
-                    }
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token. The session must not be revoked.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get movies from the System by actor.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Movie"
+                    "Auth"
                 ],
-                "summary": "Get movies from the System by actor.",
+                "summary": "Refreshes access token.",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Name of the actor to be getted",
-                        "name": "actor",
-                        "in": "path",
-                        "required": true
+                        "description": "Refresh token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RefreshRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.MovieOut"
-                            }
+                            "$ref": "#/definitions/models.Tokens"
                         }
                     },
-                    "403": {
-                        "description": "User does not exist",
+                    "400": {
+                        "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
             }
         },
-        "/movies/name/{name}": {
+        "/auth/register": {
+            "post": {
+                "description": "Create a viewer account and get it's ID. Available if registration is open or by invite code.\nNickname must be 3-32 latin letters, digits, '_', '-' or '.'; password must be 8-72 bytes with a letter and a digit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Registers a new user.",
+                "parameters": [
+                    {
+                        "description": "Registration data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Registration"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Wrong invite code",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Registration is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Nickname is taken",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections": {
             "get": {
                 "security": [
                     {
                         "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Get movies from the System by name.",
+                "description": "Get public collections and private collections of the authenticated user ordered by name. Users with the catalog:write permission get all collections.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Movie"
+                    "Collection"
                 ],
-                "summary": "Get movies from the System by name.",
+                "summary": "Get collections from the System.",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Name of the movie to be getted",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size, 1 - 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -580,33 +802,199 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.MovieOut"
+                                "$ref": "#/definitions/models.CollectionOut"
                             }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of items"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "403": {
-                        "description": "User does not exist",
+                        "description": "Permission denied",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add collection or franchise owned by the authenticated user and get it's ID. Movies keep the given order.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Adds collection to the System.",
+                "parameters": [
+                    {
+                        "description": "Collection to be added",
+                        "name": "collection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ID of the added collection",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied or built-in admin",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
                 }
             }
         },
-        "/users": {
-            "post": {
+        "/collections/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get public collection, or private collection of the authenticated user, with its movies in order. Users with the catalog:write permission can get any collection.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Get collection from the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the collection to be getted",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionOut"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
                 "security": [
                     {
                         "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Add user to the System and get it's ID. User should be an admin.",
+                "description": "Update name, description, kind, visibility and movies of the collection. Only the given fields are changed. Only the owner or a user with the catalog:write permission can update the collection.",
                 "consumes": [
                     "application/json"
                 ],
@@ -614,151 +1002,4715 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Collection"
                 ],
-                "summary": "Adds user to the System.",
+                "summary": "Updates collection in the System.",
                 "parameters": [
                     {
-                        "description": "User to be added",
-                        "name": "user",
+                        "type": "integer",
+                        "description": "ID of the collection to be updated",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Collection data to be updated",
+                        "name": "collection",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.CollectionIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "ID of the added user",
-                        "schema": {
-                            "type": "integer"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "403": {
-                        "description": "User not an admin",
-                        "schema": {
-                            "type": "string"
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete collection from the System, movies are kept. Only the owner or a user with the catalog:write permission can delete the collection.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Deletes collection from the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the collection to be deleted",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/movies": {
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace movies of the collection with the given ordered list. Only the owner or a user with the catalog:write permission can change the collection.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "Reorders movies of collection.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the collection",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Movies in the new order",
+                        "name": "movies",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionMoviesIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/episodes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get episode from the System with its guest cast. User should have the catalog:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Get episode from the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the episode to be getted",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EpisodeOut"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Episode not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update episode of the season, empty fields are left unchanged. User should have the catalog:write permission. Cast replaces all guest actors of the episode.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Updates episode in the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the episode to be updated",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Episode data to be updated",
+                        "name": "episode",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EpisodeIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Episode not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Episode with the number already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Actor not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete episode from the System. User should have the catalog:write permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Deletes episode from the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the episode to be deleted",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Episode not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all genres from the System ordered by name. User should have the catalog:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Genre"
+                ],
+                "summary": "Get genres from the System.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.GenreOut"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add genre to the System and get it's ID. User should have the catalog:write permission. Genre names are unique.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Genre"
+                ],
+                "summary": "Adds genre to the System.",
+                "parameters": [
+                    {
+                        "description": "Genre to be added",
+                        "name": "genre",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GenreIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ID of the added genre",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Genre already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get genre from the System. User should have the catalog:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Genre"
+                ],
+                "summary": "Get genre from the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the genre to be getted",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenreOut"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Genre not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rename genre in the System. User should have the catalog:write permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Genre"
+                ],
+                "summary": "Updates genre in the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the genre to be updated",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Genre data to be updated",
+                        "name": "genre",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GenreIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Genre not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Genre already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete genre from the System, movies lose the genre. User should have the catalog:write permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Genre"
+                ],
+                "summary": "Deletes genre from the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the genre to be deleted",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Genre not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/{key}": {
+            "get": {
+                "description": "Get poster, photo or thumbnail by the URL returned in movies and actors. Does not require authentication.",
+                "produces": [
+                    "image/jpeg",
+                    "image/png",
+                    "image/gif"
+                ],
+                "tags": [
+                    "Media"
+                ],
+                "summary": "Get image file.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key of the file",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "File not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/movie": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add movie to the System and get it's ID. User should have the catalog:write permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movie"
+                ],
+                "summary": "Adds movie to the System.",
+                "parameters": [
+                    {
+                        "description": "Movie to be added",
+                        "name": "movie",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ID of the added movie",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Actor not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/movie/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get movie from the System. User should have the catalog:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movie"
+                ],
+                "summary": "Get movie from the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the movie to be getted",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include status of the movies in personal lists of the current user",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieOut"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update movie in the System. User should have the catalog:write permission. Credits replace all people of the movie, actors alone replace only its actors.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movie"
+                ],
+                "summary": "Updates movie in the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the movie to be updated",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Movie data to be updated",
+                        "name": "movie",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MovieIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Actor not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete movie from the System together with the poster. User should have the catalog:write permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movie"
+                ],
+                "summary": "Deletes movie from the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the movie to be deleted",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/movie/{id}/poster": {
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload JPEG, PNG or GIF poster up to 5 MB as the image field of multipart form, replacing the previous poster. A JPEG thumbnail is generated. User should have the catalog:write permission.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movie"
+                ],
+                "summary": "Uploads poster of the movie.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the movie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Poster",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Image"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Image is too large",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported image type",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "501": {
+                        "description": "Image uploads are disabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete poster of the movie together with its thumbnail. User should have the catalog:write permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movie"
+                ],
+                "summary": "Deletes poster of the movie.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the movie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "501": {
+                        "description": "Image uploads are disabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/movie/{id}/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get reviews of the movie, most recently updated first. User should have the catalog:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Get reviews of movie.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the movie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size, 1 - 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ReviewOut"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of items"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add rating and optional text review of the movie by the authenticated user and get it's ID. A user can review a movie only once, use PUT /reviews/{id} to change the review.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Rates and reviews movie.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the movie to be reviewed",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rating and review",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ID of the added review",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Built-in admin has no personal data",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Movie is already reviewed by user",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get movies from the System, filtered by rating, release date, actors, genres, name, runtime, countries, languages, age rating, budget and box office. All filters are combined. User should have the catalog:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movie"
+                ],
+                "summary": "Get movies from the System.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "-rating",
+                        "description": "Comma separated sort fields (id, name, release_date, rating), prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimal rating, 0 - 10",
+                        "name": "min_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximal rating, 0 - 10",
+                        "name": "max_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest release date, YYYY-MM-DD",
+                        "name": "released_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest release date, YYYY-MM-DD",
+                        "name": "released_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated ids of actors",
+                        "name": "actors",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "any",
+                        "description": "Whether movies should have any or all of the actors",
+                        "name": "actors_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated ids of genres, movies should have any of them",
+                        "name": "genres",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fragment of the movie name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimal runtime in minutes",
+                        "name": "min_runtime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximal runtime in minutes",
+                        "name": "max_runtime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated ISO 3166-1 alpha-2 codes of countries, movies should be produced in any of them",
+                        "name": "countries",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated ISO 639-1 codes of languages, movies should have any of them as the original or a spoken language",
+                        "name": "languages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated age ratings (0+, 6+, 12+, 16+, 18+), + may be omitted",
+                        "name": "age_ratings",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 code of the budget and box office currency, required for budget and box office filters",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimal budget",
+                        "name": "min_budget",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximal budget",
+                        "name": "max_budget",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimal box office",
+                        "name": "min_box_office",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximal box office",
+                        "name": "max_box_office",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size, 1 - 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include status of the movies in personal lists of the current user",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MovieOut"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of items"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/actor/{actor}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get movies from the System by a fragment of the actor's name or a similar name in Cyrillic or Latin, ordered by similarity. User should have the catalog:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movie"
+                ],
+                "summary": "Get movies from the System by actor.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the actor to be getted",
+                        "name": "actor",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.4,
+                        "description": "Minimal trigram similarity of names, (0, 1]",
+                        "name": "similarity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size, 1 - 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include status of the movies in personal lists of the current user",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MovieOut"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of items"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/name/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get movies from the System by a fragment of the name or a similar name in Cyrillic or Latin, ordered by similarity. User should have the catalog:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Movie"
+                ],
+                "summary": "Get movies from the System by name.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the movie to be getted",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.4,
+                        "description": "Minimal trigram similarity of names, (0, 1]",
+                        "name": "similarity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size, 1 - 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include status of the movies in personal lists of the current user",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MovieOut"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of items"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/people/{id}/movies": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get movies in which a person (an actor from /actor) took part, optionally only in the given role. User should have the catalog:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Actor"
+                ],
+                "summary": "Get filmography of a person from the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the person",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "actor",
+                            "director",
+                            "writer",
+                            "producer",
+                            "composer"
+                        ],
+                        "type": "string",
+                        "description": "Role of the person in the movies, any role if empty",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "release_date",
+                        "description": "Comma separated sort fields (id, name, release_date, rating), prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size, 1 - 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include status of the movies in personal lists of the current user",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MovieOut"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of items"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Person not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace rating and text of the review. Users can update only their own reviews.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Updates review.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the review to be updated",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rating and review",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Built-in admin has no personal data",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete the review together with its rating. Users can delete only their own reviews.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Deletes review.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the review to be deleted",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Built-in admin has no personal data",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get roles with their permissions from the System. User should have the users:manage permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get roles from the System.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Role"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Search movies by name, description and cast and actors by name with Russian and English stemming.\nHits are ordered by rank, highlights are HTML: the catalog text is HTML-escaped and matches are wrapped in \u003cb\u003e\u003c/b\u003e. User should have the catalog:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Search"
+                ],
+                "summary": "Full-text search of movies and actors.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query, supports quotes, OR and -",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Maximum number of hits of each type, 1 - 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get season from the System with its episodes ordered by number. User should have the catalog:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Get season from the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the season to be getted",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SeasonOut"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Season not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update season of the series, empty fields are left unchanged. User should have the catalog:write permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Updates season in the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the season to be updated",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Season data to be updated",
+                        "name": "season",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SeasonIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Season not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Season with the number already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete season from the System together with its episodes. User should have the catalog:write permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Deletes season from the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the season to be deleted",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Season not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/{id}/episodes": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add episode to the season and get it's ID. User should have the catalog:write permission. Numbers are unique within the season.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Adds episode to the season.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the season",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Episode to be added",
+                        "name": "episode",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EpisodeIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ID of the added episode",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Season not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Episode with the number already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Actor not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/series": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get series from the System without seasons. User should have the catalog:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Get series from the System.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "-rating",
+                        "description": "Comma separated sort fields (id, name, release_date, rating), prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size, 1 - 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SeriesOut"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of items"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add series to the System and get it's ID. User should have the catalog:write permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Adds series to the System.",
+                "parameters": [
+                    {
+                        "description": "Series to be added",
+                        "name": "series",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SeriesIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ID of the added series",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get series from the System with its seasons and episodes ordered by number. User should have the catalog:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Get series from the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the series to be getted",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SeriesOut"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Series not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update series in the System, empty fields are left unchanged. User should have the catalog:write permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Updates series in the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the series to be updated",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Series data to be updated",
+                        "name": "series",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SeriesIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Series not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete series from the System together with its seasons and episodes. User should have the catalog:write permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Deletes series from the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the series to be deleted",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Series not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/seasons": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add season to the series and get it's ID. User should have the catalog:write permission. Numbers are unique within the series.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Adds season to the series.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the series",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Season to be added",
+                        "name": "season",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SeasonIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ID of the added season",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Series not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Season with the number already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/suggest": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get id and name pairs of actors or movies, whose name or a word in the name starts with the prefix.\nName prefix matches go first. User should have the catalog:read permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Search"
+                ],
+                "summary": "Suggest actors or movies by prefix.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Beginning of the name",
+                        "name": "prefix",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "actor",
+                            "movie"
+                        ],
+                        "type": "string",
+                        "description": "Type of suggestions",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Maximum number of suggestions, 1 - 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Suggestion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get users from the System. User should have the users:manage permission. Passwords are never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get users from the System.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.UserOut"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add user to the System and get it's ID. User should have the users:manage permission and all permissions of the given roles.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Adds user to the System.",
+                "parameters": [
+                    {
+                        "description": "User to be added",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ID of the added user",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Nickname is already taken",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Role not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/lists/{list}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get movies of the watchlist, favorites or watched list of the authenticated user, most recently watched and added first. Movies contain their status in all personal lists.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "List"
+                ],
+                "summary": "Get movies of the personal list.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "watchlist",
+                            "favorites",
+                            "watched"
+                        ],
+                        "type": "string",
+                        "description": "Personal list",
+                        "name": "list",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size, 1 - 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MovieOut"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of items"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Built-in admin has no personal data",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/lists/{list}/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add movie to the watchlist, favorites or watched list of the authenticated user. For the watched list the date of watching can be set, today by default; adding the movie again updates the date.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "List"
+                ],
+                "summary": "Adds movie to the personal list.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "watchlist",
+                            "favorites",
+                            "watched"
+                        ],
+                        "type": "string",
+                        "description": "Personal list",
+                        "name": "list",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the movie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Date of watching",
+                        "name": "entry",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ListEntryIn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Built-in admin has no personal data",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove movie from the watchlist, favorites or watched list of the authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "List"
+                ],
+                "summary": "Removes movie from the personal list.",
+                "parameters": [
+                    {
+                        "enum": [
+                            "watchlist",
+                            "favorites",
+                            "watched"
+                        ],
+                        "type": "string",
+                        "description": "Personal list",
+                        "name": "list",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the movie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Built-in admin has no personal data",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie is not in the list",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/password": {
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change password of the authenticated user. The old password is required. All sessions of the user are revoked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Changes password of the current user.",
+                "parameters": [
+                    {
+                        "description": "Old and new passwords",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PasswordChange"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Old password is wrong",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get reviews of the authenticated user, most recently updated first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Get reviews of the current user.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size, 1 - 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ReviewOut"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total number of items"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Built-in admin has no personal data",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get user from the System. User should have the users:manage permission. Password is never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user from the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the user to be getted",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserOut"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete user and its sessions from the System. User should have the users:manage permission and all permissions of the roles of the user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Deletes user from the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the user to be deleted",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace roles of the user in the System. User should have the users:manage permission and all permissions of the given and current roles of the user. Deprecated is_admin flag is accepted instead of roles.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Updates user in the System.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the user to be updated",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User data to be updated",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "User is not authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Role not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.ActorHit": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Id - id актёра.",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name - имя актёра.",
+                    "type": "string"
+                },
+                "name_highlight": {
+                    "description": "NameHighlight - экранированное для HTML имя с выделенными тегами \u003cb\u003e\u003c/b\u003e совпадениями.",
+                    "type": "string"
+                },
+                "rank": {
+                    "description": "Rank - релевантность актёра запросу.",
+                    "type": "number"
+                }
+            }
+        },
+        "models.ActorIn": {
+            "type": "object",
+            "properties": {
+                "date_of_birth": {
+                    "description": "DateOfBirth - дата рождения актёра.",
+                    "type": "string"
+                },
+                "gender": {
+                    "description": "Gender - пол актёра.",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name - имя актёра.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ActorOut": {
+            "type": "object",
+            "properties": {
+                "date_of_birth": {
+                    "description": "DateOfBirth - дата рождения актёра.",
+                    "type": "string"
+                },
+                "gender": {
+                    "description": "Gender - пол актёра.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Id - id актёра.",
+                    "type": "integer"
+                },
+                "movies": {
+                    "description": "Movies - список id фильмов, в которых принимал участие актёр.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "name": {
+                    "description": "Name - имя актёра.",
+                    "type": "string"
+                },
+                "photo": {
+                    "description": "Photo - фотография актёра, если она загружена.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Image"
+                        }
+                    ]
+                },
+                "roles": {
+                    "description": "Roles - роли актёра в фильмах в порядке титров.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CastRole"
+                    }
+                }
+            }
+        },
+        "models.CastMember": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "description": "ActorId - id актёра.",
+                    "type": "integer"
+                },
+                "billing": {
+                    "description": "Billing - позиция в титрах, начиная с 1 (0 - после заданных позиций по порядку в списке).",
+                    "type": "integer"
+                },
+                "cameo": {
+                    "description": "Cameo - является ли роль камео.",
+                    "type": "boolean"
+                },
+                "character": {
+                    "description": "Character - имя персонажа.",
+                    "type": "string"
+                },
+                "voice": {
+                    "description": "Voice - является ли роль озвучкой.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CastRole": {
+            "type": "object",
+            "properties": {
+                "billing": {
+                    "description": "Billing - позиция в титрах фильма.",
+                    "type": "integer"
+                },
+                "cameo": {
+                    "description": "Cameo - является ли роль камео.",
+                    "type": "boolean"
+                },
+                "character": {
+                    "description": "Character - имя персонажа.",
+                    "type": "string"
+                },
+                "movie_id": {
+                    "description": "MovieId - id фильма.",
+                    "type": "integer"
+                },
+                "voice": {
+                    "description": "Voice - является ли роль озвучкой.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CollectionIn": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description - описание коллекции.",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "Kind - вид коллекции, по умолчанию подборка.",
+                    "type": "string"
+                },
+                "movies": {
+                    "description": "Movies - id фильмов коллекции по порядку, заменяет фильмы при обновлении.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "name": {
+                    "description": "Name - название коллекции.",
+                    "type": "string"
+                },
+                "public": {
+                    "description": "Public - видимость коллекции всем пользователям, по умолчанию скрыта.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CollectionMoviesIn": {
+            "type": "object",
+            "properties": {
+                "movies": {
+                    "description": "Movies - id фильмов коллекции в новом порядке.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.CollectionOut": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description - описание коллекции.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Id - id коллекции.",
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "Kind - вид коллекции: подборка или франшиза.",
+                    "type": "string"
+                },
+                "movies": {
+                    "description": "Movies - id фильмов коллекции по порядку.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "name": {
+                    "description": "Name - название коллекции.",
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "Owner - никнейм владельца коллекции.",
+                    "type": "string"
+                },
+                "public": {
+                    "description": "Public - видимость коллекции всем пользователям.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Credit": {
+            "type": "object",
+            "properties": {
+                "person_id": {
+                    "description": "PersonId - id человека.",
+                    "type": "integer"
+                },
+                "role": {
+                    "description": "Role - тип участия (actor, director, writer, producer или composer).",
+                    "type": "string"
+                }
+            }
+        },
+        "models.EpisodeIn": {
+            "type": "object",
+            "properties": {
+                "cast": {
+                    "description": "Cast - приглашённые актёры серии, заменяет актёров при обновлении.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CastMember"
+                    }
+                },
+                "description": {
+                    "description": "Description - необязательное описание серии.",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name - название серии.",
+                    "type": "string"
+                },
+                "number": {
+                    "description": "Number - номер серии в сезоне, начиная с 1.",
+                    "type": "integer"
+                },
+                "rating": {
+                    "description": "Rating - рэйтинг серии.",
+                    "type": "integer"
+                },
+                "release_date": {
+                    "description": "ReleaseDate - дата выхода серии.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.EpisodeOut": {
+            "type": "object",
+            "properties": {
+                "cast": {
+                    "description": "Cast - приглашённые актёры серии в порядке титров.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CastMember"
+                    }
+                },
+                "description": {
+                    "description": "Description - описание серии.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Id - id серии.",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name - название серии.",
+                    "type": "string"
+                },
+                "number": {
+                    "description": "Number - номер серии в сезоне.",
+                    "type": "integer"
+                },
+                "rating": {
+                    "description": "Rating - рэйтинг серии.",
+                    "type": "integer"
+                },
+                "release_date": {
+                    "description": "ReleaseDate - дата выхода серии.",
+                    "type": "string"
+                },
+                "season_id": {
+                    "description": "SeasonId - id сезона.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field - название поля в json.",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Message - описание ошибки.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.GenreIn": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Name - название жанра.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.GenreOut": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Id - id жанра.",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name - название жанра.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Image": {
+            "type": "object",
+            "properties": {
+                "thumbnail_url": {
+                    "description": "ThumbnailUrl - адрес миниатюры в формате JPEG.",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Url - адрес изображения.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ListEntryIn": {
+            "type": "object",
+            "properties": {
+                "watched_at": {
+                    "description": "WatchedAt - дата просмотра, только для списка просмотренных, по умолчанию сегодня.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.MovieHit": {
+            "type": "object",
+            "properties": {
+                "description_highlight": {
+                    "description": "DescriptionHighlight - экранированные для HTML фрагменты описания с выделенными совпадениями.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Id - id фильма.",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name - название фильма.",
+                    "type": "string"
+                },
+                "name_highlight": {
+                    "description": "NameHighlight - экранированное для HTML название с выделенными тегами \u003cb\u003e\u003c/b\u003e совпадениями.",
+                    "type": "string"
+                },
+                "rank": {
+                    "description": "Rank - релевантность фильма запросу.",
+                    "type": "number"
+                },
+                "rating": {
+                    "description": "Rating - рэйтинг фильма.",
+                    "type": "integer"
+                },
+                "release_date": {
+                    "description": "ReleaseDate - дата выпуска фильма.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.MovieIn": {
+            "type": "object",
+            "properties": {
+                "actors": {
+                    "description": "Actors - список id актёров, принимавших участие в фильме.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "age_rating": {
+                    "description": "AgeRating - возрастной рейтинг (0+, 6+, 12+, 16+ или 18+).",
+                    "type": "string"
+                },
+                "box_office": {
+                    "description": "BoxOffice - кассовые сборы фильма в валюте Currency.",
+                    "type": "integer"
+                },
+                "budget": {
+                    "description": "Budget - бюджет фильма в валюте Currency.",
+                    "type": "integer"
+                },
+                "cast": {
+                    "description": "Cast - роли актёров фильма, заменяет Actors.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CastMember"
+                    }
+                },
+                "countries": {
+                    "description": "Countries - страны производства (ISO 3166-1 alpha-2).",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "credits": {
+                    "description": "Credits - список участников фильма любых типов, дополняет Actors.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Credit"
+                    }
+                },
+                "currency": {
+                    "description": "Currency - валюта бюджета и сборов (ISO 4217).",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description - описание фильма.",
+                    "type": "string"
+                },
+                "genres": {
+                    "description": "Genres - список id жанров фильма.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "languages": {
+                    "description": "Languages - языки, на которых говорят в фильме (ISO 639-1).",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "Name - название фильма.",
+                    "type": "string"
+                },
+                "original_language": {
+                    "description": "OriginalLanguage - язык оригинала (ISO 639-1).",
+                    "type": "string"
+                },
+                "rating": {
+                    "description": "Rating - рэйтинг фильма.",
+                    "type": "integer"
+                },
+                "release_date": {
+                    "description": "ReleaseDate - дата выпуска фильма.",
+                    "type": "string"
+                },
+                "runtime": {
+                    "description": "Runtime - продолжительность фильма в минутах.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MovieOut": {
+            "type": "object",
+            "properties": {
+                "actors": {
+                    "description": "Actors - список id актёров, принимавших участие в фильме.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "age_rating": {
+                    "description": "AgeRating - возрастной рейтинг (0+, 6+, 12+, 16+ или 18+).",
+                    "type": "string"
+                },
+                "box_office": {
+                    "description": "BoxOffice - кассовые сборы фильма в валюте Currency.",
+                    "type": "integer"
+                },
+                "budget": {
+                    "description": "Budget - бюджет фильма в валюте Currency.",
+                    "type": "integer"
+                },
+                "cast": {
+                    "description": "Cast - роли актёров фильма в порядке титров.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CastMember"
+                    }
+                },
+                "countries": {
+                    "description": "Countries - страны производства (ISO 3166-1 alpha-2).",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "credits": {
+                    "description": "Credits - список участников фильма, включая актёров.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Credit"
+                    }
+                },
+                "currency": {
+                    "description": "Currency - валюта бюджета и сборов (ISO 4217).",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description - описание фильма.",
+                    "type": "string"
+                },
+                "genres": {
+                    "description": "Genres - список id жанров фильма.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "id": {
+                    "description": "Id - id фильма.",
+                    "type": "integer"
+                },
+                "languages": {
+                    "description": "Languages - языки, на которых говорят в фильме (ISO 639-1).",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "Name - название фильма.",
+                    "type": "string"
+                },
+                "original_language": {
+                    "description": "OriginalLanguage - язык оригинала (ISO 639-1).",
+                    "type": "string"
+                },
+                "poster": {
+                    "description": "Poster - постер фильма, если он загружен.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Image"
                         }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
+                    ]
+                },
+                "rating": {
+                    "description": "Rating - рэйтинг фильма.",
+                    "type": "integer"
+                },
+                "release_date": {
+                    "description": "ReleaseDate - дата выпуска фильма.",
+                    "type": "string"
+                },
+                "runtime": {
+                    "description": "Runtime - продолжительность фильма в минутах.",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status - положение фильма в списках текущего пользователя, если запрошено.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MovieStatus"
                         }
-                    }
+                    ]
+                },
+                "user_rating": {
+                    "description": "UserRating - средняя оценка фильма пользователями, null без оценок.",
+                    "type": "number"
+                },
+                "votes": {
+                    "description": "Votes - количество оценок фильма пользователями.",
+                    "type": "integer"
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.ActorIn": {
+        },
+        "models.MovieStatus": {
             "type": "object",
             "properties": {
-                "date_of_birth": {
-                    "description": "DateOfBirth - дата рождения актёра.",
+                "favorite": {
+                    "description": "Favorite - фильм в избранном.",
+                    "type": "boolean"
+                },
+                "watched": {
+                    "description": "Watched - фильм просмотрен.",
+                    "type": "boolean"
+                },
+                "watched_at": {
+                    "description": "WatchedAt - дата просмотра фильма.",
                     "type": "string"
                 },
-                "gender": {
-                    "description": "Gender - пол актёра.",
+                "watchlist": {
+                    "description": "Watchlist - фильм в списке к просмотру.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.PasswordChange": {
+            "type": "object",
+            "properties": {
+                "new_password": {
+                    "description": "NewPassword - новый пароль пользователя.",
                     "type": "string"
                 },
-                "name": {
-                    "description": "Name - имя актёра.",
+                "old_password": {
+                    "description": "OldPassword - текущий пароль пользователя.",
                     "type": "string"
                 }
             }
         },
-        "models.ActorOut": {
+        "models.Problem": {
             "type": "object",
             "properties": {
-                "date_of_birth": {
-                    "description": "DateOfBirth - дата рождения актёра.",
+                "detail": {
+                    "description": "Detail - описание ошибки для клиента.",
                     "type": "string"
                 },
-                "gender": {
-                    "description": "Gender - пол актёра.",
+                "errors": {
+                    "description": "Errors - ошибки валидации отдельных полей.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldError"
+                    }
+                },
+                "instance": {
+                    "description": "Instance - путь запроса, вызвавшего ошибку.",
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "RequestId - id запроса, по которому ошибку можно найти в логах.",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status - http-статус ответа.",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Title - краткое описание типа ошибки.",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type - URI типа ошибки.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.RefreshRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "description": "RefreshToken - токен обновления.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Registration": {
+            "type": "object",
+            "properties": {
+                "invite_code": {
+                    "description": "InviteCode - код приглашения, нужен, если регистрация только по приглашениям.",
+                    "type": "string"
+                },
+                "nickname": {
+                    "description": "Nickname - никнейм (логин) пользователя.",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Password - пароль пользователя.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReviewIn": {
+            "type": "object",
+            "properties": {
+                "rating": {
+                    "description": "Rating - оценка фильма.",
+                    "type": "integer"
+                },
+                "text": {
+                    "description": "Text - необязательный текст отзыва.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReviewOut": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "CreatedAt - время создания отзыва.",
                     "type": "string"
                 },
                 "id": {
-                    "description": "Id - id актёра.",
+                    "description": "Id - id отзыва.",
                     "type": "integer"
                 },
-                "movies": {
-                    "description": "Movies - список id фильмов, в которых принимал участие актёр.",
+                "movie_id": {
+                    "description": "MovieId - id фильма.",
+                    "type": "integer"
+                },
+                "nickname": {
+                    "description": "Nickname - никнейм автора отзыва.",
+                    "type": "string"
+                },
+                "rating": {
+                    "description": "Rating - оценка фильма автором.",
+                    "type": "integer"
+                },
+                "text": {
+                    "description": "Text - текст отзыва, пустой, если автор только оценил фильм.",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "UpdatedAt - время последнего изменения отзыва.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Role": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Name - название роли.",
+                    "type": "string"
+                },
+                "permissions": {
+                    "description": "Permissions - список прав роли.",
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.SearchResult": {
+            "type": "object",
+            "properties": {
+                "actors": {
+                    "description": "Actors - найденные актёры.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ActorHit"
                     }
                 },
+                "movies": {
+                    "description": "Movies - найденные фильмы.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MovieHit"
+                    }
+                }
+            }
+        },
+        "models.SeasonIn": {
+            "type": "object",
+            "properties": {
                 "name": {
-                    "description": "Name - имя актёра.",
+                    "description": "Name - необязательное название сезона.",
                     "type": "string"
+                },
+                "number": {
+                    "description": "Number - номер сезона в сериале, начиная с 1.",
+                    "type": "integer"
                 }
             }
         },
-        "models.MovieIn": {
+        "models.SeasonOut": {
             "type": "object",
             "properties": {
-                "actors": {
-                    "description": "Actors - список id актёров, принимавших участие в фильме.",
+                "episodes": {
+                    "description": "Episodes - серии сезона по номерам.",
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/models.EpisodeOut"
                     }
                 },
+                "id": {
+                    "description": "Id - id сезона.",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name - название сезона, может быть пустым.",
+                    "type": "string"
+                },
+                "number": {
+                    "description": "Number - номер сезона в сериале.",
+                    "type": "integer"
+                },
+                "series_id": {
+                    "description": "SeriesId - id сериала.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SeriesIn": {
+            "type": "object",
+            "properties": {
                 "description": {
-                    "description": "Description - описание фильма.",
+                    "description": "Description - описание сериала.",
                     "type": "string"
                 },
                 "name": {
-                    "description": "Name - название фильма.",
+                    "description": "Name - название сериала.",
                     "type": "string"
                 },
                 "rating": {
-                    "description": "Rating - рэйтинг фильма.",
+                    "description": "Rating - рэйтинг сериала.",
                     "type": "integer"
                 },
                 "release_date": {
-                    "description": "ReleaseDate - дата выпуска фильма.",
+                    "description": "ReleaseDate - дата премьеры сериала.",
                     "type": "string"
                 }
             }
         },
-        "models.MovieOut": {
+        "models.SeriesOut": {
             "type": "object",
             "properties": {
-                "actors": {
-                    "description": "Actors - список id актёров, принимавших участие в фильме.",
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
-                },
                 "description": {
-                    "description": "Description - описание фильма.",
+                    "description": "Description - описание сериала.",
                     "type": "string"
                 },
                 "id": {
-                    "description": "Id - id фильма.",
+                    "description": "Id - id сериала.",
                     "type": "integer"
                 },
                 "name": {
-                    "description": "Name - название фильма.",
+                    "description": "Name - название сериала.",
                     "type": "string"
                 },
                 "rating": {
-                    "description": "Rating - рэйтинг фильма.",
+                    "description": "Rating - рэйтинг сериала.",
                     "type": "integer"
                 },
                 "release_date": {
-                    "description": "ReleaseDate - дата выпуска фильма.",
+                    "description": "ReleaseDate - дата премьеры сериала.",
+                    "type": "string"
+                },
+                "seasons": {
+                    "description": "Seasons - сезоны сериала по номерам, только при получении одного сериала.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SeasonOut"
+                    }
+                }
+            }
+        },
+        "models.Suggestion": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Id - id актёра или фильма.",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name - имя актёра или название фильма.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Tokens": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "description": "AccessToken - токен доступа.",
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "ExpiresIn - время жизни токена доступа в секундах.",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "description": "RefreshToken - токен обновления.",
+                    "type": "string"
+                },
+                "token_type": {
+                    "description": "TokenType - тип токена (Bearer).",
                     "type": "string"
                 }
             }
@@ -767,7 +5719,7 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "is_admin": {
-                    "description": "IsAdmin - флаг, указывающий на то, является ли пользователь администратором.",
+                    "description": "IsAdmin - устаревший флаг администратора, используется, если роли не указаны.",
                     "type": "boolean"
                 },
                 "nickname": {
@@ -777,6 +5729,49 @@ const docTemplate = `{
                 "password": {
                     "description": "Password - пароль пользователя.",
                     "type": "string"
+                },
+                "roles": {
+                    "description": "Roles - список ролей пользователя.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.UserOut": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Id - id пользователя.",
+                    "type": "integer"
+                },
+                "nickname": {
+                    "description": "Nickname - никнейм (логин) пользователя.",
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "Roles - список ролей пользователя.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.UserPatch": {
+            "type": "object",
+            "properties": {
+                "is_admin": {
+                    "description": "IsAdmin - устаревший флаг администратора, используется, если роли не указаны.",
+                    "type": "boolean"
+                },
+                "roles": {
+                    "description": "Roles - новый список ролей пользователя.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         }
@@ -784,6 +5779,12 @@ const docTemplate = `{
     "securityDefinitions": {
         "BasicAuth": {
             "type": "basic"
+        },
+        "BearerAuth": {
+            "description": "Type \"Bearer\" followed by a space and an access token from /auth/login.",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -10,7 +10,9 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/auth"
 	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/storage"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -21,21 +23,30 @@ type App struct {
 	dbHandler postgres.DbHandler
 	addr      string
 	defAdmin  bool
+	signer    *auth.Signer
+	storage   storage.Storage
+
+	registration RegistrationPolicy
 }
 
 // CreateApp - создание приложения.
 //
-// Принимает: адрес, логгер информации, логгер ошибок, обработчик БД, указатель на существование базового администратора.
+// Принимает: адрес, логгер информации, логгер ошибок, обработчик БД, указатель на существование базового администратора,
+// подписчик токенов доступа, настройки самостоятельной регистрации, хранилище изображений (nil - загрузка изображений отключена).
 //
 // Возвращает: приложение.
 func CreateApp(addr string, infoLog *log.Logger, errorLog *log.Logger,
-	dbHandler postgres.DbHandler, defAdmin bool) *App {
+	dbHandler postgres.DbHandler, defAdmin bool, signer *auth.Signer, registration RegistrationPolicy, storage storage.Storage) *App {
 	return &App{
 		infoLog:   infoLog,
 		errorLog:  errorLog,
 		dbHandler: dbHandler,
 		addr:      addr,
 		defAdmin:  defAdmin,
+		signer:    signer,
+		storage:   storage,
+
+		registration: registration,
 	}
 }
 
@@ -3,21 +3,26 @@ package filmoteka
 import (
 	"log"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/auth"
 	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/storage"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestCreateApp(t *testing.T) {
 	t.Run("first case", func(t *testing.T) {
-		app := CreateApp("addr", nil, nil, nil, false)
+		app := CreateApp("addr", nil, nil, nil, false, nil, RegistrationPolicy{}, nil)
 		assert.NotNil(t, app)
 		assert.Equal(t, "addr", app.addr)
 		assert.False(t, app.defAdmin)
 		assert.Nil(t, app.dbHandler)
 		assert.Nil(t, app.errorLog)
 		assert.Nil(t, app.infoLog)
+		assert.Nil(t, app.signer)
+		assert.Nil(t, app.storage)
 	})
 
 	t.Run("second case", func(t *testing.T) {
@@ -32,13 +37,19 @@ func TestCreateApp(t *testing.T) {
 			errorLog     = log.Logger{}
 			dbHandler, _ = postgres.GetHandler(mockDB, false)
 			defAdmin     = true
+			signer       = auth.NewSigner([]byte("secret"), time.Minute, time.Hour)
+			registration = RegistrationPolicy{Mode: RegistrationOpen}
+			store, _     = storage.NewFS(t.TempDir(), "/media/")
 		)
-		app := CreateApp(addr, &infoLog, &errorLog, dbHandler, defAdmin)
+		app := CreateApp(addr, &infoLog, &errorLog, dbHandler, defAdmin, signer, registration, store)
 		assert.NotNil(t, app)
 		assert.Equal(t, addr, app.addr)
 		assert.Equal(t, &infoLog, app.infoLog)
 		assert.Equal(t, &errorLog, app.errorLog)
 		assert.Equal(t, dbHandler, app.dbHandler)
 		assert.True(t, app.defAdmin)
+		assert.Equal(t, signer, app.signer)
+		assert.Equal(t, registration, app.registration)
+		assert.Equal(t, store, app.storage)
 	})
 }
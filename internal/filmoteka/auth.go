@@ -0,0 +1,175 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/auth"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+)
+
+// Login - обрабатывает http запрос на вход пользователя в фильмотеку.
+//
+// @Summary      Logs user in.
+// @Description  Exchange nickname and password for a signed bearer access token and a refresh token.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        user body models.User true "User credentials"
+// @Success      200 {object} models.Tokens
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "Wrong credentials"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /auth/login [post]
+func (app *App) Login(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to log in")
+	if app.signer == nil {
+		app.handleError(w, r, errTokensDisabled, http.StatusNotFound)
+		return
+	}
+
+	var user models.User
+	d := json.NewDecoder(r.Body)
+	err := d.Decode(&user)
+	if err == nil {
+		err = user.CheckCredentials()
+	}
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	superuser, err := app.checkCredentials(user.Nickname, user.Password)
+	if err != nil {
+		app.handleError(w, r, err, authErrorStatus(err))
+		return
+	}
+
+	sId, err := auth.NewSessionId()
+	if err != nil {
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	session := models.Session{
+		Id:        sId,
+		Nickname:  user.Nickname,
+		Superuser: superuser,
+		ExpiresAt: time.Now().Add(app.signer.RefreshTTL()),
+	}
+	tokens, err := app.issueTokens(session.Id, true)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	if err = app.dbHandler.AddSession(session); err != nil {
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	app.sendJson(w, r, tokens)
+	app.infoLog.Printf("user %s is logged in\n", user.Nickname)
+}
+
+// Refresh - обрабатывает http запрос на обновление токена доступа.
+//
+// @Summary      Refreshes access token.
+// @Description  Exchange a refresh token for a new access token. The session must not be revoked.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        token body models.RefreshRequest true "Refresh token"
+// @Success      200 {object} models.Tokens
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "Invalid refresh token"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /auth/refresh [post]
+func (app *App) Refresh(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to refresh an access token")
+	if app.signer == nil {
+		app.handleError(w, r, errTokensDisabled, http.StatusNotFound)
+		return
+	}
+
+	var req models.RefreshRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	claims, err := app.signer.Parse(req.RefreshToken, auth.TypeRefresh)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusUnauthorized)
+		return
+	}
+	if _, err = app.dbHandler.GetSession(claims.SessionId); err != nil {
+		app.handleError(w, r, err, authErrorStatus(err))
+		return
+	}
+
+	tokens, err := app.issueTokens(claims.SessionId, false)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	app.sendJson(w, r, tokens)
+	app.infoLog.Println("access token is refreshed")
+}
+
+// Logout - обрабатывает http запрос на выход пользователя из фильмотеки.
+//
+// @Summary      Logs user out.
+// @Description  Revoke the session of the bearer token, so its access and refresh tokens stop working.
+// @Tags         Auth
+// @Produce      json
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      401 {object} models.Problem "Invalid access token"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /auth/logout [post]
+func (app *App) Logout(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to log out")
+	token, ok := bearerToken(r)
+	if !ok {
+		app.handleError(w, r, errNoBearerToken, http.StatusUnauthorized)
+		return
+	}
+	session, err := app.authSession(token)
+	if err != nil {
+		app.handleError(w, r, err, authErrorStatus(err))
+		return
+	}
+
+	if err = app.dbHandler.DeleteSession(session.Id); err != nil {
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("user %s is logged out\n", session.Nickname)
+}
+
+// issueTokens - выпуск токенов для сессии.
+//
+// Принимает: id сессии и флаг выпуска токена обновления.
+//
+// Возвращает: токены и ошибку.
+func (app *App) issueTokens(sessionId string, withRefresh bool) (models.Tokens, error) {
+	access, err := app.signer.IssueAccess(sessionId)
+	if err != nil {
+		return models.Tokens{}, err
+	}
+	tokens := models.Tokens{
+		AccessToken: access,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(app.signer.AccessTTL().Seconds()),
+	}
+	if withRefresh {
+		if tokens.RefreshToken, err = app.signer.IssueRefresh(sessionId); err != nil {
+			return models.Tokens{}, err
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,153 @@
+// Пакет auth реализует выпуск и проверку подписанных токенов доступа.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Типы токенов.
+const (
+	// TypeAccess - токен доступа.
+	TypeAccess = "access"
+	// TypeRefresh - токен обновления.
+	TypeRefresh = "refresh"
+)
+
+var (
+	// ErrMalformed - ошибка разбора токена.
+	ErrMalformed = errors.New("malformed token")
+	// ErrSignature - ошибка проверки подписи токена.
+	ErrSignature = errors.New("invalid token signature")
+	// ErrExpired - ошибка истечения срока действия токена.
+	ErrExpired = errors.New("token is expired")
+	// ErrType - ошибка несоответствия типа токена.
+	ErrType = errors.New("unexpected token type")
+)
+
+// Claims - структура, представляющая содержимое токена.
+type Claims struct {
+	SessionId string `json:"sid"` // SessionId - id сессии, к которой относится токен.
+	Type      string `json:"typ"` // Type - тип токена.
+	ExpiresAt int64  `json:"exp"` // ExpiresAt - время истечения токена (unix).
+}
+
+// Signer - структура, выпускающая и проверяющая токены.
+type Signer struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+// NewSigner - создание Signer.
+//
+// Принимает: секретный ключ, время жизни токена доступа и токена обновления.
+//
+// Возвращает: Signer.
+func NewSigner(secret []byte, accessTTL, refreshTTL time.Duration) *Signer {
+	return &Signer{
+		secret:     secret,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		now:        time.Now,
+	}
+}
+
+// AccessTTL - время жизни токена доступа.
+func (s *Signer) AccessTTL() time.Duration {
+	return s.accessTTL
+}
+
+// RefreshTTL - время жизни токена обновления.
+func (s *Signer) RefreshTTL() time.Duration {
+	return s.refreshTTL
+}
+
+// IssueAccess - выпуск токена доступа.
+//
+// Принимает: id сессии.
+//
+// Возвращает: токен и ошибку.
+func (s *Signer) IssueAccess(sessionId string) (string, error) {
+	return s.issue(Claims{SessionId: sessionId, Type: TypeAccess, ExpiresAt: s.now().Add(s.accessTTL).Unix()})
+}
+
+// IssueRefresh - выпуск токена обновления.
+//
+// Принимает: id сессии.
+//
+// Возвращает: токен и ошибку.
+func (s *Signer) IssueRefresh(sessionId string) (string, error) {
+	return s.issue(Claims{SessionId: sessionId, Type: TypeRefresh, ExpiresAt: s.now().Add(s.refreshTTL).Unix()})
+}
+
+// Parse - проверка токена и получение его содержимого.
+//
+// Принимает: токен и ожидаемый тип токена.
+//
+// Возвращает: содержимое токена и ошибку.
+func (s *Signer) Parse(token, typ string) (Claims, error) {
+	payload, sign, ok := strings.Cut(token, ".")
+	if !ok {
+		return Claims{}, ErrMalformed
+	}
+	gotSign, err := base64.RawURLEncoding.DecodeString(sign)
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+	if !hmac.Equal(gotSign, s.sign(payload)) {
+		return Claims{}, ErrSignature
+	}
+
+	js, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return Claims{}, ErrMalformed
+	}
+	var claims Claims
+	if err = json.Unmarshal(js, &claims); err != nil {
+		return Claims{}, errors.Join(ErrMalformed, err)
+	}
+	if claims.Type != typ {
+		return Claims{}, ErrType
+	}
+	if s.now().Unix() >= claims.ExpiresAt {
+		return Claims{}, ErrExpired
+	}
+	return claims, nil
+}
+
+// issue - выпуск токена с заданным содержимым.
+func (s *Signer) issue(claims Claims) (string, error) {
+	js, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(js)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.sign(payload)), nil
+}
+
+// sign - вычисление подписи HMAC-SHA256.
+func (s *Signer) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// NewSessionId - генерация случайного id сессии.
+//
+// Возвращает: id сессии и ошибку.
+func NewSessionId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner(t *testing.T) {
+	signer := NewSigner([]byte("secret"), time.Minute, time.Hour)
+
+	t.Run("access token", func(t *testing.T) {
+		token, err := signer.IssueAccess("sid")
+		assert.NoError(t, err)
+
+		claims, err := signer.Parse(token, TypeAccess)
+		assert.NoError(t, err)
+		assert.Equal(t, "sid", claims.SessionId)
+		assert.Equal(t, TypeAccess, claims.Type)
+	})
+
+	t.Run("refresh token", func(t *testing.T) {
+		token, err := signer.IssueRefresh("sid")
+		assert.NoError(t, err)
+
+		claims, err := signer.Parse(token, TypeRefresh)
+		assert.NoError(t, err)
+		assert.Equal(t, "sid", claims.SessionId)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		token, _ := signer.IssueRefresh("sid")
+		_, err := signer.Parse(token, TypeAccess)
+		assert.ErrorIs(t, err, ErrType)
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		token, _ := NewSigner([]byte("other"), time.Minute, time.Hour).IssueAccess("sid")
+		_, err := signer.Parse(token, TypeAccess)
+		assert.ErrorIs(t, err, ErrSignature)
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		token, _ := signer.IssueAccess("sid")
+		other, _ := signer.IssueAccess("other")
+		_, sign, _ := strings.Cut(token, ".")
+		payload, _, _ := strings.Cut(other, ".")
+		_, err := signer.Parse(payload+"."+sign, TypeAccess)
+		assert.ErrorIs(t, err, ErrSignature)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := signer.Parse("token", TypeAccess)
+		assert.ErrorIs(t, err, ErrMalformed)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expired := NewSigner([]byte("secret"), time.Minute, time.Hour)
+		expired.now = func() time.Time { return time.Now().Add(-time.Hour) }
+		token, _ := expired.IssueAccess("sid")
+		_, err := signer.Parse(token, TypeAccess)
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+}
+
+func TestNewSessionId(t *testing.T) {
+	first, err := NewSessionId()
+	assert.NoError(t, err)
+	second, err := NewSessionId()
+	assert.NoError(t, err)
+	assert.Len(t, first, 32)
+	assert.NotEqual(t, first, second)
+}
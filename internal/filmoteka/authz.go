@@ -0,0 +1,145 @@
+package filmoteka
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// principal - авторизованный пользователь запроса.
+type principal struct {
+	nickname    string   // nickname - никнейм пользователя.
+	superuser   bool     // superuser - флаг базового администратора, обладающего всеми правами.
+	permissions []string // permissions - права, выданные пользователю через его роли.
+}
+
+// can - проверка наличия права у пользователя.
+func (p principal) can(permission string) bool {
+	return p.superuser || slices.Contains(p.permissions, permission)
+}
+
+// ctxKey - тип ключей контекста запроса.
+type ctxKey int
+
+// Ключи контекста запроса.
+const (
+	// principalKey - ключ авторизованного пользователя.
+	principalKey ctxKey = iota
+	// requestIdKey - ключ id запроса.
+	requestIdKey
+)
+
+// authenticated - обёртка обработчика, пропускающая только авторизованных пользователей.
+//
+// Принимает: обработчик.
+//
+// Возвращает: обработчик, кладущий пользователя в контекст запроса.
+func (app *App) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := app.authUser(r)
+		if err != nil {
+			app.handleError(w, r, err, authErrorStatus(err))
+			return
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), principalKey, p)))
+	}
+}
+
+// require - обёртка обработчика, пропускающая только пользователей с указанным правом.
+//
+// Принимает: право и обработчик.
+//
+// Возвращает: обработчик.
+func (app *App) require(permission string, next http.HandlerFunc) http.HandlerFunc {
+	return app.authenticated(func(w http.ResponseWriter, r *http.Request) {
+		p := principalFrom(r)
+		if !p.can(permission) {
+			err := fmt.Errorf("%w %s: %s", errNoPermission, permission, p.nickname)
+			app.handleError(w, r, err, authErrorStatus(err))
+			return
+		}
+		next(w, r)
+	})
+}
+
+// personal - обёртка обработчика личных данных пользователя: отзывов, списков и коллекций.
+//
+// Базовый администратор не хранится в таблице пользователей, поэтому личных данных у него нет.
+//
+// Принимает: обработчик.
+//
+// Возвращает: обработчик, пропускающий только авторизованных пользователей из БД.
+func (app *App) personal(next http.HandlerFunc) http.HandlerFunc {
+	return app.authenticated(func(w http.ResponseWriter, r *http.Request) {
+		if principalFrom(r).superuser {
+			app.handleError(w, r, errNoProfile, authErrorStatus(errNoProfile))
+			return
+		}
+		next(w, r)
+	})
+}
+
+// checkGrantable - проверка, может ли пользователь запроса выдать роли.
+//
+// Роль можно выдать, только если все её права есть у самого пользователя, иначе администратор пользователей
+// мог бы выдать себе или другому роль admin. Несуществующие роли не проверяются - их отвергнет БД.
+//
+// Принимает: запрос и названия ролей.
+//
+// Возвращает: ошибку (errNoPermission, если роль выдать нельзя).
+func (app *App) checkGrantable(r *http.Request, roles []string) error {
+	return app.checkRoles(r, roles, "grant")
+}
+
+// checkManageable - проверка, может ли пользователь запроса изменить или удалить пользователя.
+//
+// Пользователя можно изменить или удалить, только если все права его текущих ролей есть у пользователя запроса,
+// иначе администратор пользователей мог бы снять роли с администратора или удалить его.
+//
+// Принимает: запрос и id пользователя.
+//
+// Возвращает: ошибку (errNoPermission, если пользователя изменить нельзя, ErrUnknownUser, если его нет).
+func (app *App) checkManageable(r *http.Request, id int) error {
+	if principalFrom(r).superuser {
+		return nil
+	}
+	user, err := app.dbHandler.GetUser(id)
+	if err != nil {
+		return err
+	}
+	return app.checkRoles(r, user.Roles, "manage user with")
+}
+
+// checkRoles - проверка наличия у пользователя запроса всех прав ролей.
+//
+// Принимает: запрос, названия ролей и действие с ними для текста ошибки.
+//
+// Возвращает: ошибку (errNoPermission, если какого-то права нет).
+func (app *App) checkRoles(r *http.Request, roles []string, action string) error {
+	p := principalFrom(r)
+	if p.superuser || len(roles) == 0 {
+		return nil
+	}
+	all, err := app.dbHandler.GetRoles()
+	if err != nil {
+		return err
+	}
+	for _, role := range all {
+		if !slices.Contains(roles, role.Name) {
+			continue
+		}
+		for _, permission := range role.Permissions {
+			if !p.can(permission) {
+				return fmt.Errorf("%w to %s role %s: %s", errNoPermission, action, role.Name, p.nickname)
+			}
+		}
+	}
+	return nil
+}
+
+// principalFrom - получение авторизованного пользователя из контекста запроса.
+func principalFrom(r *http.Request) principal {
+	p, _ := r.Context().Value(principalKey).(principal)
+	return p
+}
@@ -0,0 +1,258 @@
+package filmoteka
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestRequire(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+	dbHandler, _ := postgres.GetHandler(mockDB, false)
+	app := CreateApp(":8080", logger, logger, dbHandler, true, nil, RegistrationPolicy{}, nil)
+
+	var got principal
+	handler := app.require(models.PermCatalogWrite, func(w http.ResponseWriter, r *http.Request) {
+		got = principalFrom(r)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("superuser", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth("admin", "admin")
+		w := httptest.NewRecorder()
+		handler(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "admin", got.nickname)
+	})
+
+	t.Run("not authenticated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+		handler(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("unknown user and wrong password are indistinguishable", func(t *testing.T) {
+		hash, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+		for _, nick := range []string{"viewer", "nobody"} {
+			rows := sqlmock.NewRows([]string{"id", "nickname", "password", "is_admin"})
+			if nick == "viewer" {
+				rows.AddRow(1, "viewer", string(hash), false)
+			}
+			mock.ExpectQuery("SELECT").WithArgs(nick).WillReturnRows(rows)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.SetBasicAuth(nick, "wrong")
+			w := httptest.NewRecorder()
+			handler(w, req)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Contains(t, w.Body.String(), `"detail":"invalid credentials"`)
+		}
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		hash, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth("viewer", "password")
+		w := httptest.NewRecorder()
+		mock.ExpectQuery("SELECT").WithArgs("viewer").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "nickname", "password", "is_admin"}).AddRow(1, "viewer", string(hash), false))
+		mock.ExpectQuery("SELECT").WithArgs("viewer").WillReturnRows(
+			sqlmock.NewRows([]string{"permission"}).AddRow(models.PermCatalogRead))
+		got = principal{}
+		handler(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, got.nickname)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPrincipalCan(t *testing.T) {
+	viewer := principal{nickname: "viewer", permissions: []string{models.PermCatalogRead}}
+	assert.True(t, viewer.can(models.PermCatalogRead))
+	assert.False(t, viewer.can(models.PermCatalogWrite))
+
+	superuser := principal{nickname: "admin", superuser: true}
+	assert.True(t, superuser.can(models.PermUsersManage))
+}
+
+func TestGrantRoles(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+	dbHandler, _ := postgres.GetHandler(mockDB, false)
+	app := CreateApp(":8080", logger, logger, dbHandler, false, nil, RegistrationPolicy{}, nil)
+
+	userAdmin := principal{nickname: "useradmin", permissions: []string{models.PermUsersManage}}
+	as := func(p principal, r *http.Request) *http.Request {
+		return r.WithContext(context.WithValue(r.Context(), principalKey, p))
+	}
+	expectRoles := func() {
+		mock.ExpectQuery("FROM roles").WillReturnRows(sqlmock.NewRows([]string{"name", "permissions"}).
+			AddRow(models.RoleAdmin, "{catalog:read,catalog:write,users:manage}").
+			AddRow(models.RoleUserAdmin, "{users:manage}").
+			AddRow(models.RoleViewer, "{}"))
+	}
+
+	t.Run("user admin grants admin to a new user", func(t *testing.T) {
+		expectRoles()
+
+		w := httptest.NewRecorder()
+		body := `{"nickname": "intruder", "password": "password1", "roles": ["admin"]}`
+		app.AddUser(w, as(userAdmin, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "to grant role admin")
+	})
+
+	t.Run("user admin grants admin to itself", func(t *testing.T) {
+		expectRoles()
+
+		w := httptest.NewRecorder()
+		r := as(userAdmin, httptest.NewRequest(http.MethodPatch, "/users/2", strings.NewReader(`{"roles": ["user_admin", "admin"]}`)))
+		r.SetPathValue("id", "2")
+		app.UpdateUser(w, r)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	expectUser := func(id int, roles string) {
+		mock.ExpectQuery("FROM users u").WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "nickname", "roles"}).AddRow(id, "user", roles))
+	}
+
+	t.Run("user admin grants its own role", func(t *testing.T) {
+		expectRoles()
+		expectUser(3, "{viewer}")
+		expectRoles()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE users").WithArgs(3, false).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM user_roles").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO user_roles").WithArgs(3, models.RoleUserAdmin).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		w := httptest.NewRecorder()
+		r := as(userAdmin, httptest.NewRequest(http.MethodPatch, "/users/3", strings.NewReader(`{"roles": ["user_admin"]}`)))
+		r.SetPathValue("id", "3")
+		app.UpdateUser(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("user admin demotes admin", func(t *testing.T) {
+		expectRoles()
+		expectUser(4, "{admin}")
+		expectRoles()
+
+		w := httptest.NewRecorder()
+		r := as(userAdmin, httptest.NewRequest(http.MethodPatch, "/users/4", strings.NewReader(`{"roles": ["viewer"]}`)))
+		r.SetPathValue("id", "4")
+		app.UpdateUser(w, r)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "to manage user with role admin")
+	})
+
+	t.Run("user admin deletes admin", func(t *testing.T) {
+		expectUser(4, "{admin}")
+		expectRoles()
+
+		w := httptest.NewRecorder()
+		r := as(userAdmin, httptest.NewRequest(http.MethodDelete, "/users/4", nil))
+		r.SetPathValue("id", "4")
+		app.DeleteUser(w, r)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "to manage user with role admin")
+	})
+
+	t.Run("user admin deletes unknown user", func(t *testing.T) {
+		mock.ExpectQuery("FROM users u").WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"id", "nickname", "roles"}))
+
+		w := httptest.NewRecorder()
+		r := as(userAdmin, httptest.NewRequest(http.MethodDelete, "/users/5", nil))
+		r.SetPathValue("id", "5")
+		app.DeleteUser(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("user admin deletes viewer", func(t *testing.T) {
+		expectUser(3, "{viewer}")
+		expectRoles()
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM sessions").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM users").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		w := httptest.NewRecorder()
+		r := as(userAdmin, httptest.NewRequest(http.MethodDelete, "/users/3", nil))
+		r.SetPathValue("id", "3")
+		app.DeleteUser(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("superuser grants admin", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE users").WithArgs(3, true).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM user_roles").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO user_roles").WithArgs(3, models.RoleAdmin).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		w := httptest.NewRecorder()
+		r := as(principal{nickname: "admin", superuser: true}, httptest.NewRequest(http.MethodPatch, "/users/3", strings.NewReader(`{"roles": ["admin"]}`)))
+		r.SetPathValue("id", "3")
+		app.UpdateUser(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPersonal(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+	dbHandler, _ := postgres.GetHandler(mockDB, false)
+	app := CreateApp(":8080", logger, logger, dbHandler, true, nil, RegistrationPolicy{}, nil)
+	handler := app.routes()
+
+	for _, target := range []struct{ method, path string }{
+		{http.MethodPost, "/movie/1/reviews"},
+		{http.MethodPut, "/reviews/1"},
+		{http.MethodDelete, "/reviews/1"},
+		{http.MethodGet, "/users/me/reviews"},
+		{http.MethodGet, "/users/me/lists/watchlist"},
+		{http.MethodPut, "/users/me/lists/watchlist/1"},
+		{http.MethodDelete, "/users/me/lists/watchlist/1"},
+		{http.MethodPost, "/collections"},
+	} {
+		t.Run("built-in admin "+target.method+" "+target.path, func(t *testing.T) {
+			r := httptest.NewRequest(target.method, target.path, strings.NewReader(`{}`))
+			r.SetBasicAuth("admin", "admin")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Contains(t, w.Body.String(), "built-in admin has no reviews, lists or collections")
+		})
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
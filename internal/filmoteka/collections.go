@@ -0,0 +1,255 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+)
+
+// collectionAccess - получение пользователя запроса для доступа к коллекциям.
+//
+// Пользователи с правом catalog:write модерируют коллекции: видят и изменяют чужие, в том числе скрытые.
+func collectionAccess(r *http.Request) models.CollectionAccess {
+	p := principalFrom(r)
+	return models.CollectionAccess{Nickname: p.nickname, Moderator: p.can(models.PermCatalogWrite)}
+}
+
+// AddCollection - обрабатывает http запрос на добавление коллекции фильмов.
+//
+// @Summary      Adds collection to the System.
+// @Description  Add collection or franchise owned by the authenticated user and get it's ID. Movies keep the given order.
+// @Tags         Collection
+// @Accept       json
+// @Produce      json
+// @Param        collection body models.CollectionIn true "Collection to be added"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {integer} int "ID of the added collection"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied or built-in admin"
+// @Failure      404 {object} models.Problem "User not found"
+// @Failure      422 {object} models.Problem "Movie not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /collections [post]
+func (app *App) AddCollection(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to add a new collection")
+	var collection models.CollectionIn
+	d := json.NewDecoder(r.Body)
+	err := d.Decode(&collection)
+	if err == nil {
+		err = collection.Check()
+	}
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := app.dbHandler.AddCollection(principalFrom(r).nickname, collection)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendJson(w, r, id)
+	app.infoLog.Printf("collection %d is added\n", id)
+}
+
+// UpdateCollection - обрабатывает http запрос на обновление коллекции фильмов.
+//
+// @Summary      Updates collection in the System.
+// @Description  Update name, description, kind, visibility and movies of the collection. Only the given fields are changed. Only the owner or a user with the catalog:write permission can update the collection.
+// @Tags         Collection
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID of the collection to be updated"
+// @Param        collection body models.CollectionIn true "Collection data to be updated"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Collection not found"
+// @Failure      422 {object} models.Problem "Movie not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /collections/{id} [put]
+func (app *App) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to update a collection")
+	var collection models.CollectionIn
+	d := json.NewDecoder(r.Body)
+	err := d.Decode(&collection)
+	if err == nil {
+		err = collection.CheckPatch()
+	}
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.dbHandler.UpdateCollection(collectionAccess(r), id, collection); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("collection %d is updated\n", id)
+}
+
+// SetCollectionMovies - обрабатывает http запрос на изменение фильмов коллекции и их порядка.
+//
+// @Summary      Reorders movies of collection.
+// @Description  Replace movies of the collection with the given ordered list. Only the owner or a user with the catalog:write permission can change the collection.
+// @Tags         Collection
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID of the collection"
+// @Param        movies body models.CollectionMoviesIn true "Movies in the new order"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Collection not found"
+// @Failure      422 {object} models.Problem "Movie not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /collections/{id}/movies [put]
+func (app *App) SetCollectionMovies(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to set movies of a collection")
+	var movies models.CollectionMoviesIn
+	d := json.NewDecoder(r.Body)
+	err := d.Decode(&movies)
+	if err == nil {
+		err = movies.Check()
+	}
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.dbHandler.SetCollectionMovies(collectionAccess(r), id, movies.Movies); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("movies of collection %d are set\n", id)
+}
+
+// DeleteCollection - обрабатывает http запрос на удаление коллекции фильмов.
+//
+// @Summary      Deletes collection from the System.
+// @Description  Delete collection from the System, movies are kept. Only the owner or a user with the catalog:write permission can delete the collection.
+// @Tags         Collection
+// @Produce      json
+// @Param        id path int true "ID of the collection to be deleted"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Collection not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /collections/{id} [delete]
+func (app *App) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to delete a collection")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.dbHandler.DeleteCollection(collectionAccess(r), id); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("collection %d is deleted\n", id)
+}
+
+// GetCollection - обрабатывает http запрос на получение коллекции фильмов.
+//
+// @Summary      Get collection from the System.
+// @Description  Get public collection, or private collection of the authenticated user, with its movies in order. Users with the catalog:write permission can get any collection.
+// @Tags         Collection
+// @Produce      json
+// @Param        id path int true "ID of the collection to be getted"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {object} models.CollectionOut
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Collection not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /collections/{id} [get]
+func (app *App) GetCollection(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get a collection")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	collection, err := app.dbHandler.GetCollection(collectionAccess(r), id)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendJson(w, r, collection)
+	app.infoLog.Printf("collection %d is getted\n", id)
+}
+
+// GetCollections - обрабатывает http запрос на получение списка коллекций фильмов.
+//
+// @Summary      Get collections from the System.
+// @Description  Get public collections and private collections of the authenticated user ordered by name. Users with the catalog:write permission get all collections.
+// @Tags         Collection
+// @Produce      json
+// @Param        limit query int false "Page size, 1 - 500" default(50)
+// @Param        offset query int false "Number of items to skip" default(0)
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {array} models.CollectionOut
+// @Header       200 {integer} X-Total-Count "Total number of items"
+// @Header       200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /collections [get]
+func (app *App) GetCollections(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get collections")
+	page, err := parsePage(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	collections, total, err := app.dbHandler.GetCollections(collectionAccess(r), page)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendPage(w, r, page, total, collections)
+	app.infoLog.Println("collections are getted")
+}
@@ -0,0 +1,121 @@
+package filmoteka
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollections(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+	dbHandler, _ := postgres.GetHandler(mockDB, false)
+	app := CreateApp(":8080", logger, logger, dbHandler, false, nil, RegistrationPolicy{}, nil)
+
+	as := func(r *http.Request, p principal) *http.Request {
+		return r.WithContext(context.WithValue(r.Context(), principalKey, p))
+	}
+	viewer := principal{nickname: "user", permissions: []string{models.PermCatalogRead}}
+	editor := principal{nickname: "editor", permissions: []string{models.PermCatalogRead, models.PermCatalogWrite}}
+
+	t.Run("add collection", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO collections").WithArgs("Marvel", "", "franchise", true, "user").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectExec("INSERT INTO collection_movies").WithArgs(2, 7, 1).
+			WillReturnError(&pq.Error{Code: "23503", Constraint: "collection_movies_movie_id_fkey"})
+		mock.ExpectRollback()
+
+		w := httptest.NewRecorder()
+		body := `{"name": "Marvel", "kind": "franchise", "public": true, "movies": [7]}`
+		app.AddCollection(w, as(httptest.NewRequest(http.MethodPost, "/collections", strings.NewReader(body)), viewer))
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "movie 7 does not exist")
+	})
+
+	t.Run("add invalid collection", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		app.AddCollection(w, as(httptest.NewRequest(http.MethodPost, "/collections", strings.NewReader(`{"name": ""}`)), viewer))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"name"`)
+	})
+
+	t.Run("update description of collection", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE collections").WithArgs(2, "user", false, "", "Фильмы студии", "", nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		w := httptest.NewRecorder()
+		r := as(httptest.NewRequest(http.MethodPut, "/collections/2", strings.NewReader(`{"description": "Фильмы студии"}`)), viewer)
+		r.SetPathValue("id", "2")
+		app.UpdateCollection(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("update collection with blank name", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := as(httptest.NewRequest(http.MethodPut, "/collections/2", strings.NewReader(`{"name": " "}`)), viewer)
+		r.SetPathValue("id", "2")
+		app.UpdateCollection(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"name"`)
+	})
+
+	t.Run("reorder collection of another user as editor", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE collections SET updated_at").WithArgs(2, "editor", true).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM collection_movies").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO collection_movies").WithArgs(2, 3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO collection_movies").WithArgs(2, 7, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		w := httptest.NewRecorder()
+		r := as(httptest.NewRequest(http.MethodPut, "/collections/2/movies", strings.NewReader(`{"movies": [3, 7]}`)), editor)
+		r.SetPathValue("id", "2")
+		app.SetCollectionMovies(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("delete collection of another user", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM collections").WithArgs(2, "user", false).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		w := httptest.NewRecorder()
+		r := as(httptest.NewRequest(http.MethodDelete, "/collections/2", nil), viewer)
+		r.SetPathValue("id", "2")
+		app.DeleteCollection(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "unknown collection")
+	})
+
+	t.Run("get collection", func(t *testing.T) {
+		mock.ExpectQuery("FROM collections c").WithArgs(2, "user", false).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "kind", "public", "owner"}).AddRow(2, "Marvel", "", "franchise", true, "editor"))
+		mock.ExpectQuery("SELECT movie_id FROM collection_movies").WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"movie_id"}).AddRow(7).AddRow(3))
+
+		w := httptest.NewRecorder()
+		r := as(httptest.NewRequest(http.MethodGet, "/collections/2", nil), viewer)
+		r.SetPathValue("id", "2")
+		app.GetCollection(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id": 2, "name": "Marvel", "description": "", "kind": "franchise", "public": true, "owner": "editor", "movies": [7, 3]}`, w.Body.String())
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,77 @@
+package filmoteka
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+)
+
+// GetFilmography - обрабатывает http запрос на получение фильмографии человека из фильмотеки.
+//
+// @Summary      Get filmography of a person from the System.
+// @Description  Get movies in which a person (an actor from /actor) took part, optionally only in the given role. User should have the catalog:read permission.
+// @Tags         Actor
+// @Produce      json
+// @Param        id path int true "ID of the person"
+// @Param        role query string false "Role of the person in the movies, any role if empty" Enums(actor, director, writer, producer, composer)
+// @Param        sort query string false "Comma separated sort fields (id, name, release_date, rating), prefixed with - for descending order" default(release_date)
+// @Param        limit query int false "Page size, 1 - 500" default(50)
+// @Param        offset query int false "Number of items to skip" default(0)
+// @Param        status query bool false "Include status of the movies in personal lists of the current user"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {array} models.MovieOut
+// @Header       200 {integer} X-Total-Count "Total number of items"
+// @Header       200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Person not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /people/{id}/movies [get]
+func (app *App) GetFilmography(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get filmography")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+	role := r.URL.Query().Get("role")
+	if role != "" && !models.IsCreditRole(role) {
+		app.handleError(w, r, &models.FieldError{Field: "role", Message: "role must be one of actor, director, writer, producer, composer"}, http.StatusBadRequest)
+		return
+	}
+	sort, err := parseSort(r, models.MovieSortFields, models.DefaultFilmographySort)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+	withStatus, err := parseWithStatus(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	movies, total, err := app.dbHandler.GetFilmography(id, role, sort, page)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	if withStatus {
+		if err := app.dbHandler.FillMovieStatuses(principalFrom(r).nickname, movies); err != nil {
+			app.handleError(w, r, err, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	app.fillPosters(movies)
+	app.sendPage(w, r, page, total, movies)
+	app.infoLog.Printf("filmography of person %d is getted\n", id)
+}
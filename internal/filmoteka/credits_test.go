@@ -0,0 +1,61 @@
+package filmoteka
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFilmography(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+	dbHandler, _ := postgres.GetHandler(mockDB, false)
+	app := CreateApp(":8080", logger, logger, dbHandler, false, nil, RegistrationPolicy{}, nil)
+
+	filmography := func(id, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/people/"+id+"/movies"+query, nil)
+		r.SetPathValue("id", id)
+		app.GetFilmography(w, r)
+		return w
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("v ON true WHERE id IN").WithArgs(3, "composer", 50, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).
+				AddRow(2, "name", "description", time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC), 8))
+		mock.ExpectQuery("SELECT movie_id, actor_id, credit").WithArgs("{2}").
+			WillReturnRows(sqlmock.NewRows([]string{"movie_id", "actor_id", "credit"}).AddRow(2, 1, "actor").AddRow(2, 3, "composer"))
+		mock.ExpectQuery("SELECT movie_id, genre_id").WithArgs("{2}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "genre_id"}))
+
+		w := filmography("3", "?role=composer")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", w.Header().Get("X-Total-Count"))
+		assert.Contains(t, w.Body.String(), `"actors":[1]`)
+		assert.Contains(t, w.Body.String(), `"credits":[{"person_id":1,"role":"actor"},{"person_id":3,"role":"composer"}]`)
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		w := filmography("3", "?role=cameraman")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"role"`)
+	})
+
+	t.Run("wrong id", func(t *testing.T) {
+		w := filmography("three", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
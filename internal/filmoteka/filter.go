@@ -0,0 +1,133 @@
+package filmoteka
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+)
+
+// dateLayout - формат дат в query параметрах.
+const dateLayout = time.DateOnly
+
+// parseMovieFilter - получение фильтра фильмов из query параметров запроса.
+//
+// Принимает: запрос.
+//
+// Возвращает: фильтр и ошибку.
+func parseMovieFilter(r *http.Request) (models.MovieFilter, error) {
+	query := r.URL.Query()
+	filter := models.MovieFilter{
+		ActorsMatch: query.Get("actors_match"),
+		Name:        query.Get("name"),
+		Currency:    query.Get("currency"),
+	}
+	errs := make([]error, 0)
+	parseInt := func(field string) *int {
+		s := query.Get(field)
+		if s == "" {
+			return nil
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			errs = append(errs, &models.FieldError{Field: field, Message: field + " must be an integer"})
+			return nil
+		}
+		return &v
+	}
+	parseDate := func(field string) *time.Time {
+		s := query.Get(field)
+		if s == "" {
+			return nil
+		}
+		v, err := time.Parse(dateLayout, s)
+		if err != nil {
+			errs = append(errs, &models.FieldError{Field: field, Message: field + " must be a date in YYYY-MM-DD format"})
+			return nil
+		}
+		return &v
+	}
+
+	parseInt64 := func(field string) *int64 {
+		s := query.Get(field)
+		if s == "" {
+			return nil
+		}
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			errs = append(errs, &models.FieldError{Field: field, Message: field + " must be an integer"})
+			return nil
+		}
+		return &v
+	}
+	parseCodes := func(field string) []string {
+		var codes []string
+		for _, list := range query[field] {
+			for _, s := range strings.Split(list, ",") {
+				codes = append(codes, strings.TrimSpace(s))
+			}
+		}
+		return codes
+	}
+
+	parseIds := func(field string) []int {
+		var ids []int
+		for _, list := range query[field] {
+			for _, s := range strings.Split(list, ",") {
+				id, err := strconv.Atoi(strings.TrimSpace(s))
+				if err != nil {
+					errs = append(errs, &models.FieldError{Field: field, Message: field + " must be a comma separated list of ids"})
+					return nil
+				}
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+
+	filter.MinRating = parseInt("min_rating")
+	filter.MaxRating = parseInt("max_rating")
+	filter.ReleasedAfter = parseDate("released_after")
+	filter.ReleasedBefore = parseDate("released_before")
+	filter.Actors = parseIds("actors")
+	filter.Genres = parseIds("genres")
+	filter.MinRuntime = parseInt("min_runtime")
+	filter.MaxRuntime = parseInt("max_runtime")
+	filter.Countries = parseCodes("countries")
+	filter.Languages = parseCodes("languages")
+	filter.AgeRatings = parseCodes("age_ratings")
+	// Незакодированный + в query превращается в пробел, поэтому рейтинг можно передать и без него: 12 означает 12+.
+	for i, a := range filter.AgeRatings {
+		if !strings.HasSuffix(a, "+") {
+			filter.AgeRatings[i] = a + "+"
+		}
+	}
+	filter.MinBudget = parseInt64("min_budget")
+	filter.MaxBudget = parseInt64("max_budget")
+	filter.MinBoxOffice = parseInt64("min_box_office")
+	filter.MaxBoxOffice = parseInt64("max_box_office")
+	if len(errs) != 0 {
+		return models.MovieFilter{}, errors.Join(errs...)
+	}
+
+	if err := filter.Check(); err != nil {
+		return models.MovieFilter{}, err
+	}
+	return filter, nil
+}
+
+// parseSort - получение сортировки из query параметра sort запроса.
+//
+// Принимает: запрос, допустимые поля сортировки и сортировку по умолчанию.
+//
+// Возвращает: сортировку и ошибку.
+func parseSort(r *http.Request, fields []string, def models.Sort) (models.Sort, error) {
+	s := r.URL.Query().Get("sort")
+	if s == "" {
+		return def, nil
+	}
+	return models.ParseSort(s, fields)
+}
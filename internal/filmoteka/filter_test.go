@@ -0,0 +1,114 @@
+package filmoteka
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMovieFilter(t *testing.T) {
+	t.Run("no filters", func(t *testing.T) {
+		filter, err := parseMovieFilter(httptest.NewRequest(http.MethodGet, "/movies", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, models.MovieFilter{}, filter)
+	})
+
+	t.Run("all filters", func(t *testing.T) {
+		target := "/movies?min_rating=5&max_rating=9&released_after=2000-01-01&released_before=2010-12-31" +
+			"&actors=1,2&actors=3&actors_match=all&genres=4,5&name=matrix"
+		filter, err := parseMovieFilter(httptest.NewRequest(http.MethodGet, target, nil))
+		assert.NoError(t, err)
+		minRating, maxRating := 5, 9
+		after, before := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2010, 12, 31, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, models.MovieFilter{
+			MinRating:      &minRating,
+			MaxRating:      &maxRating,
+			ReleasedAfter:  &after,
+			ReleasedBefore: &before,
+			Actors:         []int{1, 2, 3},
+			ActorsMatch:    models.ActorsMatchAll,
+			Genres:         []int{4, 5},
+			Name:           "matrix",
+		}, filter)
+	})
+
+	t.Run("malformed values", func(t *testing.T) {
+		target := "/movies?min_rating=high&released_before=31.12.2010&actors=1,a&genres=drama"
+		_, err := parseMovieFilter(httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, []models.FieldError{
+			{Field: "min_rating", Message: "min_rating must be an integer"},
+			{Field: "released_before", Message: "released_before must be a date in YYYY-MM-DD format"},
+			{Field: "actors", Message: "actors must be a comma separated list of ids"},
+			{Field: "genres", Message: "genres must be a comma separated list of ids"},
+		}, models.FieldErrors(err))
+	})
+
+	t.Run("invalid ranges", func(t *testing.T) {
+		target := "/movies?min_rating=9&max_rating=5&actors_match=some"
+		_, err := parseMovieFilter(httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, []models.FieldError{
+			{Field: "min_rating", Message: "min rating must not be greater than max rating"},
+			{Field: "actors_match", Message: "actors_match must be any or all"},
+		}, models.FieldErrors(err))
+	})
+
+	t.Run("metadata filters", func(t *testing.T) {
+		target := "/movies?min_runtime=90&max_runtime=180&countries=RU,SU&languages=ru&age_ratings=12%2B,16" +
+			"&currency=RUB&min_budget=1000&max_box_office=5000000000"
+		filter, err := parseMovieFilter(httptest.NewRequest(http.MethodGet, target, nil))
+		assert.NoError(t, err)
+		minRuntime, maxRuntime := 90, 180
+		minBudget, maxBoxOffice := int64(1000), int64(5000000000)
+		assert.Equal(t, models.MovieFilter{
+			MinRuntime:   &minRuntime,
+			MaxRuntime:   &maxRuntime,
+			Countries:    []string{"RU", "SU"},
+			Languages:    []string{"ru"},
+			AgeRatings:   []string{"12+", "16+"},
+			Currency:     "RUB",
+			MinBudget:    &minBudget,
+			MaxBoxOffice: &maxBoxOffice,
+		}, filter)
+	})
+
+	t.Run("invalid metadata filters", func(t *testing.T) {
+		target := "/movies?countries=rus&languages=russian&age_ratings=21&max_budget=1000"
+		_, err := parseMovieFilter(httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, []models.FieldError{
+			{Field: "countries", Message: "countries must be ISO 3166-1 alpha-2 codes, e.g. RU"},
+			{Field: "languages", Message: "languages must be ISO 639-1 codes, e.g. ru"},
+			{Field: "age_ratings", Message: "age ratings must be 0+, 6+, 12+, 16+ or 18+"},
+			{Field: "currency", Message: "currency must be set together with budget or box office filters"},
+		}, models.FieldErrors(err))
+	})
+}
+
+func TestParseSort(t *testing.T) {
+	t.Run("default sort", func(t *testing.T) {
+		sort, err := parseSort(httptest.NewRequest(http.MethodGet, "/movies", nil), models.MovieSortFields, models.DefaultMovieSort)
+		assert.NoError(t, err)
+		assert.Equal(t, models.DefaultMovieSort, sort)
+	})
+
+	t.Run("multiple keys", func(t *testing.T) {
+		sort, err := parseSort(httptest.NewRequest(http.MethodGet, "/movies?sort=-rating,name,release_date", nil), models.MovieSortFields, models.DefaultMovieSort)
+		assert.NoError(t, err)
+		assert.Equal(t, models.Sort{{Field: "rating", Desc: true}, {Field: "name"}, {Field: "release_date"}}, sort)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		logger := log.New(io.Discard, "test", log.LstdFlags)
+		app := CreateApp(":8080", logger, logger, nil, false, nil, RegistrationPolicy{}, nil)
+		w := httptest.NewRecorder()
+
+		app.GetActors(w, httptest.NewRequest(http.MethodGet, "/actors?sort=rating", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"sort"`)
+	})
+}
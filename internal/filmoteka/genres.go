@@ -0,0 +1,188 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+)
+
+// AddGenre - обрабатывает http запрос на добавление жанра в фильмотеку.
+//
+// @Summary      Adds genre to the System.
+// @Description  Add genre to the System and get it's ID. User should have the catalog:write permission. Genre names are unique.
+// @Tags         Genre
+// @Accept       json
+// @Produce      json
+// @Param        genre body models.GenreIn true "Genre to be added"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {integer} int "ID of the added genre"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      409 {object} models.Problem "Genre already exists"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /genres [post]
+func (app *App) AddGenre(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to add a new genre")
+	var genre models.GenreIn
+	d := json.NewDecoder(r.Body)
+	err := d.Decode(&genre)
+	if err == nil {
+		err = genre.Check()
+	}
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := app.dbHandler.AddGenre(genre)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendJson(w, r, id)
+	app.infoLog.Printf("genre %d is added\n", id)
+}
+
+// UpdateGenre - обрабатывает http запрос на переименование жанра в фильмотеке.
+//
+// @Summary      Updates genre in the System.
+// @Description  Rename genre in the System. User should have the catalog:write permission.
+// @Tags         Genre
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID of the genre to be updated"
+// @Param        genre body models.GenreIn true "Genre data to be updated"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Genre not found"
+// @Failure      409 {object} models.Problem "Genre already exists"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /genres/{id} [put]
+func (app *App) UpdateGenre(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to update a genre")
+	var genre models.GenreIn
+	d := json.NewDecoder(r.Body)
+	err := d.Decode(&genre)
+	if err == nil {
+		err = genre.Check()
+	}
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.dbHandler.UpdateGenre(id, genre); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("genre %d is updated\n", id)
+}
+
+// DeleteGenre - обрабатывает http запрос на удаление жанра из фильмотеки.
+//
+// @Summary      Deletes genre from the System.
+// @Description  Delete genre from the System, movies lose the genre. User should have the catalog:write permission.
+// @Tags         Genre
+// @Produce      json
+// @Param        id path int true "ID of the genre to be deleted"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Genre not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /genres/{id} [delete]
+func (app *App) DeleteGenre(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to delete a genre")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.dbHandler.DeleteGenre(id); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("genre %d is deleted\n", id)
+}
+
+// GetGenre - обрабатывает http запрос на получение жанра из фильмотеки.
+//
+// @Summary      Get genre from the System.
+// @Description  Get genre from the System. User should have the catalog:read permission.
+// @Tags         Genre
+// @Produce      json
+// @Param        id path int true "ID of the genre to be getted"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {object} models.GenreOut
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Genre not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /genres/{id} [get]
+func (app *App) GetGenre(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get a genre")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	genre, err := app.dbHandler.GetGenre(id)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendJson(w, r, genre)
+	app.infoLog.Printf("genre %d is getted\n", id)
+}
+
+// GetGenres - обрабатывает http запрос на получение списка жанров из фильмотеки.
+//
+// @Summary      Get genres from the System.
+// @Description  Get all genres from the System ordered by name. User should have the catalog:read permission.
+// @Tags         Genre
+// @Produce      json
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {array} models.GenreOut
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /genres [get]
+func (app *App) GetGenres(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get genres")
+	genres, err := app.dbHandler.GetGenres()
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendJson(w, r, genres)
+	app.infoLog.Println("genres are getted")
+}
@@ -0,0 +1,80 @@
+package filmoteka
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenres(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+	dbHandler, _ := postgres.GetHandler(mockDB, false)
+	app := CreateApp(":8080", logger, logger, dbHandler, false, nil, RegistrationPolicy{}, nil)
+
+	addGenre := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		app.AddGenre(w, httptest.NewRequest(http.MethodPost, "/genres", strings.NewReader(body)))
+		return w
+	}
+
+	t.Run("add genre", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO genres").WithArgs("Драма").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectCommit()
+
+		w := addGenre(`{"name": "Драма"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "3", w.Body.String())
+	})
+
+	t.Run("add existing genre", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO genres").WithArgs("Драма").WillReturnError(&pq.Error{Code: "23505"})
+		mock.ExpectRollback()
+
+		w := addGenre(`{"name": "Драма"}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "genre already exists")
+	})
+
+	t.Run("add invalid genre", func(t *testing.T) {
+		w := addGenre(`{"name": ""}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"name"`)
+	})
+
+	t.Run("get genres", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id, name FROM genres").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "Драма").AddRow(1, "Комедия"))
+
+		w := httptest.NewRecorder()
+		app.GetGenres(w, httptest.NewRequest(http.MethodGet, "/genres", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"id": 3, "name": "Драма"}, {"id": 1, "name": "Комедия"}]`, w.Body.String())
+	})
+
+	t.Run("get unknown genre", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id, name FROM genres").WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/genres/7", nil)
+		r.SetPathValue("id", "7")
+		app.GetGenre(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -44,7 +44,7 @@ func TestGraceRun(t *testing.T) {
 	t.Run("testing graceful shutdown of the server", func(t *testing.T) {
 		assert.Equal(t, 1, 1)
 		logger := log.New(io.Discard, "test", log.LstdFlags)
-		app := filmoteka.CreateApp(":8080", logger, logger, nil, false)
+		app := filmoteka.CreateApp(":8080", logger, logger, nil, false, nil, filmoteka.RegistrationPolicy{}, nil)
 		srvr := &testServer{}
 
 		go func() {
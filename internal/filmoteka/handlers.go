@@ -2,10 +2,12 @@ package filmoteka
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -13,91 +15,81 @@ import (
 // AddActor - обрабатывает http запрос на добавление актёра в фильмотеку.
 //
 // @Summary      Adds actor to the System.
-// @Description  Add actor to the System and get it's ID. User should be an admin. All fields are required.
+// @Description  Add actor to the System and get it's ID. User should have the catalog:write permission. All fields are required.
 // @Tags         Actor
 // @Accept       json
 // @Produce      json
 // @Param        actor body models.ActorIn true "Actor to be added"
 // @Security BasicAuth
+// @Security BearerAuth
 // @Success      200 {integer} int "ID of the added actor"
-// @Failure      400 {string} string "Bad request"
-// @Failure      403 {string} string "User not an admin"
-// @Failure      500 {string} string "Internal server error"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      500 {object} models.Problem "Internal server error"
 // @Router       /actor [post]
 func (app *App) AddActor(w http.ResponseWriter, r *http.Request) {
 	app.infoLog.Println("trying to add a new actor")
-	isAdmin, err := app.authIsAdmin(r)
-	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusForbidden)
-		return
-	}
-	if !isAdmin {
-		handleError(app.errorLog, w, "user trying to add a new actor not an admin", http.StatusForbidden)
-		return
-	}
-
 	var actor models.ActorIn
 	d := json.NewDecoder(r.Body)
-	if err = d.Decode(&actor); err == nil {
+	err := d.Decode(&actor)
+	if err == nil {
 		err = actor.Check()
 	}
 	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusBadRequest)
+		app.handleError(w, r, err, http.StatusBadRequest)
 		return
 	}
 
 	id, err := app.dbHandler.AddActor(actor)
 	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusInternalServerError)
+		app.handleError(w, r, err, dbErrorStatus(err))
 		return
 	}
 
-	app.sendJson(w, id)
+	app.sendJson(w, r, id)
 	app.infoLog.Printf("actor %d is added\n", id)
 }
 
 // UpdateActor - обрабатывает http запрос на обновление актёра в фильмотеке.
 //
 // @Summary      Updates actor in the System.
-// @Description  Update actor in the System. User should be an admin. All fields are not required.
+// @Description  Update actor in the System. User should have the catalog:write permission. All fields are not required.
 // @Tags         Actor
 // @Accept       json
 // @Produce      json
 // @Param        id path int true "ID of the actor to be updated"
 // @Param        actor body models.ActorIn true "Actor data to be updated"
 // @Security BasicAuth
+// @Security BearerAuth
 // @Success      200 {string} string "OK"
-// @Failure      400 {string} string "Bad request"
-// @Failure      403 {string} string "User not an admin"
-// @Failure      500 {string} string "Internal server error"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Actor not found"
+// @Failure      500 {object} models.Problem "Internal server error"
 // @Router       /actor/{id} [put]
 func (app *App) UpdateActor(w http.ResponseWriter, r *http.Request) {
 	app.infoLog.Println("trying to update an actor")
-	isAdmin, err := app.authIsAdmin(r)
-	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusForbidden)
-		return
-	}
-	if !isAdmin {
-		handleError(app.errorLog, w, "user trying to update an actor not an admin", http.StatusForbidden)
-		return
-	}
-
 	var actor models.ActorIn
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&actor); err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusBadRequest)
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+	if actor.IsZero() {
+		app.handleError(w, r, errNothingToUpdate, http.StatusBadRequest)
 		return
 	}
 
 	id, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		handleError(app.errorLog, w, "id must be an integer", http.StatusBadRequest)
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
 		return
 	}
 
 	if err := app.dbHandler.UpdateActor(id, actor); err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusInternalServerError)
+		app.handleError(w, r, err, dbErrorStatus(err))
 		return
 	}
 
@@ -108,37 +100,33 @@ func (app *App) UpdateActor(w http.ResponseWriter, r *http.Request) {
 // DeleteActor - обрабатывает http запрос на удаление актёра из фильмотеки.
 //
 // @Summary      Deletes actor from the System.
-// @Description  Delete actor from the System. User should be an admin.
+// @Description  Delete actor from the System together with the photo. User should have the catalog:write permission.
 // @Tags         Actor
 // @Produce      json
 // @Param        id path int true "ID of the actor to be deleted"
 // @Security BasicAuth
+// @Security BearerAuth
 // @Success      200 {string} string "OK"
-// @Failure      400 {string} string "Bad request"
-// @Failure      403 {string} string "User not an admin"
-// @Failure      500 {string} string "Internal server error"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Actor not found"
+// @Failure      500 {object} models.Problem "Internal server error"
 // @Router       /actor/{id} [delete]
 func (app *App) DeleteActor(w http.ResponseWriter, r *http.Request) {
 	app.infoLog.Println("trying to delete an actor")
-	isAdmin, err := app.authIsAdmin(r)
-	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusForbidden)
-		return
-	}
-	if !isAdmin {
-		handleError(app.errorLog, w, "user trying to delete an actor not an admin", http.StatusForbidden)
-		return
-	}
 	id, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		handleError(app.errorLog, w, "id must be an integer", http.StatusBadRequest)
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
 		return
 	}
 
-	if err := app.dbHandler.DeleteActor(id); err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusInternalServerError)
+	key, err := app.dbHandler.DeleteActor(id)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
 		return
 	}
+	app.removeImage(key)
 
 	w.WriteHeader(http.StatusOK)
 	app.infoLog.Printf("actor %d is deleted\n", id)
@@ -147,148 +135,162 @@ func (app *App) DeleteActor(w http.ResponseWriter, r *http.Request) {
 // GetActor - обрабатывает http запрос на получение актёра из фильмотеки.
 //
 // @Summary      Get actor from the System.
-// @Description  Get actor from the System.
+// @Description  Get actor from the System. User should have the catalog:read permission.
 // @Tags         Actor
 // @Produce      json
 // @Param        id path int true "ID of the actor to be getted"
 // @Security BasicAuth
+// @Security BearerAuth
 // @Success      200 {object} models.ActorOut
-// @Failure      400 {string} string "Bad request"
-// @Failure      500 {string} string "Internal server error"
-// @Failure      403 {string} string "User does not exist"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      404 {object} models.Problem "Actor not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
 // @Router       /actor/{id} [get]
 func (app *App) GetActor(w http.ResponseWriter, r *http.Request) {
 	app.infoLog.Println("trying to get an actor")
-	_, err := app.authIsAdmin(r)
-	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusForbidden)
-		return
-	}
-
 	id, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		handleError(app.errorLog, w, "id must be an integer", http.StatusBadRequest)
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
 		return
 	}
 	actor, err := app.dbHandler.GetActor(id)
 	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusInternalServerError)
+		app.handleError(w, r, err, dbErrorStatus(err))
 		return
 	}
 
-	app.sendJson(w, actor)
+	actor.Photo = app.imageOf(actor.PhotoKey)
+	app.sendJson(w, r, actor)
 	app.infoLog.Printf("actor %d is getted\n", id)
 }
 
 // GetActors - обрабатывает http запрос на получение списка актёров из фильмотеки.
 //
 // @Summary      Get actors from the System.
-// @Description  Get actors from the System.
+// @Description  Get actors from the System, optionally searching by a case-insensitive fragment of the name or a similar name in Cyrillic or Latin, ordered by similarity. User should have the catalog:read permission.
 // @Tags         Actor
 // @Produce      json
+// @Param        sort query string false "Comma separated sort fields (id, name, gender, date_of_birth), prefixed with - for descending order, ignored when searching by name" default(id)
+// @Param        name query string false "Fragment of the actor's name, Cyrillic or Latin"
+// @Param        similarity query number false "Minimal trigram similarity of names, (0, 1]" default(0.4)
+// @Param        limit query int false "Page size, 1 - 500" default(50)
+// @Param        offset query int false "Number of items to skip" default(0)
 // @Security BasicAuth
+// @Security BearerAuth
 // @Success      200 {array} models.ActorOut
-// @Failure      400 {string} string "Bad request"
-// @Failure      500 {string} string "Internal server error"
-// @Failure      403 {string} string "User does not exist"
+// @Header       200 {integer} X-Total-Count "Total number of items"
+// @Header       200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
 // @Router       /actors [get]
 func (app *App) GetActors(w http.ResponseWriter, r *http.Request) {
 	app.infoLog.Println("trying to get list of actors")
-	if _, err := app.authIsAdmin(r); err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusForbidden)
+	sort, err := parseSort(r, models.ActorSortFields, models.DefaultActorSort)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
 		return
 	}
-
-	actors, err := app.dbHandler.GetActors()
+	page, err := parsePage(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+	var actors []models.ActorOut
+	var total int
+	if name := r.URL.Query().Get("name"); name != "" {
+		var search models.NameSearch
+		if search, err = parseNameSearch(r, name); err != nil {
+			app.handleError(w, r, err, http.StatusBadRequest)
+			return
+		}
+		actors, total, err = app.dbHandler.GetActorsByName(search, page)
+	} else {
+		actors, total, err = app.dbHandler.GetActors(sort, page)
+	}
 	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusInternalServerError)
+		app.handleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	app.sendJson(w, actors)
+	app.fillPhotos(actors)
+	app.sendPage(w, r, page, total, actors)
 	app.infoLog.Println("list of actors is getted")
 }
 
 // AddMovie - обрабатывает http запрос на добавление фильма в фильмотеку.
 //
 // @Summary      Adds movie to the System.
-// @Description  Add movie to the System and get it's ID. User should be an admin.
+// @Description  Add movie to the System and get it's ID. User should have the catalog:write permission.
 // @Tags         Movie
 // @Accept       json
 // @Produce      json
 // @Param        movie body models.MovieIn true "Movie to be added"
 // @Security BasicAuth
+// @Security BearerAuth
 // @Success      200 {integer} int "ID of the added movie"
-// @Failure      400 {string} string "Bad request"
-// @Failure      403 {string} string "User not an admin"
-// @Failure      500 {string} string "Internal server error"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      422 {object} models.Problem "Actor not found"
+// @Failure      500 {object} models.Problem "Internal server error"
 // @Router       /movie [post]
 func (app *App) AddMovie(w http.ResponseWriter, r *http.Request) {
 	app.infoLog.Println("trying to add a new movie")
-	isAdmin, err := app.authIsAdmin(r)
-	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusForbidden)
-		return
-	}
-	if !isAdmin {
-		handleError(app.errorLog, w, "user trying to add a new movie not an admin", http.StatusForbidden)
-		return
-	}
-
 	var movie models.MovieIn
 	d := json.NewDecoder(r.Body)
-	if err = d.Decode(&movie); err == nil {
+	err := d.Decode(&movie)
+	if err == nil {
 		err = movie.Check()
 	}
 	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusBadRequest)
+		app.handleError(w, r, err, http.StatusBadRequest)
 		return
 	}
 
 	id, err := app.dbHandler.AddMovie(movie)
 	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusInternalServerError)
+		app.handleError(w, r, err, dbErrorStatus(err))
 		return
 	}
 
-	app.sendJson(w, id)
+	app.sendJson(w, r, id)
 	app.infoLog.Printf("movie %d is added\n", id)
 }
 
 // DeleteMovie - обрабатывает http запрос на удаление фильма из фильмотеки.
 //
 // @Summary      Deletes movie from the System.
-// @Description  Delete movie from the System. User should be an admin.
+// @Description  Delete movie from the System together with the poster. User should have the catalog:write permission.
 // @Tags         Movie
 // @Produce      json
 // @Param        id path int true "ID of the movie to be deleted"
 // @Security BasicAuth
+// @Security BearerAuth
 // @Success      200 {string} string "OK"
-// @Failure      400 {string} string "Bad request"
-// @Failure      403 {string} string "User not an admin"
-// @Failure      500 {string} string "Internal server error"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Movie not found"
+// @Failure      500 {object} models.Problem "Internal server error"
 // @Router       /movie/{id} [delete]
 func (app *App) DeleteMovie(w http.ResponseWriter, r *http.Request) {
 	app.infoLog.Println("trying to delete a movie")
-	isAdmin, err := app.authIsAdmin(r)
-	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusForbidden)
-		return
-	}
-	if !isAdmin {
-		handleError(app.errorLog, w, "user trying to delete a movie not an admin", http.StatusForbidden)
-		return
-	}
 	id, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		handleError(app.errorLog, w, "id must be an integer", http.StatusBadRequest)
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
 		return
 	}
 
-	if err := app.dbHandler.DeleteMovie(id); err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusInternalServerError)
+	key, err := app.dbHandler.DeleteMovie(id)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
 		return
 	}
+	app.removeImage(key)
 
 	w.WriteHeader(http.StatusOK)
 	app.infoLog.Printf("movie %d is deleted\n", id)
@@ -297,58 +299,48 @@ func (app *App) DeleteMovie(w http.ResponseWriter, r *http.Request) {
 // UpdateMovie - обрабатывает http запрос на обновление фильма в фильмотеке.
 //
 // @Summary      Updates movie in the System.
-// @Description  Update movie in the System. User should be an admin.
+// @Description  Update movie in the System. User should have the catalog:write permission. Credits replace all people of the movie, actors alone replace only its actors.
 // @Tags         Movie
 // @Accept       json
 // @Produce      json
 // @Param        id path int true "ID of the movie to be updated"
 // @Param        movie body models.MovieIn true "Movie data to be updated"
 // @Security BasicAuth
+// @Security BearerAuth
 // @Success      200 {string} string "OK"
-// @Failure      400 {string} string "Bad request"
-// @Failure      403 {string} string "User not an admin"
-// @Failure      500 {string} string "Internal server error"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Movie not found"
+// @Failure      422 {object} models.Problem "Actor not found"
+// @Failure      500 {object} models.Problem "Internal server error"
 // @Router       /movie/{id} [put]
 func (app *App) UpdateMovie(w http.ResponseWriter, r *http.Request) {
 	app.infoLog.Println("trying to update a movie")
-	isAdmin, err := app.authIsAdmin(r)
-	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusForbidden)
-		return
-	}
-	if !isAdmin {
-		handleError(app.errorLog, w, "user trying to update a movie not an admin", http.StatusForbidden)
-		return
-	}
-
 	var movie models.MovieIn
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&movie); err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusBadRequest)
+		app.handleError(w, r, err, http.StatusBadRequest)
 		return
 	}
 
-	if len(movie.Name) > 150 {
-		handleError(app.errorLog, w, "movie name must be less than 150 chars", http.StatusBadRequest)
-		return
-	}
-	if len(movie.Description) > 150 {
-		handleError(app.errorLog, w, "movie description must be less than 1000 chars", http.StatusBadRequest)
+	if movie.IsZero() {
+		app.handleError(w, r, errNothingToUpdate, http.StatusBadRequest)
 		return
 	}
-	if movie.Rating != nil && *movie.Rating > 10 || *movie.Rating < 0 {
-		handleError(app.errorLog, w, "movie rating must be in range 0 - 10", http.StatusBadRequest)
+	if err := movie.CheckPatch(); err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
 		return
 	}
 
 	id, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
-		handleError(app.errorLog, w, "id must be an integer", http.StatusBadRequest)
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
 		return
 	}
 
 	if err := app.dbHandler.UpdateMovie(id, movie); err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusInternalServerError)
+		app.handleError(w, r, err, dbErrorStatus(err))
 		return
 	}
 
@@ -356,160 +348,522 @@ func (app *App) UpdateMovie(w http.ResponseWriter, r *http.Request) {
 	app.infoLog.Printf("movie %d is updated\n", id)
 }
 
+// GetMovie - обрабатывает http запрос на получение фильма из фильмотеки.
+//
+// @Summary      Get movie from the System.
+// @Description  Get movie from the System. User should have the catalog:read permission.
+// @Tags         Movie
+// @Produce      json
+// @Param        id path int true "ID of the movie to be getted"
+// @Param        status query bool false "Include status of the movies in personal lists of the current user"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {object} models.MovieOut
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Movie not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /movie/{id} [get]
+func (app *App) GetMovie(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get a movie")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+	withStatus, err := parseWithStatus(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+	movie, err := app.dbHandler.GetMovie(id)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+	if withStatus {
+		movies := []models.MovieOut{movie}
+		if err := app.dbHandler.FillMovieStatuses(principalFrom(r).nickname, movies); err != nil {
+			app.handleError(w, r, err, http.StatusInternalServerError)
+			return
+		}
+		movie = movies[0]
+	}
+
+	movie.Poster = app.imageOf(movie.PosterKey)
+	app.sendJson(w, r, movie)
+	app.infoLog.Printf("movie %d is getted\n", id)
+}
+
 // GetMovies - обрабатывает http запрос на получение списка фильмов из фильмотеки.
 //
 // @Summary      Get movies from the System.
-// @Description  Get movies from the System.
+// @Description  Get movies from the System, filtered by rating, release date, actors, genres, name, runtime, countries, languages, age rating, budget and box office. All filters are combined. User should have the catalog:read permission.
 // @Tags         Movie
 // @Produce      json
-// @Param        sort query string false "Sort movies by name, release date or rating"
+// @Param        sort query string false "Comma separated sort fields (id, name, release_date, rating), prefixed with - for descending order" default(-rating)
+// @Param        min_rating query int false "Minimal rating, 0 - 10"
+// @Param        max_rating query int false "Maximal rating, 0 - 10"
+// @Param        released_after query string false "Earliest release date, YYYY-MM-DD"
+// @Param        released_before query string false "Latest release date, YYYY-MM-DD"
+// @Param        actors query string false "Comma separated ids of actors"
+// @Param        actors_match query string false "Whether movies should have any or all of the actors" Enums(any, all) default(any)
+// @Param        genres query string false "Comma separated ids of genres, movies should have any of them"
+// @Param        name query string false "Fragment of the movie name"
+// @Param        min_runtime query int false "Minimal runtime in minutes"
+// @Param        max_runtime query int false "Maximal runtime in minutes"
+// @Param        countries query string false "Comma separated ISO 3166-1 alpha-2 codes of countries, movies should be produced in any of them"
+// @Param        languages query string false "Comma separated ISO 639-1 codes of languages, movies should have any of them as the original or a spoken language"
+// @Param        age_ratings query string false "Comma separated age ratings (0+, 6+, 12+, 16+, 18+), + may be omitted"
+// @Param        currency query string false "ISO 4217 code of the budget and box office currency, required for budget and box office filters"
+// @Param        min_budget query int false "Minimal budget"
+// @Param        max_budget query int false "Maximal budget"
+// @Param        min_box_office query int false "Minimal box office"
+// @Param        max_box_office query int false "Maximal box office"
+// @Param        limit query int false "Page size, 1 - 500" default(50)
+// @Param        offset query int false "Number of items to skip" default(0)
+// @Param        status query bool false "Include status of the movies in personal lists of the current user"
 // @Security BasicAuth
+// @Security BearerAuth
 // @Success      200 {array} models.MovieOut
-// @Failure      500 {string} string "Internal server error"
-// @Failure      403 {string} string "User does not exist"
+// @Header       200 {integer} X-Total-Count "Total number of items"
+// @Header       200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
 // @Router       /movies [get]
 func (app *App) GetMovies(w http.ResponseWriter, r *http.Request) {
 	app.infoLog.Println("trying to get list of movies")
-	if _, err := app.authIsAdmin(r); err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusForbidden)
+	sort, err := parseSort(r, models.MovieSortFields, models.DefaultMovieSort)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
 		return
 	}
-
-	var sortBy int
-	sorts, ok := r.URL.Query()["sort"]
-	if !ok {
-		sortBy = models.SortByRating
-	} else {
-		switch sorts[0] {
-		case "name":
-			sortBy = models.SortByName
-		case "release":
-			sortBy = models.SortByReleaseDate
-		case "rating":
-			fallthrough
-		default:
-			sortBy = models.SortByRating
-		}
+	page, err := parsePage(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
 	}
-	movies, err := app.dbHandler.GetMovies(sortBy)
+	withStatus, err := parseWithStatus(r)
 	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusInternalServerError)
+		app.handleError(w, r, err, http.StatusBadRequest)
 		return
 	}
+	filter, err := parseMovieFilter(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+	movies, total, err := app.dbHandler.GetMovies(sort, filter, page)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	if withStatus {
+		if err := app.dbHandler.FillMovieStatuses(principalFrom(r).nickname, movies); err != nil {
+			app.handleError(w, r, err, http.StatusInternalServerError)
+			return
+		}
+	}
 
-	app.sendJson(w, movies)
+	app.fillPosters(movies)
+	app.sendPage(w, r, page, total, movies)
 	app.infoLog.Println("list of movies is getted")
 }
 
 // GetMoviesByName - обрабатывает http запрос на получение списка фильмов из фильмотеки по имени.
 //
 // @Summary      Get movies from the System by name.
-// @Description  Get movies from the System by name.
+// @Description  Get movies from the System by a fragment of the name or a similar name in Cyrillic or Latin, ordered by similarity. User should have the catalog:read permission.
 // @Tags         Movie
 // @Produce      json
 // @Param        name path string true "Name of the movie to be getted"
+// @Param        similarity query number false "Minimal trigram similarity of names, (0, 1]" default(0.4)
+// @Param        limit query int false "Page size, 1 - 500" default(50)
+// @Param        offset query int false "Number of items to skip" default(0)
+// @Param        status query bool false "Include status of the movies in personal lists of the current user"
 // @Security BasicAuth
+// @Security BearerAuth
 // @Success      200 {array} models.MovieOut
-// @Failure      500 {string} string "Internal server error"
-// @Failure      403 {string} string "User does not exist"
+// @Header       200 {integer} X-Total-Count "Total number of items"
+// @Header       200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
 // @Router       /movies/name/{name} [get]
 func (app *App) GetMoviesByName(w http.ResponseWriter, r *http.Request) {
 	app.infoLog.Println("trying to get list of movies by searching the name")
-	if _, err := app.authIsAdmin(r); err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusForbidden)
+	name := r.PathValue("name")
+	page, err := parsePage(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
 		return
 	}
-
-	name := r.PathValue("name")
-	movies, err := app.dbHandler.GetMoviesByName(name)
+	withStatus, err := parseWithStatus(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+	search, err := parseNameSearch(r, name)
 	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusInternalServerError)
+		app.handleError(w, r, err, http.StatusBadRequest)
 		return
 	}
+	movies, total, err := app.dbHandler.GetMoviesByName(search, page)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	if withStatus {
+		if err := app.dbHandler.FillMovieStatuses(principalFrom(r).nickname, movies); err != nil {
+			app.handleError(w, r, err, http.StatusInternalServerError)
+			return
+		}
+	}
 
-	app.sendJson(w, movies)
+	app.fillPosters(movies)
+	app.sendPage(w, r, page, total, movies)
 	app.infoLog.Println("list of movies is getted by searching the name")
 }
 
 // GetMoviesByActor - обрабатывает http запрос на получение списка фильмов из фильмотеки по актёру.
 //
 // @Summary      Get movies from the System by actor.
-// @Description  Get movies from the System by actor.
+// @Description  Get movies from the System by a fragment of the actor's name or a similar name in Cyrillic or Latin, ordered by similarity. User should have the catalog:read permission.
 // @Tags         Movie
 // @Produce      json
 // @Param        actor path string true "Name of the actor to be getted"
+// @Param        similarity query number false "Minimal trigram similarity of names, (0, 1]" default(0.4)
+// @Param        limit query int false "Page size, 1 - 500" default(50)
+// @Param        offset query int false "Number of items to skip" default(0)
+// @Param        status query bool false "Include status of the movies in personal lists of the current user"
 // @Security BasicAuth
+// @Security BearerAuth
 // @Success      200 {array} models.MovieOut
-// @Failure      500 {string} string "Internal server error"
-// @Failure      403 {string} string "User does not exist"
+// @Header       200 {integer} X-Total-Count "Total number of items"
+// @Header       200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
 // @Router       /movies/actor/{actor} [get]
 func (app *App) GetMoviesByActor(w http.ResponseWriter, r *http.Request) {
 	app.infoLog.Println("trying to get list of movies by searching the actor")
-	if _, err := app.authIsAdmin(r); err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusForbidden)
+	actor := r.PathValue("actor")
+	page, err := parsePage(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
 		return
 	}
-
-	actor := r.PathValue("actor")
-	movies, err := app.dbHandler.GetMoviesByActor(actor)
+	withStatus, err := parseWithStatus(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+	search, err := parseNameSearch(r, actor)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+	movies, total, err := app.dbHandler.GetMoviesByActor(search, page)
 	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusInternalServerError)
+		app.handleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 
-	app.sendJson(w, movies)
+	if withStatus {
+		if err := app.dbHandler.FillMovieStatuses(principalFrom(r).nickname, movies); err != nil {
+			app.handleError(w, r, err, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	app.fillPosters(movies)
+	app.sendPage(w, r, page, total, movies)
 	app.infoLog.Println("list of movies is getted by searching the actor")
 }
 
 // AddUser - обрабатывает http запрос на добавление пользователя в фильмотеку.
 //
 // @Summary      Adds user to the System.
-// @Description  Add user to the System and get it's ID. User should be an admin.
+// @Description  Add user to the System and get it's ID. User should have the users:manage permission and all permissions of the given roles.
 // @Tags         User
 // @Accept       json
 // @Produce      json
 // @Param        user body models.User true "User to be added"
 // @Security BasicAuth
+// @Security BearerAuth
 // @Success      200 {integer} int "ID of the added user"
-// @Failure      400 {string} string "Bad request"
-// @Failure      403 {string} string "User not an admin"
-// @Failure      500 {string} string "Internal server error"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      409 {object} models.Problem "Nickname is already taken"
+// @Failure      422 {object} models.Problem "Role not found"
+// @Failure      500 {object} models.Problem "Internal server error"
 // @Router       /users [post]
 func (app *App) AddUser(w http.ResponseWriter, r *http.Request) {
 	app.infoLog.Println("trying to add a new user")
-	isAdmin, err := app.authIsAdmin(r)
-	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusForbidden)
-		return
-	}
-	if !isAdmin {
-		handleError(app.errorLog, w, "user trying to add a new movie not an admin", http.StatusForbidden)
-		return
-	}
-
 	var user models.User
 	d := json.NewDecoder(r.Body)
-	if err = d.Decode(&user); err == nil {
+	err := d.Decode(&user)
+	if err == nil {
 		err = user.Check()
 	}
 	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusBadRequest)
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+	if err = app.checkGrantable(r, user.RoleNames()); err != nil {
+		app.handleError(w, r, err, authErrorStatus(err))
 		return
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), 8)
 	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusInternalServerError)
+		app.handleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 	user.Password = string(hashedPassword)
 
 	id, err := app.dbHandler.AddUser(user)
 	if err != nil {
-		handleError(app.errorLog, w, err.Error(), http.StatusInternalServerError)
+		app.handleError(w, r, err, dbErrorStatus(err))
 		return
 	}
 
-	app.sendJson(w, id)
+	app.sendJson(w, r, id)
 	app.infoLog.Printf("user %d is added\n", id)
 }
+
+// GetUsers - обрабатывает http запрос на получение списка пользователей фильмотеки.
+//
+// @Summary      Get users from the System.
+// @Description  Get users from the System. User should have the users:manage permission. Passwords are never returned.
+// @Tags         User
+// @Produce      json
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {array} models.UserOut
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /users [get]
+func (app *App) GetUsers(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get list of users")
+	users, err := app.dbHandler.GetUsers()
+	if err != nil {
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	app.sendJson(w, r, users)
+	app.infoLog.Println("list of users is getted")
+}
+
+// GetUser - обрабатывает http запрос на получение пользователя фильмотеки.
+//
+// @Summary      Get user from the System.
+// @Description  Get user from the System. User should have the users:manage permission. Password is never returned.
+// @Tags         User
+// @Produce      json
+// @Param        id path int true "ID of the user to be getted"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {object} models.UserOut
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "User not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /users/{id} [get]
+func (app *App) GetUser(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get a user")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+	user, err := app.dbHandler.GetUser(id)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendJson(w, r, user)
+	app.infoLog.Printf("user %d is getted\n", id)
+}
+
+// UpdateUser - обрабатывает http запрос на изменение пользователя фильмотеки.
+//
+// @Summary      Updates user in the System.
+// @Description  Replace roles of the user in the System. User should have the users:manage permission and all permissions of the given and current roles of the user. Deprecated is_admin flag is accepted instead of roles.
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID of the user to be updated"
+// @Param        user body models.UserPatch true "User data to be updated"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "User not found"
+// @Failure      422 {object} models.Problem "Role not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /users/{id} [patch]
+func (app *App) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to update a user")
+	var patch models.UserPatch
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&patch); err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+	if patch.RoleNames() == nil {
+		app.handleError(w, r, errNothingToUpdate, http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+	if err = app.checkGrantable(r, patch.RoleNames()); err != nil {
+		app.handleError(w, r, err, authErrorStatus(err))
+		return
+	}
+	if err = app.checkManageable(r, id); err != nil {
+		app.handleError(w, r, err, manageErrorStatus(err))
+		return
+	}
+
+	if err := app.dbHandler.UpdateUser(id, patch); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("user %d is updated\n", id)
+}
+
+// DeleteUser - обрабатывает http запрос на удаление пользователя из фильмотеки.
+//
+// @Summary      Deletes user from the System.
+// @Description  Delete user and its sessions from the System. User should have the users:manage permission and all permissions of the roles of the user.
+// @Tags         User
+// @Produce      json
+// @Param        id path int true "ID of the user to be deleted"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "User not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /users/{id} [delete]
+func (app *App) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to delete a user")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+	if err = app.checkManageable(r, id); err != nil {
+		app.handleError(w, r, err, manageErrorStatus(err))
+		return
+	}
+
+	if err := app.dbHandler.DeleteUser(id); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("user %d is deleted\n", id)
+}
+
+// GetRoles - обрабатывает http запрос на получение списка ролей фильмотеки.
+//
+// @Summary      Get roles from the System.
+// @Description  Get roles with their permissions from the System. User should have the users:manage permission.
+// @Tags         User
+// @Produce      json
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {array} models.Role
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /roles [get]
+func (app *App) GetRoles(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get list of roles")
+	roles, err := app.dbHandler.GetRoles()
+	if err != nil {
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	app.sendJson(w, r, roles)
+	app.infoLog.Println("list of roles is getted")
+}
+
+// ChangePassword - обрабатывает http запрос на смену пароля текущего пользователя.
+//
+// @Summary      Changes password of the current user.
+// @Description  Change password of the authenticated user. The old password is required. All sessions of the user are revoked.
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        password body models.PasswordChange true "Old and new passwords"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Old password is wrong"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /users/me/password [put]
+func (app *App) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to change password")
+	user := principalFrom(r)
+
+	var change models.PasswordChange
+	d := json.NewDecoder(r.Body)
+	err := d.Decode(&change)
+	if err == nil {
+		err = change.Check()
+	}
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if err = app.dbHandler.CheckUserPassword(user.nickname, change.OldPassword); err != nil {
+		status := authErrorStatus(err)
+		if errors.Is(err, postgres.ErrInvalidCredentials) {
+			status = http.StatusForbidden
+		}
+		app.handleError(w, r, err, status)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(change.NewPassword), 8)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	if err = app.dbHandler.UpdateUserPassword(user.nickname, string(hashedPassword)); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("password of user %s is changed\n", user.nickname)
+}
@@ -3,63 +3,178 @@ package filmoteka
 import (
 	"encoding/json"
 	"errors"
-	"log"
+	"fmt"
 	"net/http"
+	"strings"
 
-	"golang.org/x/crypto/bcrypt"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/auth"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+)
+
+var (
+	// errNoCredentials - ошибка отсутствия данных для авторизации.
+	errNoCredentials = errors.New("error parsing basic auth")
+	// errTokensDisabled - ошибка авторизации по токену, когда она отключена.
+	errTokensDisabled = errors.New("token authentication is disabled")
+	// errIdNotInteger - ошибка разбора id из пути запроса.
+	errIdNotInteger = errors.New("id must be an integer")
+	// errNothingToUpdate - ошибка пустого запроса на изменение.
+	errNothingToUpdate = errors.New("nothing to update")
+	// errNoBearerToken - ошибка отсутствия Bearer токена.
+	errNoBearerToken = errors.New("bearer token is required")
+	// errNoPermission - ошибка недостатка прав пользователя.
+	errNoPermission = errors.New("user does not have permission")
+	// errNoProfile - ошибка обращения базового администратора к личным данным, которых у него нет.
+	errNoProfile = fmt.Errorf("%w: built-in admin has no reviews, lists or collections, use a regular user account", errNoPermission)
 )
 
 // sendJson - отправка json-ответа.
 //
-// Принимает: ResponseWriter и любой объект.
-func (app *App) sendJson(w http.ResponseWriter, obj any) {
+// Принимает: ResponseWriter, запрос и любой объект.
+func (app *App) sendJson(w http.ResponseWriter, r *http.Request, obj any) {
 	js, err := json.Marshal(obj)
 	if err != nil {
-		app.errorLog.Println(err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		app.handleError(w, r, err, http.StatusInternalServerError)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	_, err = w.Write(js)
-	if err != nil {
+	if _, err = w.Write(js); err != nil {
 		app.errorLog.Println(err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
-// authIsAdmin - проверка прав пользователя.
+// authUser - получение авторизованного пользователя.
 //
 // Принимает: http.Request.
 //
-// Возвращает: true, если пользователь админ, иначе false и ошибку.
-func (app *App) authIsAdmin(r *http.Request) (bool, error) {
-	nick, pswd, ok := r.BasicAuth()
-	if !ok {
-		return false, errors.New("error parsing basic auth")
+// Поддерживает Basic Auth и Bearer токен, выданный через /auth/login.
+//
+// Возвращает: пользователя вместе с его правами и ошибку.
+func (app *App) authUser(r *http.Request) (principal, error) {
+	var p principal
+	if token, ok := bearerToken(r); ok {
+		s, err := app.authSession(token)
+		if err != nil {
+			return principal{}, err
+		}
+		p = principal{nickname: s.Nickname, superuser: s.Superuser}
+	} else {
+		nick, pswd, ok := r.BasicAuth()
+		if !ok {
+			return principal{}, errNoCredentials
+		}
+		superuser, err := app.checkCredentials(nick, pswd)
+		if err != nil {
+			return principal{}, err
+		}
+		p = principal{nickname: nick, superuser: superuser}
 	}
 
+	if !p.superuser {
+		permissions, err := app.dbHandler.GetUserPermissions(p.nickname)
+		if err != nil {
+			return principal{}, err
+		}
+		p.permissions = permissions
+	}
+	return p, nil
+}
+
+// checkCredentials - проверка логина и пароля пользователя.
+//
+// Принимает: никнейм и пароль.
+//
+// Возвращает: true, если это базовый администратор, обладающий всеми правами, и ошибку.
+func (app *App) checkCredentials(nick, pswd string) (bool, error) {
 	if app.defAdmin && nick == "admin" && pswd == "admin" {
 		return true, nil
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(pswd), 8)
-	if err != nil {
+	if err := app.dbHandler.CheckUserPassword(nick, pswd); err != nil {
 		return false, err
 	}
 
-	isAdmin, err := app.dbHandler.CheckUserRole(nick, string(hashedPassword))
+	return false, nil
+}
+
+// authSession - получение сессии по токену доступа.
+//
+// Принимает: токен доступа.
+//
+// Возвращает: сессию и ошибку.
+func (app *App) authSession(token string) (models.Session, error) {
+	if app.signer == nil {
+		return models.Session{}, errTokensDisabled
+	}
+	claims, err := app.signer.Parse(token, auth.TypeAccess)
 	if err != nil {
-		return false, err
+		return models.Session{}, err
 	}
+	return app.dbHandler.GetSession(claims.SessionId)
+}
 
-	return isAdmin, nil
+// bearerToken - получение Bearer токена из заголовка Authorization.
+//
+// Принимает: http.Request.
+//
+// Возвращает: токен и true, если он передан.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
 }
 
-// handleError - обработка ошибок.
+// authErrorStatus - получение http-статуса для ошибки авторизации.
 //
-// Принимает: логгер, ResponseWriter, сообщение и http-статус.
-func handleError(log *log.Logger, w http.ResponseWriter, msg string, status int) {
-	log.Println(msg)
-	http.Error(w, msg, status)
+// Принимает: ошибку, полученную при проверке пользователя.
+//
+// Возвращает: 403, если у пользователя нет прав, 401, если не удалось установить пользователя, иначе 500.
+func authErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errNoPermission):
+		return http.StatusForbidden
+	case errors.Is(err, errNoCredentials), errors.Is(err, errTokensDisabled),
+		errors.Is(err, postgres.ErrUnknownUser), errors.Is(err, postgres.ErrInvalidCredentials),
+		errors.Is(err, postgres.ErrUnknownSession),
+		errors.Is(err, auth.ErrMalformed), errors.Is(err, auth.ErrSignature),
+		errors.Is(err, auth.ErrExpired), errors.Is(err, auth.ErrType):
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// manageErrorStatus - получение http-статуса для ошибки проверки прав на пользователя.
+//
+// Принимает: ошибку проверки.
+//
+// Возвращает: 403, если прав недостаточно, иначе статус ошибки обработчика БД.
+func manageErrorStatus(err error) int {
+	if errors.Is(err, errNoPermission) {
+		return http.StatusForbidden
+	}
+	return dbErrorStatus(err)
+}
+
+// dbErrorStatus - получение http-статуса для ошибки обработчика БД.
+//
+// Принимает: ошибку обработчика БД.
+//
+// Возвращает: 404, если записи нет, 409, если запись конфликтует с существующей,
+// 422, если запись ссылается на несуществующую, иначе 500.
+func dbErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, postgres.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, postgres.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, postgres.ErrForeignKey):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
 }
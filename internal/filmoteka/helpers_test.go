@@ -1,29 +1,33 @@
 package filmoteka
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/auth"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
 	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestSendJson(t *testing.T) {
 	t.Run("send json success", func(t *testing.T) {
 		logger := log.New(io.Discard, "test", log.LstdFlags)
-		app := CreateApp(":8080", logger, logger, nil, false)
+		app := CreateApp(":8080", logger, logger, nil, false, nil, RegistrationPolicy{}, nil)
 		w := httptest.NewRecorder()
 		obj := map[string]interface{}{
 			"key": "value",
 		}
 
-		app.sendJson(w, obj)
+		app.sendJson(w, httptest.NewRequest(http.MethodGet, "/", nil), obj)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
@@ -35,19 +39,20 @@ func TestSendJson(t *testing.T) {
 
 	t.Run("send json error", func(t *testing.T) {
 		logger := log.New(io.Discard, "test", log.LstdFlags)
-		app := CreateApp(":8080", logger, logger, nil, false)
+		app := CreateApp(":8080", logger, logger, nil, false, nil, RegistrationPolicy{}, nil)
 		w := httptest.NewRecorder()
 		obj := make(chan int)
 		defer close(obj)
 
-		app.sendJson(w, obj)
+		app.sendJson(w, httptest.NewRequest(http.MethodGet, "/", nil), obj)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Equal(t, "json: unsupported type: chan int\n", w.Body.String())
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		assert.NotContains(t, w.Body.String(), "chan int")
 	})
 }
 
-func TestAuthIsAdmin(t *testing.T) {
+func TestAuthUser(t *testing.T) {
 	logger := log.New(io.Discard, "test", log.LstdFlags)
 	mockDB, mock, err := sqlmock.New()
 	if err != nil {
@@ -55,70 +60,160 @@ func TestAuthIsAdmin(t *testing.T) {
 	}
 	defer mockDB.Close()
 	dbHandler, _ := postgres.GetHandler(mockDB, false)
-	app := CreateApp(":8080", logger, logger, dbHandler, true)
+	signer := auth.NewSigner([]byte("secret"), time.Minute, time.Hour)
+	app := CreateApp(":8080", logger, logger, dbHandler, true, signer, RegistrationPolicy{}, nil)
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	userRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "nickname", "password", "is_admin"}).AddRow(1, "user", string(hash), false)
+	}
+	permissionRows := func(permissions ...string) *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"permission"})
+		for _, p := range permissions {
+			rows.AddRow(p)
+		}
+		return rows
+	}
+	sessionRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "nickname", "superuser", "expires_at"})
+	}
 
 	t.Run("default admin", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 		req.SetBasicAuth("admin", "admin")
-		isAdmin, err := app.authIsAdmin(req)
+		p, err := app.authUser(req)
 		assert.NoError(t, err)
-		assert.True(t, isAdmin)
+		assert.Equal(t, "admin", p.nickname)
+		assert.True(t, p.can(models.PermUsersManage))
 	})
 
-	t.Run("non-admin", func(t *testing.T) {
+	t.Run("basic", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 		req.SetBasicAuth("user", "password")
-		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
-		isAdmin, err := app.authIsAdmin(req)
+		mock.ExpectQuery("SELECT").WithArgs("user").WillReturnRows(userRows())
+		mock.ExpectQuery("SELECT").WithArgs("user").WillReturnRows(permissionRows(models.PermCatalogRead))
+		p, err := app.authUser(req)
 		assert.NoError(t, err)
-		assert.False(t, isAdmin)
+		assert.Equal(t, "user", p.nickname)
+		assert.True(t, p.can(models.PermCatalogRead))
+		assert.False(t, p.can(models.PermCatalogWrite))
 	})
 
-	t.Run("admin", func(t *testing.T) {
+	t.Run("wrong password", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
-		req.SetBasicAuth("user", "password")
-		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
-		isAdmin, err := app.authIsAdmin(req)
-		assert.NoError(t, err)
-		assert.True(t, isAdmin)
+		req.SetBasicAuth("user", "wrong")
+		mock.ExpectQuery("SELECT").WithArgs("user").WillReturnRows(userRows())
+		_, err := app.authUser(req)
+		assert.ErrorIs(t, err, postgres.ErrInvalidCredentials)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth("nobody", "password")
+		mock.ExpectQuery("SELECT").WithArgs("nobody").WillReturnRows(sqlmock.NewRows([]string{"id", "nickname", "password", "is_admin"}))
+		_, err := app.authUser(req)
+		assert.ErrorIs(t, err, postgres.ErrInvalidCredentials)
+		assert.NotErrorIs(t, err, postgres.ErrUnknownUser)
 	})
 
 	t.Run("error parsing basic auth", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
-		isAdmin, err := app.authIsAdmin(req)
-		assert.Error(t, err)
-		assert.False(t, isAdmin)
+		_, err := app.authUser(req)
+		assert.ErrorIs(t, err, errNoCredentials)
 	})
-}
 
-func TestHandleError(t *testing.T) {
-	t.Run("first case", func(t *testing.T) {
-		var buf bytes.Buffer
-		logger := log.New(&buf, "", log.LstdFlags)
-		w := httptest.NewRecorder()
-		errTxt := "An error occurred"
-		status := http.StatusInternalServerError
+	t.Run("bearer", func(t *testing.T) {
+		token, _ := signer.IssueAccess("sid")
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		mock.ExpectQuery("SELECT").WithArgs("sid").WillReturnRows(sessionRows().AddRow("sid", "user", false, time.Now().Add(time.Hour)))
+		mock.ExpectQuery("SELECT").WithArgs("user").WillReturnRows(permissionRows(models.PermCatalogRead, models.PermCatalogWrite))
+		p, err := app.authUser(req)
+		assert.NoError(t, err)
+		assert.Equal(t, "user", p.nickname)
+		assert.True(t, p.can(models.PermCatalogWrite))
+		assert.False(t, p.can(models.PermUsersManage))
+	})
 
-		handleError(logger, w, errTxt, status)
-		errTxt += "\n"
+	t.Run("bearer superuser", func(t *testing.T) {
+		token, _ := signer.IssueAccess("sid")
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		mock.ExpectQuery("SELECT").WithArgs("sid").WillReturnRows(sessionRows().AddRow("sid", "admin", true, time.Now().Add(time.Hour)))
+		p, err := app.authUser(req)
+		assert.NoError(t, err)
+		assert.True(t, p.can(models.PermUsersManage))
+	})
 
-		assert.Contains(t, buf.String(), errTxt)
-		assert.Equal(t, status, w.Code)
-		assert.Equal(t, errTxt, w.Body.String())
+	t.Run("bearer revoked session", func(t *testing.T) {
+		token, _ := signer.IssueAccess("sid")
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		mock.ExpectQuery("SELECT").WithArgs("sid").WillReturnRows(sessionRows())
+		_, err := app.authUser(req)
+		assert.ErrorIs(t, err, postgres.ErrUnknownSession)
 	})
 
-	t.Run("second case", func(t *testing.T) {
-		var buf bytes.Buffer
-		logger := log.New(&buf, "", log.LstdFlags)
-		w := httptest.NewRecorder()
-		errTxt := "Something went wrong"
-		status := http.StatusForbidden
+	t.Run("bearer refresh token", func(t *testing.T) {
+		token, _ := signer.IssueRefresh("sid")
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		_, err := app.authUser(req)
+		assert.ErrorIs(t, err, auth.ErrType)
+	})
 
-		handleError(logger, w, errTxt, status)
-		errTxt += "\n"
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthErrorStatus(t *testing.T) {
+	cases := map[error]int{
+		errNoPermission:                http.StatusForbidden,
+		errNoCredentials:               http.StatusUnauthorized,
+		postgres.ErrUnknownUser:        http.StatusUnauthorized,
+		postgres.ErrInvalidCredentials: http.StatusUnauthorized,
+		postgres.ErrUnknownSession:     http.StatusUnauthorized,
+		auth.ErrExpired:                http.StatusUnauthorized,
+		errors.New("db is down"):       http.StatusInternalServerError,
+	}
+	for err, status := range cases {
+		assert.Equal(t, status, authErrorStatus(errors.Join(errors.New("wrap"), err)), err.Error())
+	}
+}
 
-		assert.Contains(t, buf.String(), errTxt)
-		assert.Equal(t, status, w.Code)
-		assert.Equal(t, errTxt, w.Body.String())
+func TestDbErrorStatus(t *testing.T) {
+	cases := map[error]int{
+		postgres.ErrUnknownUser:   http.StatusNotFound,
+		postgres.ErrUnknownActor:  http.StatusNotFound,
+		postgres.ErrUnknownMovie:  http.StatusNotFound,
+		postgres.ErrNicknameTaken: http.StatusConflict,
+		postgres.ErrForeignKey:    http.StatusUnprocessableEntity,
+		postgres.ErrUnknownRole:   http.StatusUnprocessableEntity,
+		errors.New("db is down"):  http.StatusInternalServerError,
+	}
+	for err, status := range cases {
+		assert.Equal(t, status, dbErrorStatus(errors.Join(errors.New("wrap"), err)), err.Error())
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	t.Run("bearer", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer token")
+		token, ok := bearerToken(req)
+		assert.True(t, ok)
+		assert.Equal(t, "token", token)
+	})
+
+	t.Run("basic", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth("user", "password")
+		_, ok := bearerToken(req)
+		assert.False(t, ok)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		_, ok := bearerToken(req)
+		assert.False(t, ok)
 	})
 }
@@ -0,0 +1,377 @@
+package filmoteka
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/storage"
+)
+
+// imageMaxPixels - максимальное количество пикселей загружаемого изображения.
+//
+// Ограничивает память, занимаемую при декодировании небольшого по размеру файла с огромным разрешением.
+const imageMaxPixels = 50_000_000
+
+// errImagesDisabled - ошибка загрузки изображения без настроенного хранилища.
+var errImagesDisabled = errors.New("image uploads are disabled")
+
+// imageTarget - описание изображения, прикрепляемого к записи фильмотеки.
+type imageTarget struct {
+	name string                                   // name - название изображения в ключах файлов и логах.
+	dir  string                                   // dir - каталог хранилища с изображениями записей.
+	set  func(id int, key string) (string, error) // set - замена ключа файла в БД с получением прежнего ключа.
+}
+
+// UploadMoviePoster - обрабатывает http запрос на загрузку постера фильма.
+//
+// @Summary      Uploads poster of the movie.
+// @Description  Upload JPEG, PNG or GIF poster up to 5 MB as the image field of multipart form, replacing the previous poster. A JPEG thumbnail is generated. User should have the catalog:write permission.
+// @Tags         Movie
+// @Accept       mpfd
+// @Produce      json
+// @Param        id path int true "ID of the movie"
+// @Param        image formData file true "Poster"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {object} models.Image
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Movie not found"
+// @Failure      413 {object} models.Problem "Image is too large"
+// @Failure      415 {object} models.Problem "Unsupported image type"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Failure      501 {object} models.Problem "Image uploads are disabled"
+// @Router       /movie/{id}/poster [put]
+func (app *App) UploadMoviePoster(w http.ResponseWriter, r *http.Request) {
+	app.uploadImage(w, r, imageTarget{name: "poster", dir: "movies", set: app.dbHandler.SetMoviePoster})
+}
+
+// DeleteMoviePoster - обрабатывает http запрос на удаление постера фильма.
+//
+// @Summary      Deletes poster of the movie.
+// @Description  Delete poster of the movie together with its thumbnail. User should have the catalog:write permission.
+// @Tags         Movie
+// @Produce      json
+// @Param        id path int true "ID of the movie"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Movie not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Failure      501 {object} models.Problem "Image uploads are disabled"
+// @Router       /movie/{id}/poster [delete]
+func (app *App) DeleteMoviePoster(w http.ResponseWriter, r *http.Request) {
+	app.deleteImage(w, r, imageTarget{name: "poster", dir: "movies", set: app.dbHandler.SetMoviePoster})
+}
+
+// UploadActorPhoto - обрабатывает http запрос на загрузку фотографии актёра.
+//
+// @Summary      Uploads photo of the actor.
+// @Description  Upload JPEG, PNG or GIF headshot up to 5 MB as the image field of multipart form, replacing the previous photo. A JPEG thumbnail is generated. User should have the catalog:write permission.
+// @Tags         Actor
+// @Accept       mpfd
+// @Produce      json
+// @Param        id path int true "ID of the actor"
+// @Param        image formData file true "Photo"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {object} models.Image
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Actor not found"
+// @Failure      413 {object} models.Problem "Image is too large"
+// @Failure      415 {object} models.Problem "Unsupported image type"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Failure      501 {object} models.Problem "Image uploads are disabled"
+// @Router       /actor/{id}/photo [put]
+func (app *App) UploadActorPhoto(w http.ResponseWriter, r *http.Request) {
+	app.uploadImage(w, r, imageTarget{name: "photo", dir: "actors", set: app.dbHandler.SetActorPhoto})
+}
+
+// DeleteActorPhoto - обрабатывает http запрос на удаление фотографии актёра.
+//
+// @Summary      Deletes photo of the actor.
+// @Description  Delete photo of the actor together with its thumbnail. User should have the catalog:write permission.
+// @Tags         Actor
+// @Produce      json
+// @Param        id path int true "ID of the actor"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Actor not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Failure      501 {object} models.Problem "Image uploads are disabled"
+// @Router       /actor/{id}/photo [delete]
+func (app *App) DeleteActorPhoto(w http.ResponseWriter, r *http.Request) {
+	app.deleteImage(w, r, imageTarget{name: "photo", dir: "actors", set: app.dbHandler.SetActorPhoto})
+}
+
+// GetMedia - обрабатывает http запрос на получение файла изображения из хранилища.
+//
+// @Summary      Get image file.
+// @Description  Get poster, photo or thumbnail by the URL returned in movies and actors. Does not require authentication.
+// @Tags         Media
+// @Produce      image/jpeg,image/png,image/gif
+// @Param        key path string true "Key of the file"
+// @Success      200 {file} file "Image"
+// @Failure      404 {object} models.Problem "File not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /media/{key} [get]
+func (app *App) GetMedia(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if app.storage == nil {
+		app.handleError(w, r, storage.ErrNotFound, http.StatusNotFound)
+		return
+	}
+	f, err := app.storage.Open(key)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+			status = http.StatusNotFound
+		}
+		app.handleError(w, r, err, status)
+		return
+	}
+	defer f.Close()
+
+	if ct := mime.TypeByExtension(path.Ext(key)); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+	// Ключи файлов уникальны для каждой загрузки, поэтому файл по ключу не меняется.
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err = io.Copy(w, f); err != nil {
+		app.errorLog.Println(err)
+	}
+}
+
+// uploadImage - загрузка изображения записи фильмотеки вместе с миниатюрой в хранилище.
+//
+// Принимает: ResponseWriter, запрос и описание изображения.
+//
+// Прежние файлы изображения удаляются после сохранения нового ключа в БД.
+func (app *App) uploadImage(w http.ResponseWriter, r *http.Request, t imageTarget) {
+	app.infoLog.Printf("trying to upload a %s\n", t.name)
+	if app.storage == nil {
+		app.handleError(w, r, errImagesDisabled, http.StatusNotImplemented)
+		return
+	}
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	data, ext, img, status, err := readImage(w, r)
+	if err != nil {
+		app.handleError(w, r, err, status)
+		return
+	}
+	var thumb bytes.Buffer
+	if err = jpeg.Encode(&thumb, thumbnail(img, models.ThumbnailMaxSide), &jpeg.Options{Quality: 85}); err != nil {
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	key := fmt.Sprintf("%s/%d/%s-%s%s", t.dir, id, t.name, strconv.FormatInt(time.Now().UnixNano(), 36), ext)
+	err = app.storage.Save(key, bytes.NewReader(data))
+	if err == nil {
+		err = app.storage.Save(thumbnailKey(key), &thumb)
+	}
+	if err != nil {
+		app.removeImage(key)
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	old, err := t.set(id, key)
+	if err != nil {
+		app.removeImage(key)
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+	app.removeImage(old)
+
+	app.sendJson(w, r, app.imageOf(key))
+	app.infoLog.Printf("%s of %s %d is uploaded\n", t.name, t.dir, id)
+}
+
+// deleteImage - удаление изображения записи фильмотеки вместе с миниатюрой.
+//
+// Принимает: ResponseWriter, запрос и описание изображения.
+func (app *App) deleteImage(w http.ResponseWriter, r *http.Request, t imageTarget) {
+	app.infoLog.Printf("trying to delete a %s\n", t.name)
+	if app.storage == nil {
+		app.handleError(w, r, errImagesDisabled, http.StatusNotImplemented)
+		return
+	}
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	old, err := t.set(id, "")
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+	app.removeImage(old)
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("%s of %s %d is deleted\n", t.name, t.dir, id)
+}
+
+// readImage - чтение изображения из поля image multipart формы.
+//
+// Принимает: ResponseWriter и запрос.
+//
+// Возвращает: содержимое файла, расширение по его типу, декодированное изображение,
+// http-статус ошибки и ошибку (превышение размера, неподдерживаемый тип или повреждённый файл).
+func readImage(w http.ResponseWriter, r *http.Request) ([]byte, string, image.Image, int, error) {
+	// Помимо файла тело формы содержит заголовки и разделители частей.
+	r.Body = http.MaxBytesReader(w, r.Body, models.ImageMaxSize+1<<20)
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, "", nil, http.StatusRequestEntityTooLarge, errors.Join(err, &models.FieldError{Field: "image", Message: "image must be less than 5 MB"})
+		}
+		return nil, "", nil, http.StatusBadRequest, errors.Join(err, &models.FieldError{Field: "image", Message: "image must be sent as the image field of multipart form"})
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, models.ImageMaxSize+1))
+	if err != nil {
+		return nil, "", nil, http.StatusBadRequest, err
+	}
+	if len(data) > models.ImageMaxSize {
+		return nil, "", nil, http.StatusRequestEntityTooLarge, &models.FieldError{Field: "image", Message: "image must be less than 5 MB"}
+	}
+	ext, ok := models.ImageTypes[http.DetectContentType(data)]
+	if !ok {
+		return nil, "", nil, http.StatusUnsupportedMediaType, &models.FieldError{Field: "image", Message: "image must be a JPEG, PNG or GIF"}
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err == nil && cfg.Width*cfg.Height > imageMaxPixels {
+		return nil, "", nil, http.StatusRequestEntityTooLarge, &models.FieldError{Field: "image", Message: "image must be less than 50 megapixels"}
+	}
+	var img image.Image
+	if err == nil {
+		img, _, err = image.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, "", nil, http.StatusBadRequest, errors.Join(err, &models.FieldError{Field: "image", Message: "image is corrupted"})
+	}
+	return data, ext, img, 0, nil
+}
+
+// thumbnail - получение миниатюры изображения.
+//
+// Принимает: изображение и максимальную длину большей стороны миниатюры.
+//
+// Каждый пиксель миниатюры усредняет покрываемую им область изображения, прозрачные области заливаются белым,
+// а изображения меньше миниатюры не увеличиваются.
+//
+// Возвращает: миниатюру.
+func thumbnail(img image.Image, maxSide int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w > maxSide || h > maxSide {
+		if w >= h {
+			tw, th = maxSide, max(1, h*maxSide/w)
+		} else {
+			tw, th = max(1, w*maxSide/h), maxSide
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+max((y+1)*h/th, y*h/th+1)
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+max((x+1)*w/tw, x*w/tw+1)
+			var sr, sg, sb, sa, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					sr, sg, sb, sa, n = sr+uint64(cr), sg+uint64(cg), sb+uint64(cb), sa+uint64(ca), n+1
+				}
+			}
+			// Цвета премультиплицированы, поэтому белый фон добавляется в доле прозрачности.
+			bg := 0xffff - sa/n
+			dst.Set(x, y, color.RGBA64{R: uint16(sr/n + bg), G: uint16(sg/n + bg), B: uint16(sb/n + bg), A: 0xffff})
+		}
+	}
+	return dst
+}
+
+// thumbnailKey - получение ключа файла миниатюры по ключу файла изображения.
+func thumbnailKey(key string) string {
+	return strings.TrimSuffix(key, path.Ext(key)) + "-thumb.jpg"
+}
+
+// imageOf - получение адресов изображения и его миниатюры.
+//
+// Принимает: ключ файла изображения в хранилище.
+//
+// Возвращает: изображение или nil, если ключ пуст или хранилище не настроено.
+func (app *App) imageOf(key string) *models.Image {
+	if key == "" || app.storage == nil {
+		return nil
+	}
+	return &models.Image{Url: app.storage.URL(key), ThumbnailUrl: app.storage.URL(thumbnailKey(key))}
+}
+
+// fillPosters - заполнение адресов постеров фильмов.
+func (app *App) fillPosters(movies []models.MovieOut) {
+	for i := range movies {
+		movies[i].Poster = app.imageOf(movies[i].PosterKey)
+	}
+}
+
+// fillPhotos - заполнение адресов фотографий актёров.
+func (app *App) fillPhotos(actors []models.ActorOut) {
+	for i := range actors {
+		actors[i].Photo = app.imageOf(actors[i].PhotoKey)
+	}
+}
+
+// removeImage - удаление файлов изображения и его миниатюры из хранилища.
+//
+// Принимает: ключ файла изображения, пустой ключ или отключённое хранилище пропускаются.
+//
+// Ошибки удаления только пишутся в лог: запись в БД уже не ссылается на файлы.
+func (app *App) removeImage(key string) {
+	if key == "" || app.storage == nil {
+		return
+	}
+	for _, k := range []string{key, thumbnailKey(key)} {
+		if err := app.storage.Delete(k); err != nil {
+			app.errorLog.Println(err)
+		}
+	}
+}
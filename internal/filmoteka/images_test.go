@@ -0,0 +1,192 @@
+package filmoteka
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// multipartImage - получение запроса с файлом в поле image multipart формы.
+func multipartImage(t *testing.T, target string, data []byte) *http.Request {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("image", "image")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(data)
+	mw.Close()
+	r := httptest.NewRequest(http.MethodPut, target, &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return r
+}
+
+func TestImages(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+	dbHandler, _ := postgres.GetHandler(mockDB, false)
+	store, err := storage.NewFS(t.TempDir(), "/media/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := CreateApp(":8080", logger, logger, dbHandler, false, nil, RegistrationPolicy{}, store)
+
+	var poster bytes.Buffer
+	png.Encode(&poster, image.NewRGBA(image.Rect(0, 0, 640, 960)))
+	keyRe := regexp.MustCompile(`^movies/2/poster-[0-9a-z]+\.png$`)
+
+	var key string
+	t.Run("upload poster", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE movies m SET poster").WithArgs(2, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"poster"}).AddRow(""))
+
+		w := httptest.NewRecorder()
+		r := multipartImage(t, "/movie/2/poster", poster.Bytes())
+		r.SetPathValue("id", "2")
+		app.UploadMoviePoster(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Regexp(t, `"url":"/media/movies/2/poster-[0-9a-z]+\.png","thumbnail_url":"/media/movies/2/poster-[0-9a-z]+-thumb\.jpg"`, w.Body.String())
+
+		key = strings.TrimPrefix(regexp.MustCompile(`/media/([^"]+\.png)`).FindString(w.Body.String()), "/media/")
+		assert.Regexp(t, keyRe, key)
+		f, err := store.Open(thumbnailKey(key))
+		if assert.NoError(t, err) {
+			defer f.Close()
+			thumb, err := jpeg.DecodeConfig(f)
+			assert.NoError(t, err)
+			assert.Equal(t, image.Config{ColorModel: color.YCbCrModel, Width: 213, Height: 320}, thumb)
+		}
+	})
+
+	t.Run("get media", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/media/"+key, nil)
+		r.SetPathValue("key", key)
+		app.GetMedia(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, poster.Bytes(), w.Body.Bytes())
+	})
+
+	t.Run("replace poster of unknown movie", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE movies m SET poster").WithArgs(9, sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows([]string{"poster"}))
+
+		w := httptest.NewRecorder()
+		r := multipartImage(t, "/movie/9/poster", poster.Bytes())
+		r.SetPathValue("id", "9")
+		app.UploadMoviePoster(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "unknown movie")
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := multipartImage(t, "/actor/1/photo", []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+		r.SetPathValue("id", "1")
+		app.UploadActorPhoto(w, r)
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+		assert.Contains(t, w.Body.String(), "image must be a JPEG, PNG or GIF")
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		data := append(poster.Bytes()[:len(poster.Bytes()):len(poster.Bytes())], make([]byte, models.ImageMaxSize)...)
+		w := httptest.NewRecorder()
+		r := multipartImage(t, "/actor/1/photo", data)
+		r.SetPathValue("id", "1")
+		app.UploadActorPhoto(w, r)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("delete poster", func(t *testing.T) {
+		mock.ExpectQuery("UPDATE movies m SET poster").WithArgs(2, "").WillReturnRows(sqlmock.NewRows([]string{"poster"}).AddRow(key))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodDelete, "/movie/2/poster", nil)
+		r.SetPathValue("id", "2")
+		app.DeleteMoviePoster(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		_, err := store.Open(key)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		_, err = store.Open(thumbnailKey(key))
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("delete movie with poster", func(t *testing.T) {
+		key := "movies/2/poster-old.png"
+		store.Save(key, bytes.NewReader(poster.Bytes()))
+		store.Save(thumbnailKey(key), bytes.NewReader(poster.Bytes()))
+		mock.ExpectQuery("DELETE FROM movies").WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"poster"}).AddRow(key))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodDelete, "/movie/2", nil)
+		r.SetPathValue("id", "2")
+		app.DeleteMovie(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		_, err := store.Open(key)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		_, err = store.Open(thumbnailKey(key))
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("delete actor with photo when uploads are disabled", func(t *testing.T) {
+		app := CreateApp(":8080", logger, logger, dbHandler, false, nil, RegistrationPolicy{}, nil)
+		mock.ExpectQuery("DELETE FROM actors").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"photo"}).AddRow("actors/1/photo-old.png"))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodDelete, "/actor/1", nil)
+		r.SetPathValue("id", "1")
+		app.DeleteActor(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("uploads are disabled", func(t *testing.T) {
+		app := CreateApp(":8080", logger, logger, dbHandler, false, nil, RegistrationPolicy{}, nil)
+		w := httptest.NewRecorder()
+		r := multipartImage(t, "/movie/2/poster", poster.Bytes())
+		r.SetPathValue("id", "2")
+		app.UploadMoviePoster(w, r)
+		assert.Equal(t, http.StatusNotImplemented, w.Code)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestThumbnail(t *testing.T) {
+	t.Run("transparent image on white", func(t *testing.T) {
+		src := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+		src.Set(0, 0, color.NRGBA{R: 255, A: 255})
+		src.Set(1, 0, color.NRGBA{R: 255, A: 255})
+		src.Set(0, 1, color.NRGBA{R: 255, A: 255})
+		src.Set(1, 1, color.NRGBA{R: 255, A: 255})
+
+		thumb := thumbnail(src, 2)
+		assert.Equal(t, image.Rect(0, 0, 2, 1), thumb.Bounds())
+		assert.Equal(t, color.RGBA{R: 255, A: 255}, color.RGBAModel.Convert(thumb.At(0, 0)))
+		assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, color.RGBAModel.Convert(thumb.At(1, 0)))
+	})
+
+	t.Run("small image is not enlarged", func(t *testing.T) {
+		thumb := thumbnail(image.NewGray(image.Rect(0, 0, 10, 30)), 320)
+		assert.Equal(t, image.Rect(0, 0, 10, 30), thumb.Bounds())
+	})
+}
@@ -0,0 +1,190 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+)
+
+// errUnknownList - ошибка указания несуществующего личного списка.
+var errUnknownList = &models.FieldError{Field: "list", Message: "list must be one of watchlist, favorites, watched"}
+
+// parseList - получение названия личного списка из пути запроса.
+//
+// Принимает: запрос.
+//
+// Возвращает: название списка и ошибку.
+func parseList(r *http.Request) (string, error) {
+	list := r.PathValue("list")
+	if !models.IsUserList(list) {
+		return "", errUnknownList
+	}
+	return list, nil
+}
+
+// parseWithStatus - получение признака запроса положения фильмов в личных списках из query параметра status.
+//
+// Принимает: запрос.
+//
+// Возвращает: признак и ошибку.
+func parseWithStatus(r *http.Request) (bool, error) {
+	s := r.URL.Query().Get("status")
+	if s == "" {
+		return false, nil
+	}
+	withStatus, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, &models.FieldError{Field: "status", Message: "status must be a boolean"}
+	}
+	return withStatus, nil
+}
+
+// AddToList - обрабатывает http запрос на добавление фильма в личный список текущего пользователя.
+//
+// @Summary      Adds movie to the personal list.
+// @Description  Add movie to the watchlist, favorites or watched list of the authenticated user. For the watched list the date of watching can be set, today by default; adding the movie again updates the date.
+// @Tags         List
+// @Accept       json
+// @Produce      json
+// @Param        list path string true "Personal list" Enums(watchlist, favorites, watched)
+// @Param        id path int true "ID of the movie"
+// @Param        entry body models.ListEntryIn false "Date of watching"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Built-in admin has no personal data"
+// @Failure      404 {object} models.Problem "Movie or user not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /users/me/lists/{list}/{id} [put]
+func (app *App) AddToList(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to add a movie to the list")
+	user := principalFrom(r)
+	list, err := parseList(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	var entry models.ListEntryIn
+	d := json.NewDecoder(r.Body)
+	err = d.Decode(&entry)
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	if err == nil {
+		err = entry.Check(list)
+	}
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+	if list == models.ListWatched && entry.WatchedAt == nil {
+		now := time.Now()
+		entry.WatchedAt = &now
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.dbHandler.AddToList(user.nickname, list, id, entry); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("movie %d is added to %s of user %s\n", id, list, user.nickname)
+}
+
+// RemoveFromList - обрабатывает http запрос на удаление фильма из личного списка текущего пользователя.
+//
+// @Summary      Removes movie from the personal list.
+// @Description  Remove movie from the watchlist, favorites or watched list of the authenticated user.
+// @Tags         List
+// @Produce      json
+// @Param        list path string true "Personal list" Enums(watchlist, favorites, watched)
+// @Param        id path int true "ID of the movie"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Built-in admin has no personal data"
+// @Failure      404 {object} models.Problem "Movie is not in the list"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /users/me/lists/{list}/{id} [delete]
+func (app *App) RemoveFromList(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to remove a movie from the list")
+	user := principalFrom(r)
+	list, err := parseList(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.dbHandler.RemoveFromList(user.nickname, list, id); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("movie %d is removed from %s of user %s\n", id, list, user.nickname)
+}
+
+// GetList - обрабатывает http запрос на получение фильмов личного списка текущего пользователя.
+//
+// @Summary      Get movies of the personal list.
+// @Description  Get movies of the watchlist, favorites or watched list of the authenticated user, most recently watched and added first. Movies contain their status in all personal lists.
+// @Tags         List
+// @Produce      json
+// @Param        list path string true "Personal list" Enums(watchlist, favorites, watched)
+// @Param        limit query int false "Page size, 1 - 500" default(50)
+// @Param        offset query int false "Number of items to skip" default(0)
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {array} models.MovieOut
+// @Header       200 {integer} X-Total-Count "Total number of items"
+// @Header       200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Built-in admin has no personal data"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /users/me/lists/{list} [get]
+func (app *App) GetList(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get movies of the list")
+	user := principalFrom(r)
+	list, err := parseList(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	movies, total, err := app.dbHandler.GetList(user.nickname, list, page)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.fillPosters(movies)
+	app.sendPage(w, r, page, total, movies)
+	app.infoLog.Printf("%s of user %s is getted\n", list, user.nickname)
+}
@@ -0,0 +1,105 @@
+package filmoteka
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLists(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+	dbHandler, _ := postgres.GetHandler(mockDB, false)
+	app := CreateApp(":8080", logger, logger, dbHandler, false, nil, RegistrationPolicy{}, nil)
+
+	asUser := func(r *http.Request) *http.Request {
+		return r.WithContext(context.WithValue(r.Context(), principalKey, principal{nickname: "user"}))
+	}
+	addToList := func(list, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := asUser(httptest.NewRequest(http.MethodPut, "/users/me/lists/"+list+"/15", strings.NewReader(body)))
+		r.SetPathValue("list", list)
+		r.SetPathValue("id", "15")
+		app.AddToList(w, r)
+		return w
+	}
+
+	t.Run("add to watchlist", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO user_movies").WithArgs("user", 15, "watchlist", nil).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		w := addToList("watchlist", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("add to watched today", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO user_movies").WithArgs("user", 15, "watched", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		w := addToList("watched", "{}")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("add to unknown list", func(t *testing.T) {
+		w := addToList("later", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"list"`)
+	})
+
+	t.Run("add to favorites with date of watching", func(t *testing.T) {
+		w := addToList("favorites", `{"watched_at": "2024-03-01T00:00:00Z"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"watched_at"`)
+	})
+
+	t.Run("remove movie not in list", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM user_movies").WithArgs("user", "favorites", 15).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		w := httptest.NewRecorder()
+		r := asUser(httptest.NewRequest(http.MethodDelete, "/users/me/lists/favorites/15", nil))
+		r.SetPathValue("list", "favorites")
+		r.SetPathValue("id", "15")
+		app.RemoveFromList(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "movie is not in the list")
+	})
+
+	t.Run("get movie with status", func(t *testing.T) {
+		watchedAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery("SELECT (.+) FROM movies").WithArgs(15).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(15, "name", "description", time.Time{}, 7))
+		mock.ExpectQuery("FROM movie_actors").WithArgs(15).WillReturnRows(sqlmock.NewRows([]string{"actor_id", "credit"}))
+		mock.ExpectQuery("FROM movie_genres").WithArgs(15).WillReturnRows(sqlmock.NewRows([]string{"genre_id"}))
+		mock.ExpectQuery("FROM user_movies").WithArgs("user", sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"movie_id", "list", "watched_at"}).AddRow(15, "watched", watchedAt))
+
+		w := httptest.NewRecorder()
+		r := asUser(httptest.NewRequest(http.MethodGet, "/movie/15?status=true", nil))
+		r.SetPathValue("id", "15")
+		app.GetMovie(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":{"watchlist":false,"favorite":false,"watched":true,"watched_at":"2024-03-01T00:00:00Z"}`)
+	})
+
+	t.Run("get movie with invalid status", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := asUser(httptest.NewRequest(http.MethodGet, "/movie/15?status=maybe", nil))
+		r.SetPathValue("id", "15")
+		app.GetMovie(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"status"`)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,79 @@
+package filmoteka
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMovieMeta(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+	dbHandler, _ := postgres.GetHandler(mockDB, false)
+	app := CreateApp(":8080", logger, logger, dbHandler, false, nil, RegistrationPolicy{}, nil)
+
+	t.Run("add movie with metadata", func(t *testing.T) {
+		date := time.Date(1972, 3, 20, 0, 0, 0, 0, time.UTC)
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO movies").WithArgs("Солярис", "Кельвин на станции", date, 8).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectExec("UPDATE movies SET runtime").
+			WithArgs(3, 163, pq.StringArray{"SU"}, "ru", pq.StringArray{"ru", "de"}, "12+", 6000000, nil, "SUR").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		w := httptest.NewRecorder()
+		body := `{"name": "Солярис", "description": "Кельвин на станции", "release_date": "1972-03-20T00:00:00Z", "rating": 8,
+			"runtime": 163, "countries": ["SU"], "original_language": "ru", "languages": ["ru", "de"], "age_rating": "12+",
+			"budget": 6000000, "currency": "SUR"}`
+		app.AddMovie(w, httptest.NewRequest(http.MethodPost, "/movie", strings.NewReader(body)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "3", strings.TrimSpace(w.Body.String()))
+	})
+
+	t.Run("add movie with invalid metadata", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		body := `{"name": "Солярис", "description": "Кельвин на станции", "release_date": "1972-03-20T00:00:00Z", "rating": 8,
+			"countries": ["USSR"], "age_rating": "12", "budget": 6000000}`
+		app.AddMovie(w, httptest.NewRequest(http.MethodPost, "/movie", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"countries"`)
+		assert.Contains(t, w.Body.String(), `"field":"age_rating"`)
+		assert.Contains(t, w.Body.String(), `"field":"currency"`)
+	})
+
+	t.Run("get movie with metadata", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM movies").WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating",
+				"runtime", "countries", "original_language", "languages", "age_rating", "budget", "box_office", "currency"}).
+				AddRow(3, "Солярис", "Кельвин на станции", time.Date(1972, 3, 20, 0, 0, 0, 0, time.UTC), 8,
+					163, "{SU}", "ru", "{ru,de}", "12+", 6000000, nil, "SUR"))
+		mock.ExpectQuery("FROM movie_actors").WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"actor_id", "credit"}))
+		mock.ExpectQuery("FROM movie_genres").WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"genre_id"}))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/movie/3", nil)
+		r.SetPathValue("id", "3")
+		app.GetMovie(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id": 3, "name": "Солярис", "description": "Кельвин на станции", "release_date": "1972-03-20T00:00:00Z",
+			"rating": 8, "user_rating": null, "votes": 0, "actors": null, "genres": null, "credits": null, "cast": null,
+			"runtime": 163, "countries": ["SU"], "original_language": "ru", "languages": ["ru", "de"], "age_rating": "12+",
+			"budget": 6000000, "currency": "SUR"}`, w.Body.String())
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
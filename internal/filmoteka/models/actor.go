@@ -7,11 +7,14 @@ import (
 
 // ActorOut - структура, представляющая отправляемого актёра.
 type ActorOut struct {
-	Id          int       `json:"id" db:"id"`                       // Id - id актёра.
-	Name        string    `json:"name" db:"name"`                   // Name - имя актёра.
-	Gender      string    `json:"gender" db:"gender"`               // Gender - пол актёра.
-	DateOfBirth time.Time `json:"date_of_birth" db:"date_of_birth"` // DateOfBirth - дата рождения актёра.
-	Movies      []int     `json:"movies" db:"-"`                    // Movies - список id фильмов, в которых принимал участие актёр.
+	Id          int        `json:"id" db:"id"`                       // Id - id актёра.
+	Name        string     `json:"name" db:"name"`                   // Name - имя актёра.
+	Gender      string     `json:"gender" db:"gender"`               // Gender - пол актёра.
+	DateOfBirth time.Time  `json:"date_of_birth" db:"date_of_birth"` // DateOfBirth - дата рождения актёра.
+	Movies      []int      `json:"movies" db:"-"`                    // Movies - список id фильмов, в которых принимал участие актёр.
+	Roles       []CastRole `json:"roles" db:"-"`                     // Roles - роли актёра в фильмах в порядке титров.
+	PhotoKey    string     `json:"-" db:"photo"`                     // PhotoKey - ключ файла фотографии в хранилище, пустой без фотографии.
+	Photo       *Image     `json:"photo,omitempty" db:"-"`           // Photo - фотография актёра, если она загружена.
 }
 
 // ActorIn - структура, представляющая получаемого актёра.
@@ -21,19 +24,26 @@ type ActorIn struct {
 	DateOfBirth time.Time `json:"date_of_birth" db:"date_of_birth"` // DateOfBirth - дата рождения актёра.
 }
 
+// IsZero - проверка, что ни одно поле актёра не задано.
+//
+// Возвращает: true, если поля не заданы.
+func (a *ActorIn) IsZero() bool {
+	return a.Name == "" && a.Gender == "" && a.DateOfBirth.IsZero()
+}
+
 // Check - проверка корректности данных актёра.
 //
 // Возвращает: ошибку.
 func (a *ActorIn) Check() error {
 	errs := make([]error, 0, 3)
 	if a.Name == "" {
-		errs = append(errs, errors.New("name must not be null"))
+		errs = append(errs, fieldError("name", "name must not be null"))
 	}
 	if a.Gender == "" {
-		errs = append(errs, errors.New("gender must not be null"))
+		errs = append(errs, fieldError("gender", "gender must not be null"))
 	}
 	if a.DateOfBirth.IsZero() {
-		errs = append(errs, errors.New("date of birth must not be null"))
+		errs = append(errs, fieldError("date_of_birth", "date of birth must not be null"))
 	}
 
 	if len(errs) != 0 {
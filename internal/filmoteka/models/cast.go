@@ -0,0 +1,70 @@
+package models
+
+import (
+	"slices"
+	"unicode/utf8"
+)
+
+// CharacterMaxLen - максимальная длина имени персонажа.
+const CharacterMaxLen = 150
+
+// CastMember - структура, представляющая роль актёра в фильме.
+type CastMember struct {
+	ActorId   int    `json:"actor_id" db:"actor_id"`        // ActorId - id актёра.
+	Character string `json:"character" db:"character_name"` // Character - имя персонажа.
+	Billing   int    `json:"billing" db:"billing"`          // Billing - позиция в титрах, начиная с 1 (0 - после заданных позиций по порядку в списке).
+	Cameo     bool   `json:"cameo" db:"cameo"`              // Cameo - является ли роль камео.
+	Voice     bool   `json:"voice" db:"voice"`              // Voice - является ли роль озвучкой.
+}
+
+// CastRole - структура, представляющая роль актёра со стороны актёра.
+type CastRole struct {
+	MovieId   int    `json:"movie_id" db:"movie_id"`        // MovieId - id фильма.
+	Character string `json:"character" db:"character_name"` // Character - имя персонажа.
+	Billing   int    `json:"billing" db:"billing"`          // Billing - позиция в титрах фильма.
+	Cameo     bool   `json:"cameo" db:"cameo"`              // Cameo - является ли роль камео.
+	Voice     bool   `json:"voice" db:"voice"`              // Voice - является ли роль озвучкой.
+}
+
+// checkCast - проверка корректности ролей актёров.
+//
+// Актёры и заданные позиции в титрах не должны повторяться.
+//
+// Возвращает: ошибку первой некорректной роли.
+func checkCast(cast []CastMember) error {
+	for i, c := range cast {
+		if c.ActorId < 1 {
+			return fieldError("cast", "actor ids must be positive")
+		}
+		if slices.ContainsFunc(cast[:i], func(o CastMember) bool { return o.ActorId == c.ActorId }) {
+			return fieldError("cast", "duplicate actor")
+		}
+		if c.Billing < 0 {
+			return fieldError("cast", "billing must not be negative")
+		}
+		if c.Billing > 0 && slices.ContainsFunc(cast[:i], func(o CastMember) bool { return o.Billing == c.Billing }) {
+			return fieldError("cast", "duplicate billing")
+		}
+		if utf8.RuneCountInString(c.Character) > CharacterMaxLen {
+			return fieldError("cast", "character name must be less than 150 chars")
+		}
+	}
+	return nil
+}
+
+// numberBilling - заполнение незаданных позиций в титрах по порядку в списке после наибольшей заданной позиции,
+// чтобы они не совпадали с заданными.
+func numberBilling(cast []CastMember) {
+	next := 1
+	for _, c := range cast {
+		if c.Billing >= next {
+			next = c.Billing + 1
+		}
+	}
+	for i := range cast {
+		if cast[i].Billing == 0 {
+			cast[i].Billing = next
+			next++
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Виды коллекций фильмов.
+const (
+	CollectionKindCollection = "collection" // CollectionKindCollection - подборка фильмов.
+	CollectionKindFranchise  = "franchise"  // CollectionKindFranchise - франшиза.
+)
+
+// CollectionNameMaxLen - максимальная длина названия коллекции в символах.
+const CollectionNameMaxLen = 150
+
+// CollectionDescriptionMaxLen - максимальная длина описания коллекции в символах.
+const CollectionDescriptionMaxLen = 1000
+
+// CollectionOut - структура, представляющая отправляемую коллекцию фильмов.
+type CollectionOut struct {
+	Id          int    `json:"id" db:"id"`                   // Id - id коллекции.
+	Name        string `json:"name" db:"name"`               // Name - название коллекции.
+	Description string `json:"description" db:"description"` // Description - описание коллекции.
+	Kind        string `json:"kind" db:"kind"`               // Kind - вид коллекции: подборка или франшиза.
+	Public      bool   `json:"public" db:"public"`           // Public - видимость коллекции всем пользователям.
+	Owner       string `json:"owner" db:"owner"`             // Owner - никнейм владельца коллекции.
+	Movies      []int  `json:"movies" db:"-"`                // Movies - id фильмов коллекции по порядку.
+}
+
+// CollectionIn - структура, представляющая получаемую коллекцию фильмов.
+type CollectionIn struct {
+	Name        string `json:"name" db:"name"`               // Name - название коллекции.
+	Description string `json:"description" db:"description"` // Description - описание коллекции.
+	Kind        string `json:"kind" db:"kind"`               // Kind - вид коллекции, по умолчанию подборка.
+	Public      *bool  `json:"public" db:"public"`           // Public - видимость коллекции всем пользователям, по умолчанию скрыта.
+	Movies      []int  `json:"movies" db:"-"`                // Movies - id фильмов коллекции по порядку, заменяет фильмы при обновлении.
+}
+
+// Check - проверка корректности данных коллекции.
+//
+// Возвращает: ошибку.
+func (c *CollectionIn) Check() error {
+	errs := make([]error, 0, 2)
+	if c.Name == "" {
+		errs = append(errs, fieldError("name", "name must not be null"))
+	}
+	if err := c.CheckPatch(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// CheckPatch - проверка корректности изменяемых данных коллекции.
+//
+// В отличие от Check, не требует заполнения названия, но название из одних пробелов недопустимо.
+//
+// Возвращает: ошибку.
+func (c *CollectionIn) CheckPatch() error {
+	errs := make([]error, 0, 4)
+	if c.Name != "" && strings.TrimSpace(c.Name) == "" {
+		errs = append(errs, fieldError("name", "name must not be null"))
+	} else if utf8.RuneCountInString(c.Name) > CollectionNameMaxLen {
+		errs = append(errs, fieldError("name", "collection name must be less than 150 chars"))
+	}
+	if utf8.RuneCountInString(c.Description) > CollectionDescriptionMaxLen {
+		errs = append(errs, fieldError("description", "collection description must be less than 1000 chars"))
+	}
+	if c.Kind != "" && c.Kind != CollectionKindCollection && c.Kind != CollectionKindFranchise {
+		errs = append(errs, fieldError("kind", "kind must be one of collection, franchise"))
+	}
+	if err := checkCollectionMovies(c.Movies); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// CollectionMoviesIn - структура, представляющая получаемый упорядоченный список фильмов коллекции.
+type CollectionMoviesIn struct {
+	Movies []int `json:"movies"` // Movies - id фильмов коллекции в новом порядке.
+}
+
+// Check - проверка корректности списка фильмов коллекции.
+//
+// Возвращает: ошибку.
+func (c *CollectionMoviesIn) Check() error {
+	if c.Movies == nil {
+		return fieldError("movies", "movies must not be null")
+	}
+	return checkCollectionMovies(c.Movies)
+}
+
+// checkCollectionMovies - проверка того, что id фильмов коллекции положительны и не повторяются.
+func checkCollectionMovies(movies []int) error {
+	for i, id := range movies {
+		if id <= 0 {
+			return fieldError("movies", "movie ids must be positive")
+		}
+		if slices.Contains(movies[:i], id) {
+			return fieldError("movies", "movie ids must not repeat")
+		}
+	}
+	return nil
+}
+
+// CollectionAccess - структура, представляющая пользователя, обращающегося к коллекциям.
+type CollectionAccess struct {
+	Nickname  string // Nickname - никнейм пользователя.
+	Moderator bool   // Moderator - доступ к чужим, в том числе скрытым, коллекциям.
+}
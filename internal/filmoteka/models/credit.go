@@ -0,0 +1,35 @@
+package models
+
+import "slices"
+
+// Типы участия человека в создании фильма.
+const (
+	// CreditActor - актёр.
+	CreditActor = "actor"
+	// CreditDirector - режиссёр.
+	CreditDirector = "director"
+	// CreditWriter - сценарист.
+	CreditWriter = "writer"
+	// CreditProducer - продюсер.
+	CreditProducer = "producer"
+	// CreditComposer - композитор.
+	CreditComposer = "composer"
+)
+
+// CreditRoles - допустимые типы участия в порядке их вывода.
+var CreditRoles = []string{CreditActor, CreditDirector, CreditWriter, CreditProducer, CreditComposer}
+
+// Credit - структура, представляющая участие человека в создании фильма.
+type Credit struct {
+	PersonId int    `json:"person_id" db:"actor_id"` // PersonId - id человека.
+	Role     string `json:"role" db:"credit"`        // Role - тип участия (actor, director, writer, producer или composer).
+}
+
+// IsCreditRole - проверка, является ли строка допустимым типом участия.
+//
+// Принимает: строку.
+//
+// Возвращает: true, если тип участия допустим.
+func IsCreditRole(role string) bool {
+	return slices.Contains(CreditRoles, role)
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Способы сопоставления актёров фильма с фильтром.
+const (
+	// ActorsMatchAny - в фильме играет хотя бы один из актёров.
+	ActorsMatchAny = "any"
+	// ActorsMatchAll - в фильме играют все актёры.
+	ActorsMatchAll = "all"
+)
+
+// MovieFilter - структура, представляющая фильтр списка фильмов.
+//
+// Пустые поля не ограничивают список, заданные условия объединяются через И.
+type MovieFilter struct {
+	MinRating      *int       // MinRating - минимальный рэйтинг фильма.
+	MaxRating      *int       // MaxRating - максимальный рэйтинг фильма.
+	ReleasedAfter  *time.Time // ReleasedAfter - самая ранняя дата выпуска фильма.
+	ReleasedBefore *time.Time // ReleasedBefore - самая поздняя дата выпуска фильма.
+	Actors         []int      // Actors - список id актёров.
+	ActorsMatch    string     // ActorsMatch - способ сопоставления актёров (any или all).
+	Genres         []int      // Genres - список id жанров, фильм должен относиться хотя бы к одному из них.
+	Name           string     // Name - фрагмент названия фильма.
+	MinRuntime     *int       // MinRuntime - минимальная продолжительность фильма в минутах.
+	MaxRuntime     *int       // MaxRuntime - максимальная продолжительность фильма в минутах.
+	Countries      []string   // Countries - коды стран, фильм должен быть снят хотя бы в одной из них.
+	Languages      []string   // Languages - коды языков, фильм должен быть снят на одном из них или на нём должны говорить в фильме.
+	AgeRatings     []string   // AgeRatings - возрастные рейтинги, фильм должен иметь один из них.
+	Currency       string     // Currency - валюта бюджета и сборов фильма, обязательна для фильтров по бюджету и сборам.
+	MinBudget      *int64     // MinBudget - минимальный бюджет фильма.
+	MaxBudget      *int64     // MaxBudget - максимальный бюджет фильма.
+	MinBoxOffice   *int64     // MinBoxOffice - минимальные сборы фильма.
+	MaxBoxOffice   *int64     // MaxBoxOffice - максимальные сборы фильма.
+}
+
+// Check - проверка корректности фильтра.
+//
+// Возвращает: ошибку.
+func (f *MovieFilter) Check() error {
+	errs := make([]error, 0, 7)
+	if f.MinRating != nil && (*f.MinRating < 0 || *f.MinRating > 10) {
+		errs = append(errs, fieldError("min_rating", "rating must in range 0 - 10"))
+	}
+	if f.MaxRating != nil && (*f.MaxRating < 0 || *f.MaxRating > 10) {
+		errs = append(errs, fieldError("max_rating", "rating must in range 0 - 10"))
+	}
+	if f.MinRating != nil && f.MaxRating != nil && *f.MinRating > *f.MaxRating {
+		errs = append(errs, fieldError("min_rating", "min rating must not be greater than max rating"))
+	}
+	if f.ReleasedAfter != nil && f.ReleasedBefore != nil && f.ReleasedAfter.After(*f.ReleasedBefore) {
+		errs = append(errs, fieldError("released_after", "released_after must not be later than released_before"))
+	}
+	for _, id := range f.Actors {
+		if id < 1 {
+			errs = append(errs, fieldError("actors", "actor ids must be positive"))
+			break
+		}
+	}
+	for _, id := range f.Genres {
+		if id < 1 {
+			errs = append(errs, fieldError("genres", "genre ids must be positive"))
+			break
+		}
+	}
+	if f.ActorsMatch != "" && f.ActorsMatch != ActorsMatchAny && f.ActorsMatch != ActorsMatchAll {
+		errs = append(errs, fieldError("actors_match", "actors_match must be any or all"))
+	}
+	if len(f.Name) > 150 {
+		errs = append(errs, fieldError("name", "movie name must be less than 150 chars"))
+	}
+	if f.MinRuntime != nil && f.MaxRuntime != nil && *f.MinRuntime > *f.MaxRuntime {
+		errs = append(errs, fieldError("min_runtime", "min runtime must not be greater than max runtime"))
+	}
+	for _, c := range f.Countries {
+		if !IsCountryCode(c) {
+			errs = append(errs, fieldError("countries", "countries must be ISO 3166-1 alpha-2 codes, e.g. RU"))
+			break
+		}
+	}
+	for _, l := range f.Languages {
+		if !IsLanguageCode(l) {
+			errs = append(errs, fieldError("languages", "languages must be ISO 639-1 codes, e.g. ru"))
+			break
+		}
+	}
+	for _, a := range f.AgeRatings {
+		if !IsAgeRating(a) {
+			errs = append(errs, fieldError("age_ratings", "age ratings must be 0+, 6+, 12+, 16+ or 18+"))
+			break
+		}
+	}
+	if f.Currency != "" && !IsCurrencyCode(f.Currency) {
+		errs = append(errs, fieldError("currency", "currency must be an ISO 4217 code, e.g. RUB"))
+	}
+	if f.Currency == "" && (f.MinBudget != nil || f.MaxBudget != nil || f.MinBoxOffice != nil || f.MaxBoxOffice != nil) {
+		errs = append(errs, fieldError("currency", "currency must be set together with budget or box office filters"))
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
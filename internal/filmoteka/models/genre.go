@@ -0,0 +1,38 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// GenreNameMaxLen - максимальная длина названия жанра в символах.
+const GenreNameMaxLen = 50
+
+// GenreOut - структура, представляющая отправляемый жанр.
+type GenreOut struct {
+	Id   int    `json:"id" db:"id"`     // Id - id жанра.
+	Name string `json:"name" db:"name"` // Name - название жанра.
+}
+
+// GenreIn - структура, представляющая получаемый жанр.
+type GenreIn struct {
+	Name string `json:"name" db:"name"` // Name - название жанра.
+}
+
+// Check - проверка корректности данных жанра.
+//
+// Возвращает: ошибку.
+func (g *GenreIn) Check() error {
+	errs := make([]error, 0, 1)
+	if strings.TrimSpace(g.Name) == "" {
+		errs = append(errs, fieldError("name", "name must not be null"))
+	} else if utf8.RuneCountInString(g.Name) > GenreNameMaxLen {
+		errs = append(errs, fieldError("name", "genre name must be less than 50 chars"))
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
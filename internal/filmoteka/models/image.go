@@ -0,0 +1,22 @@
+package models
+
+// Ограничения загружаемых изображений.
+const (
+	// ImageMaxSize - максимальный размер загружаемого изображения в байтах.
+	ImageMaxSize = 5 << 20
+	// ThumbnailMaxSide - максимальная длина большей стороны миниатюры в пикселях.
+	ThumbnailMaxSide = 320
+)
+
+// ImageTypes - допустимые типы загружаемых изображений и расширения их файлов.
+var ImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// Image - структура, представляющая изображение вместе с миниатюрой.
+type Image struct {
+	Url          string `json:"url"`           // Url - адрес изображения.
+	ThumbnailUrl string `json:"thumbnail_url"` // ThumbnailUrl - адрес миниатюры в формате JPEG.
+}
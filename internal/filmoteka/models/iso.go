@@ -0,0 +1,138 @@
+package models
+
+import "strings"
+
+// countryCodes - коды стран ISO 3166-1 alpha-2.
+//
+// Кроме действующих кодов, содержит коды бывших стран (SU, YU, CS, DD), в которых сняты многие старые фильмы.
+var countryCodes = codeSet(`
+	AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ
+	BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS BT BV BW BY BZ
+	CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ
+	DE DJ DK DM DO DZ
+	EC EE EG EH ER ES ET
+	FI FJ FK FM FO FR
+	GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY
+	HK HM HN HR HT HU
+	ID IE IL IM IN IO IQ IR IS IT
+	JE JM JO JP
+	KE KG KH KI KM KN KP KR KW KY KZ
+	LA LB LC LI LK LR LS LT LU LV LY
+	MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ
+	NA NC NE NF NG NI NL NO NP NR NU NZ
+	OM
+	PA PE PF PG PH PK PL PM PN PR PS PT PW PY
+	QA
+	RE RO RS RU RW
+	SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ
+	TC TD TF TG TH TJ TK TL TM TN TO TR TT TV TW TZ
+	UA UG UM US UY UZ
+	VA VC VE VG VI VN VU
+	WF WS
+	YE YT
+	ZA ZM ZW
+	SU YU CS DD`)
+
+// languageCodes - коды языков ISO 639-1.
+var languageCodes = codeSet(`
+	aa ab ae af ak am an ar as av ay az
+	ba be bg bh bi bm bn bo br bs
+	ca ce ch co cr cs cu cv cy
+	da de dv dz
+	ee el en eo es et eu
+	fa ff fi fj fo fr fy
+	ga gd gl gn gu gv
+	ha he hi ho hr ht hu hy hz
+	ia id ie ig ii ik io is it iu
+	ja jv
+	ka kg ki kj kk kl km kn ko kr ks ku kv kw ky
+	la lb lg li ln lo lt lu lv
+	mg mh mi mk ml mn mr ms mt my
+	na nb nd ne ng nl nn no nr nv ny
+	oc oj om or os
+	pa pi pl ps pt
+	qu
+	rm rn ro ru rw
+	sa sc sd se sg si sk sl sm sn so sq sr ss st su sv sw
+	ta te tg th ti tk tl tn to tr ts tt tw ty
+	ug uk ur uz
+	ve vi vo
+	wa wo
+	xh
+	yi yo
+	za zh zu`)
+
+// currencyCodes - коды валют ISO 4217.
+//
+// Кроме действующих кодов, содержит коды валют, в которых указаны бюджеты многих старых фильмов (SUR, RUR, DEM, FRF, ITL).
+var currencyCodes = codeSet(`
+	AED AFN ALL AMD ANG AOA ARS AUD AWG AZN
+	BAM BBD BDT BGN BHD BIF BMD BND BOB BRL BSD BTN BWP BYN BZD
+	CAD CDF CHF CLP CNY COP CRC CUP CVE CZK
+	DJF DKK DOP DZD
+	EGP ERN ETB EUR
+	FJD FKP
+	GBP GEL GHS GIP GMD GNF GTQ GYD
+	HKD HNL HTG HUF
+	IDR ILS INR IQD IRR ISK
+	JMD JOD JPY
+	KES KGS KHR KMF KPW KRW KWD KYD KZT
+	LAK LBP LKR LRD LSL LYD
+	MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MYR MZN
+	NAD NGN NIO NOK NPR NZD
+	OMR
+	PAB PEN PGK PHP PKR PLN PYG
+	QAR
+	RON RSD RUB RWF
+	SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN SVC SYP SZL
+	THB TJS TMT TND TOP TRY TTD TWD TZS
+	UAH UGX USD UYU UZS
+	VES VND VUV
+	WST
+	XAF XCD XOF XPF
+	YER
+	ZAR ZMW ZWL
+	SUR RUR DEM FRF ITL`)
+
+// codeSet - получение множества кодов из строки кодов, разделённых пробелами.
+//
+// Принимает: строку кодов.
+//
+// Возвращает: множество кодов.
+func codeSet(codes string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, code := range strings.Fields(codes) {
+		set[code] = struct{}{}
+	}
+	return set
+}
+
+// IsCountryCode - проверка, является ли строка кодом страны ISO 3166-1 alpha-2 в верхнем регистре.
+//
+// Принимает: строку.
+//
+// Возвращает: true, если код страны известен.
+func IsCountryCode(code string) bool {
+	_, ok := countryCodes[code]
+	return ok
+}
+
+// IsLanguageCode - проверка, является ли строка кодом языка ISO 639-1 в нижнем регистре.
+//
+// Принимает: строку.
+//
+// Возвращает: true, если код языка известен.
+func IsLanguageCode(code string) bool {
+	_, ok := languageCodes[code]
+	return ok
+}
+
+// IsCurrencyCode - проверка, является ли строка кодом валюты ISO 4217 в верхнем регистре.
+//
+// Принимает: строку.
+//
+// Возвращает: true, если код валюты известен.
+func IsCurrencyCode(code string) bool {
+	_, ok := currencyCodes[code]
+	return ok
+}
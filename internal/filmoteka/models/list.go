@@ -0,0 +1,71 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Личные списки фильмов пользователя.
+const (
+	ListWatchlist = "watchlist" // ListWatchlist - фильмы, которые пользователь хочет посмотреть.
+	ListFavorites = "favorites" // ListFavorites - любимые фильмы пользователя.
+	ListWatched   = "watched"   // ListWatched - просмотренные пользователем фильмы.
+)
+
+// UserLists - все личные списки фильмов.
+var UserLists = []string{ListWatchlist, ListFavorites, ListWatched}
+
+// IsUserList - проверка того, что строка является названием личного списка.
+//
+// Принимает: название списка.
+//
+// Возвращает: true, если список существует.
+func IsUserList(list string) bool {
+	return slices.Contains(UserLists, list)
+}
+
+// MovieStatus - структура, представляющая положение фильма в личных списках пользователя.
+type MovieStatus struct {
+	Watchlist bool       `json:"watchlist"`            // Watchlist - фильм в списке к просмотру.
+	Favorite  bool       `json:"favorite"`             // Favorite - фильм в избранном.
+	Watched   bool       `json:"watched"`              // Watched - фильм просмотрен.
+	WatchedAt *time.Time `json:"watched_at,omitempty"` // WatchedAt - дата просмотра фильма.
+}
+
+// Set - отметка фильма в списке.
+//
+// Принимает: название списка и дату просмотра (для списка просмотренных).
+func (s *MovieStatus) Set(list string, watchedAt *time.Time) {
+	switch list {
+	case ListWatchlist:
+		s.Watchlist = true
+	case ListFavorites:
+		s.Favorite = true
+	case ListWatched:
+		s.Watched = true
+		s.WatchedAt = watchedAt
+	}
+}
+
+// ListEntryIn - структура, представляющая получаемые данные фильма в личном списке.
+type ListEntryIn struct {
+	WatchedAt *time.Time `json:"watched_at"` // WatchedAt - дата просмотра, только для списка просмотренных, по умолчанию сегодня.
+}
+
+// Check - проверка корректности данных фильма в списке.
+//
+// Принимает: название списка.
+//
+// Возвращает: ошибку.
+func (e *ListEntryIn) Check(list string) error {
+	if e.WatchedAt == nil {
+		return nil
+	}
+	if list != ListWatched {
+		return fieldError("watched_at", "date of watching can be set only for watched list")
+	}
+	if e.WatchedAt.After(time.Now()) {
+		return fieldError("watched_at", "date of watching must not be in the future")
+	}
+	return nil
+}
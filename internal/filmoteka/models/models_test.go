@@ -1,6 +1,8 @@
 package models_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -191,6 +193,49 @@ func TestMovieInCheck(t *testing.T) {
 		assert.Contains(t, err.Error(), expectError)
 	})
 
+	t.Run("valid metadata", func(t *testing.T) {
+		runtime, budget := 163, int64(6000000)
+		movie := models.MovieIn{
+			Name:        "Солярис",
+			Description: "Психолог Крис Кельвин отправляется на станцию над планетой Солярис.",
+			ReleaseDate: time.Date(1972, 3, 20, 0, 0, 0, 0, time.UTC),
+			Rating:      new(int),
+			MovieMeta: models.MovieMeta{
+				Runtime:          &runtime,
+				Countries:        []string{"SU"},
+				OriginalLanguage: "ru",
+				Languages:        []string{"ru", "de"},
+				AgeRating:        models.AgeRating12,
+				Budget:           &budget,
+				Currency:         "SUR",
+			},
+		}
+		assert.NoError(t, movie.Check())
+	})
+
+	t.Run("invalid metadata", func(t *testing.T) {
+		runtime, boxOffice := 0, int64(-1)
+		movie := models.MovieIn{
+			MovieMeta: models.MovieMeta{
+				Runtime:          &runtime,
+				Countries:        []string{"RU", "Russia"},
+				OriginalLanguage: "RU",
+				Languages:        []string{"xx"},
+				AgeRating:        "13+",
+				BoxOffice:        &boxOffice,
+			},
+		}
+		assert.Equal(t, []models.FieldError{
+			{Field: "runtime", Message: "runtime must be in range 1 - 1000 minutes"},
+			{Field: "countries", Message: "countries must be ISO 3166-1 alpha-2 codes, e.g. RU"},
+			{Field: "original_language", Message: "original language must be an ISO 639-1 code, e.g. ru"},
+			{Field: "languages", Message: "languages must be ISO 639-1 codes, e.g. ru"},
+			{Field: "age_rating", Message: "age rating must be one of 0+, 6+, 12+, 16+, 18+"},
+			{Field: "box_office", Message: "box office must not be negative"},
+			{Field: "currency", Message: "currency must be set together with budget or box office"},
+		}, models.FieldErrors(movie.CheckPatch()))
+	})
+
 	t.Run("two errors", func(t *testing.T) {
 		movie := models.MovieIn{
 			Description: "A team of explorers travel through a wormhole in space in an attempt to ensure humanity's survival.",
@@ -221,13 +266,57 @@ func TestMovieInCheck(t *testing.T) {
 func TestUserCheck(t *testing.T) {
 	t.Run("valid user", func(t *testing.T) {
 		user := models.User{
-			Nickname: "JohnDoe",
-			Password: "password",
+			Nickname: "John.Doe_1",
+			Password: "passw0rd",
 		}
 		err := user.Check()
 		assert.NoError(t, err)
 	})
 
+	t.Run("short nickname", func(t *testing.T) {
+		user := models.User{
+			Nickname: "JD",
+			Password: "passw0rd",
+		}
+		err := user.Check()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "name must be from 3 to 32 characters long")
+	})
+
+	t.Run("forbidden characters in nickname", func(t *testing.T) {
+		for _, nickname := range []string{"John Doe", "Джон", "john@doe"} {
+			user := models.User{
+				Nickname: nickname,
+				Password: "passw0rd",
+			}
+			err := user.Check()
+			assert.Error(t, err, nickname)
+			assert.Contains(t, err.Error(), "name may contain only latin letters, digits, '_', '-' and '.'")
+		}
+	})
+
+	t.Run("short password", func(t *testing.T) {
+		user := models.User{
+			Nickname: "JohnDoe",
+			Password: "pa55",
+		}
+		err := user.Check()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "password must be from 8 to 72 bytes long")
+	})
+
+	t.Run("weak password", func(t *testing.T) {
+		for _, password := range []string{"password", "12345678"} {
+			user := models.User{
+				Nickname: "JohnDoe",
+				Password: password,
+			}
+			err := user.Check()
+			assert.Error(t, err, password)
+			assert.Contains(t, err.Error(), "password must contain at least one letter and one digit")
+		}
+	})
+
 	t.Run("missing nickname", func(t *testing.T) {
 		user := models.User{
 			Password: "password",
@@ -254,3 +343,423 @@ func TestUserCheck(t *testing.T) {
 		assert.Contains(t, err.Error(), "password must not be null")
 	})
 }
+
+func TestUserCheckCredentials(t *testing.T) {
+	t.Run("weak credentials are accepted", func(t *testing.T) {
+		user := models.User{Nickname: "admin", Password: "admin"}
+		assert.NoError(t, user.CheckCredentials())
+	})
+
+	t.Run("all fields are missing", func(t *testing.T) {
+		user := models.User{}
+		err := user.CheckCredentials()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "name must not be null")
+		assert.Contains(t, err.Error(), "password must not be null")
+	})
+}
+
+func TestPasswordChangeCheck(t *testing.T) {
+	t.Run("valid change", func(t *testing.T) {
+		change := models.PasswordChange{OldPassword: "old", NewPassword: "n3w-password"}
+		assert.NoError(t, change.Check())
+	})
+
+	t.Run("weak new password", func(t *testing.T) {
+		change := models.PasswordChange{OldPassword: "old", NewPassword: "new"}
+		err := change.Check()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "password must be from 8 to 72 bytes long")
+	})
+
+	t.Run("all fields are missing", func(t *testing.T) {
+		change := models.PasswordChange{}
+		err := change.Check()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "old password must not be null")
+		assert.Contains(t, err.Error(), "new password must not be null")
+	})
+}
+
+func TestUserRoleNames(t *testing.T) {
+	t.Run("roles", func(t *testing.T) {
+		user := models.User{IsAdmin: true, Roles: []string{models.RoleEditor}}
+		assert.Equal(t, []string{models.RoleEditor}, user.RoleNames())
+	})
+
+	t.Run("admin flag", func(t *testing.T) {
+		user := models.User{IsAdmin: true}
+		assert.Equal(t, []string{models.RoleAdmin}, user.RoleNames())
+	})
+
+	t.Run("default", func(t *testing.T) {
+		user := models.User{}
+		assert.Equal(t, []string{models.RoleViewer}, user.RoleNames())
+	})
+}
+
+func TestUserPatchRoleNames(t *testing.T) {
+	isAdmin := false
+	assert.Equal(t, []string{models.RoleViewer}, (&models.UserPatch{IsAdmin: &isAdmin}).RoleNames())
+	assert.Equal(t, []string{}, (&models.UserPatch{IsAdmin: &isAdmin, Roles: []string{}}).RoleNames())
+	assert.Nil(t, (&models.UserPatch{}).RoleNames())
+}
+
+func TestRegistrationUser(t *testing.T) {
+	reg := models.Registration{Nickname: "JohnDoe", Password: "passw0rd", InviteCode: "code"}
+	assert.Equal(t, models.User{Nickname: "JohnDoe", Password: "passw0rd", Roles: []string{models.RoleViewer}}, reg.User())
+}
+
+func TestMovieInCheckPatch(t *testing.T) {
+	t.Run("empty patch", func(t *testing.T) {
+		movie := models.MovieIn{}
+		assert.NoError(t, movie.CheckPatch())
+	})
+
+	t.Run("invalid fields", func(t *testing.T) {
+		rating := 11
+		movie := models.MovieIn{Description: strings.Repeat("a", 1001), Rating: &rating}
+		err := movie.CheckPatch()
+		assert.Equal(t, []models.FieldError{
+			{Field: "description", Message: "movie description must be less than 1000 chars"},
+			{Field: "rating", Message: "rating must in range 0 - 10"},
+		}, models.FieldErrors(err))
+	})
+
+	t.Run("invalid credits", func(t *testing.T) {
+		movie := models.MovieIn{Credits: []models.Credit{{PersonId: 1, Role: "cameraman"}}}
+		assert.Equal(t, []models.FieldError{
+			{Field: "credits", Message: "credit role must be one of actor, director, writer, producer, composer"},
+		}, models.FieldErrors(movie.CheckPatch()))
+
+		movie = models.MovieIn{Credits: []models.Credit{{Role: models.CreditDirector}}}
+		assert.Equal(t, []models.FieldError{
+			{Field: "credits", Message: "person ids must be positive"},
+		}, models.FieldErrors(movie.CheckPatch()))
+	})
+
+	t.Run("invalid cast", func(t *testing.T) {
+		movie := models.MovieIn{Actors: []int{1}, Cast: []models.CastMember{{ActorId: 1, Billing: -1}}}
+		assert.Equal(t, []models.FieldError{
+			{Field: "cast", Message: "cast must not be set together with actors"},
+			{Field: "cast", Message: "billing must not be negative"},
+		}, models.FieldErrors(movie.CheckPatch()))
+
+		movie = models.MovieIn{Cast: []models.CastMember{{ActorId: 1, Character: strings.Repeat("я", models.CharacterMaxLen+1)}}}
+		assert.Equal(t, []models.FieldError{
+			{Field: "cast", Message: "character name must be less than 150 chars"},
+		}, models.FieldErrors(movie.CheckPatch()))
+
+		movie = models.MovieIn{Cast: []models.CastMember{{ActorId: 1, Character: "Нео"}, {ActorId: 1, Character: "Томас Андерсон"}}}
+		assert.Equal(t, []models.FieldError{
+			{Field: "cast", Message: "duplicate actor"},
+		}, models.FieldErrors(movie.CheckPatch()))
+
+		movie = models.MovieIn{Cast: []models.CastMember{{ActorId: 1, Billing: 2}, {ActorId: 2}, {ActorId: 3, Billing: 2}}}
+		assert.Equal(t, []models.FieldError{
+			{Field: "cast", Message: "duplicate billing"},
+		}, models.FieldErrors(movie.CheckPatch()))
+	})
+}
+
+func TestMovieInCastList(t *testing.T) {
+	t.Run("from actors and credits", func(t *testing.T) {
+		movie := models.MovieIn{
+			Actors: []int{1, 2},
+			Credits: []models.Credit{
+				{PersonId: 3, Role: models.CreditDirector},
+				{PersonId: 2, Role: models.CreditActor},
+				{PersonId: 4, Role: models.CreditActor},
+			},
+		}
+		assert.Equal(t, []models.CastMember{{ActorId: 1, Billing: 1}, {ActorId: 2, Billing: 2}, {ActorId: 4, Billing: 3}}, movie.CastList())
+	})
+
+	t.Run("from cast", func(t *testing.T) {
+		movie := models.MovieIn{Cast: []models.CastMember{
+			{ActorId: 5, Character: "Нео", Billing: 2},
+			{ActorId: 6, Character: "Агент Смит", Cameo: true},
+		}}
+		assert.Equal(t, []models.CastMember{
+			{ActorId: 5, Character: "Нео", Billing: 2},
+			{ActorId: 6, Character: "Агент Смит", Billing: 3, Cameo: true},
+		}, movie.CastList())
+		assert.True(t, movie.HasCast())
+	})
+
+	t.Run("unset billing after explicit", func(t *testing.T) {
+		movie := models.MovieIn{Cast: []models.CastMember{{ActorId: 5}, {ActorId: 6, Billing: 1}, {ActorId: 7}}}
+		assert.Equal(t, []models.CastMember{{ActorId: 5, Billing: 2}, {ActorId: 6, Billing: 1}, {ActorId: 7, Billing: 3}}, movie.CastList())
+	})
+}
+
+func TestMovieInCrewList(t *testing.T) {
+	movie := models.MovieIn{
+		Actors: []int{1},
+		Credits: []models.Credit{
+			{PersonId: 3, Role: models.CreditDirector},
+			{PersonId: 1, Role: models.CreditActor},
+			{PersonId: 3, Role: models.CreditWriter},
+			{PersonId: 3, Role: models.CreditDirector},
+		},
+	}
+	assert.Equal(t, []models.Credit{{PersonId: 3, Role: models.CreditDirector}, {PersonId: 3, Role: models.CreditWriter}}, movie.CrewList())
+}
+
+func TestFieldErrors(t *testing.T) {
+	t.Run("nested", func(t *testing.T) {
+		user := models.User{Nickname: "JD", Password: "password"}
+		assert.Equal(t, []models.FieldError{
+			{Field: "nickname", Message: "name must be from 3 to 32 characters long"},
+			{Field: "password", Message: "password must contain at least one letter and one digit"},
+		}, models.FieldErrors(errors.Join(errors.New("wrap"), user.Check())))
+	})
+
+	t.Run("no field errors", func(t *testing.T) {
+		assert.Nil(t, models.FieldErrors(errors.New("error")))
+		assert.Nil(t, models.FieldErrors(nil))
+	})
+}
+
+func TestPageCheck(t *testing.T) {
+	t.Run("valid page", func(t *testing.T) {
+		page := models.Page{Limit: models.MaxPageLimit, Offset: 10}
+		assert.NoError(t, page.Check())
+	})
+
+	t.Run("invalid page", func(t *testing.T) {
+		page := models.Page{Limit: models.MaxPageLimit + 1, Offset: -1}
+		assert.Equal(t, []models.FieldError{
+			{Field: "limit", Message: "limit must be in range 1 - 500"},
+			{Field: "offset", Message: "offset must not be negative"},
+		}, models.FieldErrors(page.Check()))
+	})
+
+	t.Run("zero limit", func(t *testing.T) {
+		page := models.Page{}
+		assert.Len(t, models.FieldErrors(page.Check()), 1)
+	})
+}
+
+func TestSearchQueryCheck(t *testing.T) {
+	t.Run("valid query", func(t *testing.T) {
+		query := models.SearchQuery{Query: "матрица OR matrix", Limit: models.DefaultSearchLimit}
+		assert.NoError(t, query.Check())
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		query := models.SearchQuery{Query: strings.Repeat("я", models.SearchQueryMaxLen+1), Limit: models.MaxSearchLimit + 1}
+		assert.Equal(t, []models.FieldError{
+			{Field: "q", Message: "search query must be less than 200 chars"},
+			{Field: "limit", Message: "limit must be in range 1 - 50"},
+		}, models.FieldErrors(query.Check()))
+	})
+
+	t.Run("blank query", func(t *testing.T) {
+		query := models.SearchQuery{Query: " ", Limit: 1}
+		assert.Equal(t, []models.FieldError{{Field: "q", Message: "search query must not be empty"}}, models.FieldErrors(query.Check()))
+	})
+}
+
+func TestNameSearchCheck(t *testing.T) {
+	t.Run("valid search", func(t *testing.T) {
+		search := models.NameSearch{Name: "Bezrukov", Similarity: 1}
+		assert.NoError(t, search.Check())
+	})
+
+	t.Run("invalid search", func(t *testing.T) {
+		search := models.NameSearch{Name: " ", Similarity: 0}
+		assert.Equal(t, []models.FieldError{
+			{Field: "name", Message: "name must not be empty"},
+			{Field: "similarity", Message: "similarity must be in range (0, 1]"},
+		}, models.FieldErrors(search.Check()))
+	})
+}
+
+func TestSuggestQueryCheck(t *testing.T) {
+	t.Run("valid query", func(t *testing.T) {
+		query := models.SuggestQuery{Prefix: "Bra", Type: models.SuggestActor, Limit: 1}
+		assert.NoError(t, query.Check())
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		query := models.SuggestQuery{Prefix: strings.Repeat("a", models.SuggestPrefixMaxLen+1), Type: "series", Limit: 0}
+		assert.Equal(t, []models.FieldError{
+			{Field: "prefix", Message: "prefix must be less than 100 chars"},
+			{Field: "type", Message: "type must be actor or movie"},
+			{Field: "limit", Message: "limit must be in range 1 - 50"},
+		}, models.FieldErrors(query.Check()))
+	})
+}
+
+func TestMovieFilterCheck(t *testing.T) {
+	t.Run("empty filter", func(t *testing.T) {
+		filter := models.MovieFilter{}
+		assert.NoError(t, filter.Check())
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		minRating, maxRating := -1, 11
+		after, before := time.Now(), time.Now().AddDate(-1, 0, 0)
+		filter := models.MovieFilter{
+			MinRating:      &minRating,
+			MaxRating:      &maxRating,
+			ReleasedAfter:  &after,
+			ReleasedBefore: &before,
+			Actors:         []int{1, 0},
+			Genres:         []int{-2},
+			Name:           strings.Repeat("a", 151),
+		}
+		assert.Equal(t, []models.FieldError{
+			{Field: "min_rating", Message: "rating must in range 0 - 10"},
+			{Field: "max_rating", Message: "rating must in range 0 - 10"},
+			{Field: "released_after", Message: "released_after must not be later than released_before"},
+			{Field: "actors", Message: "actor ids must be positive"},
+			{Field: "genres", Message: "genre ids must be positive"},
+			{Field: "name", Message: "movie name must be less than 150 chars"},
+		}, models.FieldErrors(filter.Check()))
+	})
+}
+
+func TestParseSort(t *testing.T) {
+	t.Run("directions", func(t *testing.T) {
+		sort, err := models.ParseSort("-rating, +name,release", models.MovieSortFields)
+		assert.NoError(t, err)
+		assert.Equal(t, models.Sort{{Field: "rating", Desc: true}, {Field: "name"}, {Field: "release_date"}}, sort)
+		assert.Equal(t, "-rating,name,release_date", sort.String())
+	})
+
+	t.Run("invalid fields", func(t *testing.T) {
+		_, err := models.ParseSort("budget,,name,-name", models.MovieSortFields)
+		assert.Equal(t, []models.FieldError{
+			{Field: "sort", Message: `unknown sort field "budget", expected one of id, name, release_date, rating`},
+			{Field: "sort", Message: "sort field must not be empty"},
+			{Field: "sort", Message: `sort field "name" is repeated`},
+		}, models.FieldErrors(err))
+	})
+}
+
+func TestGenreInCheck(t *testing.T) {
+	t.Run("valid genre", func(t *testing.T) {
+		genre := models.GenreIn{Name: "Драма"}
+		assert.NoError(t, genre.Check())
+	})
+
+	t.Run("empty name", func(t *testing.T) {
+		genre := models.GenreIn{Name: " "}
+		assert.Equal(t, []models.FieldError{{Field: "name", Message: "name must not be null"}}, models.FieldErrors(genre.Check()))
+	})
+
+	t.Run("long name", func(t *testing.T) {
+		genre := models.GenreIn{Name: strings.Repeat("ж", models.GenreNameMaxLen+1)}
+		assert.Equal(t, []models.FieldError{{Field: "name", Message: "genre name must be less than 50 chars"}}, models.FieldErrors(genre.Check()))
+	})
+}
+
+func TestReviewInCheck(t *testing.T) {
+	t.Run("valid review", func(t *testing.T) {
+		rating := 0
+		review := models.ReviewIn{Rating: &rating}
+		assert.NoError(t, review.Check())
+	})
+
+	t.Run("no rating", func(t *testing.T) {
+		review := models.ReviewIn{Text: "Отличный фильм"}
+		assert.Equal(t, []models.FieldError{{Field: "rating", Message: "rating must not be null"}}, models.FieldErrors(review.Check()))
+	})
+
+	t.Run("rating out of range and long text", func(t *testing.T) {
+		rating := 11
+		review := models.ReviewIn{Rating: &rating, Text: strings.Repeat("ж", models.ReviewTextMaxLen+1)}
+		assert.Equal(t, []models.FieldError{
+			{Field: "rating", Message: "rating must in range 0 - 10"},
+			{Field: "text", Message: "review text must be less than 5000 chars"},
+		}, models.FieldErrors(review.Check()))
+	})
+}
+
+func TestListEntryInCheck(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+	tomorrow := time.Now().AddDate(0, 0, 1)
+
+	t.Run("valid entries", func(t *testing.T) {
+		entry := models.ListEntryIn{}
+		assert.NoError(t, entry.Check(models.ListWatchlist))
+		entry.WatchedAt = &yesterday
+		assert.NoError(t, entry.Check(models.ListWatched))
+	})
+
+	t.Run("date of watching for another list", func(t *testing.T) {
+		entry := models.ListEntryIn{WatchedAt: &yesterday}
+		assert.Equal(t, []models.FieldError{{Field: "watched_at", Message: "date of watching can be set only for watched list"}}, models.FieldErrors(entry.Check(models.ListFavorites)))
+	})
+
+	t.Run("date of watching in the future", func(t *testing.T) {
+		entry := models.ListEntryIn{WatchedAt: &tomorrow}
+		assert.Equal(t, []models.FieldError{{Field: "watched_at", Message: "date of watching must not be in the future"}}, models.FieldErrors(entry.Check(models.ListWatched)))
+	})
+}
+
+func TestCollectionInCheck(t *testing.T) {
+	t.Run("valid collection", func(t *testing.T) {
+		collection := models.CollectionIn{Name: "Marvel", Kind: models.CollectionKindFranchise, Movies: []int{3, 1}}
+		assert.NoError(t, collection.Check())
+	})
+
+	t.Run("invalid collection", func(t *testing.T) {
+		collection := models.CollectionIn{Name: " ", Kind: "series", Movies: []int{3, 3}}
+		assert.Equal(t, []models.FieldError{
+			{Field: "name", Message: "name must not be null"},
+			{Field: "kind", Message: "kind must be one of collection, franchise"},
+			{Field: "movies", Message: "movie ids must not repeat"},
+		}, models.FieldErrors(collection.Check()))
+	})
+
+	t.Run("patch without name", func(t *testing.T) {
+		collection := models.CollectionIn{Description: "Фильмы студии"}
+		assert.NoError(t, collection.CheckPatch())
+		assert.Equal(t, []models.FieldError{{Field: "name", Message: "name must not be null"}}, models.FieldErrors(collection.Check()))
+	})
+
+	t.Run("no movies to reorder", func(t *testing.T) {
+		movies := models.CollectionMoviesIn{}
+		assert.Equal(t, []models.FieldError{{Field: "movies", Message: "movies must not be null"}}, models.FieldErrors(movies.Check()))
+	})
+
+	t.Run("invalid movie id", func(t *testing.T) {
+		movies := models.CollectionMoviesIn{Movies: []int{1, 0}}
+		assert.Equal(t, []models.FieldError{{Field: "movies", Message: "movie ids must be positive"}}, models.FieldErrors(movies.Check()))
+	})
+}
+
+func TestSeriesCheck(t *testing.T) {
+	rating := 11
+	t.Run("invalid series", func(t *testing.T) {
+		series := models.SeriesIn{Name: "Dark", Rating: &rating}
+		assert.Equal(t, []models.FieldError{
+			{Field: "description", Message: "description must not be null"},
+			{Field: "release_date", Message: "date of release must not be null"},
+			{Field: "rating", Message: "rating must in range 0 - 10"},
+		}, models.FieldErrors(series.Check()))
+	})
+
+	t.Run("season without number", func(t *testing.T) {
+		season := models.SeasonIn{}
+		assert.Equal(t, []models.FieldError{{Field: "number", Message: "number must not be null"}}, models.FieldErrors(season.Check()))
+		assert.NoError(t, season.CheckPatch())
+	})
+
+	t.Run("episode patch with invalid cast", func(t *testing.T) {
+		episode := models.EpisodeIn{Number: -1, Cast: []models.CastMember{{ActorId: 0}}}
+		assert.Equal(t, []models.FieldError{
+			{Field: "number", Message: "number must be positive"},
+			{Field: "cast", Message: "actor ids must be positive"},
+		}, models.FieldErrors(episode.CheckPatch()))
+	})
+
+	t.Run("episode cast billing", func(t *testing.T) {
+		episode := models.EpisodeIn{Cast: []models.CastMember{{ActorId: 4}, {ActorId: 6, Billing: 5}}}
+		assert.Equal(t, []models.CastMember{{ActorId: 4, Billing: 6}, {ActorId: 6, Billing: 5}}, episode.CastList())
+		assert.Equal(t, 0, episode.Cast[0].Billing)
+	})
+}
@@ -2,26 +2,80 @@ package models
 
 import (
 	"errors"
+	"slices"
 	"time"
+
+	"github.com/lib/pq"
+)
+
+// Возрастные рейтинги фильмов.
+const (
+	AgeRating0  = "0+"  // AgeRating0 - для любой зрительской аудитории.
+	AgeRating6  = "6+"  // AgeRating6 - для детей старше 6 лет.
+	AgeRating12 = "12+" // AgeRating12 - для детей старше 12 лет.
+	AgeRating16 = "16+" // AgeRating16 - для детей старше 16 лет.
+	AgeRating18 = "18+" // AgeRating18 - запрещено для детей.
 )
 
+// AgeRatings - допустимые возрастные рейтинги по возрастанию возраста.
+var AgeRatings = []string{AgeRating0, AgeRating6, AgeRating12, AgeRating16, AgeRating18}
+
+// MovieMaxRuntime - максимальная продолжительность фильма в минутах.
+const MovieMaxRuntime = 1000
+
+// IsAgeRating - проверка, является ли строка допустимым возрастным рейтингом.
+//
+// Принимает: строку.
+//
+// Возвращает: true, если возрастной рейтинг допустим.
+func IsAgeRating(rating string) bool {
+	return slices.Contains(AgeRatings, rating)
+}
+
 // MovieOut - структура, представляющая отправляемый фильм.
 type MovieOut struct {
-	Id          int       `json:"id" db:"id"`                     // Id - id фильма.
-	Name        string    `json:"name" db:"name"`                 // Name - название фильма.
-	Description string    `json:"description" db:"description"`   // Description - описание фильма.
-	ReleaseDate time.Time `json:"release_date" db:"release_date"` // ReleaseDate - дата выпуска фильма.
-	Rating      int       `json:"rating" db:"rating"`             // Rating - рэйтинг фильма.
-	Actors      []int     `json:"actors" db:"-"`                  // Actors - список id актёров, принимавших участие в фильме.
+	Id          int          `json:"id" db:"id"`                     // Id - id фильма.
+	Name        string       `json:"name" db:"name"`                 // Name - название фильма.
+	Description string       `json:"description" db:"description"`   // Description - описание фильма.
+	ReleaseDate time.Time    `json:"release_date" db:"release_date"` // ReleaseDate - дата выпуска фильма.
+	Rating      int          `json:"rating" db:"rating"`             // Rating - рэйтинг фильма.
+	UserRating  *float64     `json:"user_rating" db:"user_rating"`   // UserRating - средняя оценка фильма пользователями, null без оценок.
+	Votes       int          `json:"votes" db:"votes"`               // Votes - количество оценок фильма пользователями.
+	Actors      []int        `json:"actors" db:"-"`                  // Actors - список id актёров, принимавших участие в фильме.
+	Genres      []int        `json:"genres" db:"-"`                  // Genres - список id жанров фильма.
+	Credits     []Credit     `json:"credits" db:"-"`                 // Credits - список участников фильма, включая актёров.
+	Cast        []CastMember `json:"cast" db:"-"`                    // Cast - роли актёров фильма в порядке титров.
+	Status      *MovieStatus `json:"status,omitempty" db:"-"`        // Status - положение фильма в списках текущего пользователя, если запрошено.
+	PosterKey   string       `json:"-" db:"poster"`                  // PosterKey - ключ файла постера в хранилище, пустой без постера.
+	Poster      *Image       `json:"poster,omitempty" db:"-"`        // Poster - постер фильма, если он загружен.
+	MovieMeta
+}
+
+// MovieMeta - структура, представляющая дополнительные сведения о фильме.
+//
+// Незаданные сведения не отправляются.
+type MovieMeta struct {
+	Runtime          *int           `json:"runtime,omitempty" db:"runtime"`                                // Runtime - продолжительность фильма в минутах.
+	Countries        pq.StringArray `json:"countries,omitempty" db:"countries" swaggertype:"array,string"` // Countries - страны производства (ISO 3166-1 alpha-2).
+	OriginalLanguage string         `json:"original_language,omitempty" db:"original_language"`            // OriginalLanguage - язык оригинала (ISO 639-1).
+	Languages        pq.StringArray `json:"languages,omitempty" db:"languages" swaggertype:"array,string"` // Languages - языки, на которых говорят в фильме (ISO 639-1).
+	AgeRating        string         `json:"age_rating,omitempty" db:"age_rating"`                          // AgeRating - возрастной рейтинг (0+, 6+, 12+, 16+ или 18+).
+	Budget           *int64         `json:"budget,omitempty" db:"budget"`                                  // Budget - бюджет фильма в валюте Currency.
+	BoxOffice        *int64         `json:"box_office,omitempty" db:"box_office"`                          // BoxOffice - кассовые сборы фильма в валюте Currency.
+	Currency         string         `json:"currency,omitempty" db:"currency"`                              // Currency - валюта бюджета и сборов (ISO 4217).
 }
 
 // MovieIn - структура, представляющая получаемый фильм.
 type MovieIn struct {
-	Name        string    `json:"name" db:"name"`                 // Name - название фильма.
-	Description string    `json:"description" db:"description"`   // Description - описание фильма.
-	ReleaseDate time.Time `json:"release_date" db:"release_date"` // ReleaseDate - дата выпуска фильма.
-	Rating      *int      `json:"rating" db:"rating"`             // Rating - рэйтинг фильма.
-	Actors      []int     `json:"actors" db:"-"`                  // Actors - список id актёров, принимавших участие в фильме.
+	Name        string       `json:"name" db:"name"`                 // Name - название фильма.
+	Description string       `json:"description" db:"description"`   // Description - описание фильма.
+	ReleaseDate time.Time    `json:"release_date" db:"release_date"` // ReleaseDate - дата выпуска фильма.
+	Rating      *int         `json:"rating" db:"rating"`             // Rating - рэйтинг фильма.
+	Actors      []int        `json:"actors" db:"-"`                  // Actors - список id актёров, принимавших участие в фильме.
+	Genres      []int        `json:"genres" db:"-"`                  // Genres - список id жанров фильма.
+	Credits     []Credit     `json:"credits" db:"-"`                 // Credits - список участников фильма любых типов, дополняет Actors.
+	Cast        []CastMember `json:"cast" db:"-"`                    // Cast - роли актёров фильма, заменяет Actors.
+	MovieMeta
 }
 
 // Check - проверка корректности данных фильма.
@@ -30,24 +84,166 @@ type MovieIn struct {
 func (m *MovieIn) Check() error {
 	errs := make([]error, 0, 3)
 	if m.Name == "" {
-		errs = append(errs, errors.New("name must not be null"))
-	}
-	if len(m.Name) > 150 {
-		errs = append(errs, errors.New("movie name must be less than 150 chars"))
+		errs = append(errs, fieldError("name", "name must not be null"))
 	}
 	if m.Description == "" {
-		errs = append(errs, errors.New("description must not be null"))
-	}
-	if len(m.Description) > 1000 {
-		errs = append(errs, errors.New("movie description must be less than 1000 chars"))
+		errs = append(errs, fieldError("description", "description must not be null"))
 	}
 	if m.ReleaseDate.IsZero() {
-		errs = append(errs, errors.New("date of release must not be null"))
+		errs = append(errs, fieldError("release_date", "date of release must not be null"))
 	}
 	if m.Rating == nil {
-		errs = append(errs, errors.New("rating must not be null"))
-	} else if *m.Rating < 0 || *m.Rating > 10 {
-		errs = append(errs, errors.New("rating must in range 0 - 10"))
+		errs = append(errs, fieldError("rating", "rating must not be null"))
+	}
+	if err := m.CheckPatch(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// CheckPatch - проверка корректности изменяемых данных фильма.
+//
+// В отличие от Check, не требует заполнения всех полей.
+//
+// Возвращает: ошибку.
+func (m *MovieIn) CheckPatch() error {
+	errs := make([]error, 0, 3)
+	if len(m.Name) > 150 {
+		errs = append(errs, fieldError("name", "movie name must be less than 150 chars"))
+	}
+	if len(m.Description) > 1000 {
+		errs = append(errs, fieldError("description", "movie description must be less than 1000 chars"))
+	}
+	if m.Rating != nil && (*m.Rating < 0 || *m.Rating > 10) {
+		errs = append(errs, fieldError("rating", "rating must in range 0 - 10"))
+	}
+	if m.Cast != nil && m.Actors != nil {
+		errs = append(errs, fieldError("cast", "cast must not be set together with actors"))
+	}
+	if err := checkCast(m.Cast); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.MovieMeta.Check(); err != nil {
+		errs = append(errs, err)
+	}
+	for _, c := range m.Credits {
+		if !IsCreditRole(c.Role) {
+			errs = append(errs, fieldError("credits", "credit role must be one of actor, director, writer, producer, composer"))
+			break
+		}
+		if c.PersonId < 1 {
+			errs = append(errs, fieldError("credits", "person ids must be positive"))
+			break
+		}
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// CastList - получение ролей актёров фильма.
+//
+// Роли берутся из Cast, а если он не задан - из Actors. Актёры из Credits, которых нет в списке, добавляются в конец.
+// Незаданные позиции в титрах идут по порядку в списке после заданных.
+//
+// Возвращает: список ролей.
+func (m *MovieIn) CastList() []CastMember {
+	cast := slices.Clone(m.Cast)
+	if cast == nil {
+		for _, id := range m.Actors {
+			cast = append(cast, CastMember{ActorId: id})
+		}
+	}
+	for _, c := range m.Credits {
+		if c.Role == CreditActor && !slices.ContainsFunc(cast, func(a CastMember) bool { return a.ActorId == c.PersonId }) {
+			cast = append(cast, CastMember{ActorId: c.PersonId})
+		}
+	}
+	numberBilling(cast)
+	return cast
+}
+
+// CrewList - получение участников фильма, кроме актёров, без повторов.
+//
+// Возвращает: список участников.
+func (m *MovieIn) CrewList() []Credit {
+	crew := make([]Credit, 0, len(m.Credits))
+	for _, c := range m.Credits {
+		if c.Role != CreditActor && !slices.Contains(crew, c) {
+			crew = append(crew, c)
+		}
+	}
+	return crew
+}
+
+// IsZero - проверка, что ни одно поле фильма не задано.
+//
+// Возвращает: true, если поля не заданы.
+func (m *MovieIn) IsZero() bool {
+	return m.Name == "" && m.Description == "" && m.ReleaseDate.IsZero() && m.Rating == nil &&
+		m.Genres == nil && m.Credits == nil && !m.HasCast() && m.MovieMeta.IsZero()
+}
+
+// HasCast - проверка, заданы ли актёры фильма.
+//
+// Возвращает: true, если задано поле Cast или Actors.
+func (m *MovieIn) HasCast() bool {
+	return m.Cast != nil || m.Actors != nil
+}
+
+// IsZero - проверка, что ни одно из дополнительных сведений о фильме не задано.
+//
+// Возвращает: true, если сведения не заданы.
+func (m *MovieMeta) IsZero() bool {
+	return m.Runtime == nil && m.Countries == nil && m.OriginalLanguage == "" && m.Languages == nil &&
+		m.AgeRating == "" && m.Budget == nil && m.BoxOffice == nil && m.Currency == ""
+}
+
+// Check - проверка корректности дополнительных сведений о фильме.
+//
+// Все сведения необязательны, но бюджет и сборы должны указываться вместе с валютой.
+//
+// Возвращает: ошибку.
+func (m *MovieMeta) Check() error {
+	errs := make([]error, 0, 3)
+	if m.Runtime != nil && (*m.Runtime < 1 || *m.Runtime > MovieMaxRuntime) {
+		errs = append(errs, fieldError("runtime", "runtime must be in range 1 - 1000 minutes"))
+	}
+	for _, c := range m.Countries {
+		if !IsCountryCode(c) {
+			errs = append(errs, fieldError("countries", "countries must be ISO 3166-1 alpha-2 codes, e.g. RU"))
+			break
+		}
+	}
+	if m.OriginalLanguage != "" && !IsLanguageCode(m.OriginalLanguage) {
+		errs = append(errs, fieldError("original_language", "original language must be an ISO 639-1 code, e.g. ru"))
+	}
+	for _, l := range m.Languages {
+		if !IsLanguageCode(l) {
+			errs = append(errs, fieldError("languages", "languages must be ISO 639-1 codes, e.g. ru"))
+			break
+		}
+	}
+	if m.AgeRating != "" && !IsAgeRating(m.AgeRating) {
+		errs = append(errs, fieldError("age_rating", "age rating must be one of 0+, 6+, 12+, 16+, 18+"))
+	}
+	if m.Budget != nil && *m.Budget < 0 {
+		errs = append(errs, fieldError("budget", "budget must not be negative"))
+	}
+	if m.BoxOffice != nil && *m.BoxOffice < 0 {
+		errs = append(errs, fieldError("box_office", "box office must not be negative"))
+	}
+	if m.Currency != "" && !IsCurrencyCode(m.Currency) {
+		errs = append(errs, fieldError("currency", "currency must be an ISO 4217 code, e.g. RUB"))
+	}
+	if m.Currency == "" && (m.Budget != nil || m.BoxOffice != nil) {
+		errs = append(errs, fieldError("currency", "currency must be set together with budget or box office"))
 	}
 
 	if len(errs) != 0 {
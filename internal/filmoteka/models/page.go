@@ -0,0 +1,38 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Ограничения размера страницы списка.
+const (
+	// DefaultPageLimit - размер страницы по умолчанию.
+	DefaultPageLimit = 50
+	// MaxPageLimit - максимальный размер страницы.
+	MaxPageLimit = 500
+)
+
+// Page - структура, представляющая запрашиваемую страницу списка.
+type Page struct {
+	Limit  int // Limit - максимальное количество элементов на странице.
+	Offset int // Offset - количество пропускаемых элементов.
+}
+
+// Check - проверка корректности параметров страницы.
+//
+// Возвращает: ошибку.
+func (p *Page) Check() error {
+	errs := make([]error, 0, 2)
+	if p.Limit < 1 || p.Limit > MaxPageLimit {
+		errs = append(errs, fieldError("limit", fmt.Sprintf("limit must be in range 1 - %d", MaxPageLimit)))
+	}
+	if p.Offset < 0 {
+		errs = append(errs, fieldError("offset", "offset must not be negative"))
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
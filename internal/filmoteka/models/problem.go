@@ -0,0 +1,54 @@
+package models
+
+import "errors"
+
+// Problem - структура, представляющая описание ошибки запроса (RFC 7807).
+type Problem struct {
+	Type      string       `json:"type"`                 // Type - URI типа ошибки.
+	Title     string       `json:"title"`                // Title - краткое описание типа ошибки.
+	Status    int          `json:"status"`               // Status - http-статус ответа.
+	Detail    string       `json:"detail,omitempty"`     // Detail - описание ошибки для клиента.
+	Instance  string       `json:"instance,omitempty"`   // Instance - путь запроса, вызвавшего ошибку.
+	RequestId string       `json:"request_id,omitempty"` // RequestId - id запроса, по которому ошибку можно найти в логах.
+	Errors    []FieldError `json:"errors,omitempty"`     // Errors - ошибки валидации отдельных полей.
+}
+
+// FieldError - структура, представляющая ошибку валидации поля.
+type FieldError struct {
+	Field   string `json:"field"`   // Field - название поля в json.
+	Message string `json:"message"` // Message - описание ошибки.
+}
+
+// Error - текст ошибки.
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// fieldError - создание ошибки валидации поля.
+func fieldError(field, message string) error {
+	return &FieldError{Field: field, Message: message}
+}
+
+// FieldErrors - получение ошибок валидации полей.
+//
+// Принимает: ошибку, в том числе объединённую через errors.Join.
+//
+// Возвращает: все ошибки валидации полей в порядке их появления.
+func FieldErrors(err error) []FieldError {
+	var fe *FieldError
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case interface{ Unwrap() []error }:
+		var res []FieldError
+		for _, inner := range e.Unwrap() {
+			res = append(res, FieldErrors(inner)...)
+		}
+		return res
+	default:
+		if errors.As(err, &fe) {
+			return []FieldError{*fe}
+		}
+		return nil
+	}
+}
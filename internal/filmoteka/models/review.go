@@ -0,0 +1,47 @@
+package models
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+)
+
+// ReviewTextMaxLen - максимальная длина текста отзыва в символах.
+const ReviewTextMaxLen = 5000
+
+// ReviewOut - структура, представляющая отправляемый отзыв пользователя о фильме.
+type ReviewOut struct {
+	Id        int       `json:"id" db:"id"`                 // Id - id отзыва.
+	MovieId   int       `json:"movie_id" db:"movie_id"`     // MovieId - id фильма.
+	Nickname  string    `json:"nickname" db:"nickname"`     // Nickname - никнейм автора отзыва.
+	Rating    int       `json:"rating" db:"rating"`         // Rating - оценка фильма автором.
+	Text      string    `json:"text" db:"text"`             // Text - текст отзыва, пустой, если автор только оценил фильм.
+	CreatedAt time.Time `json:"created_at" db:"created_at"` // CreatedAt - время создания отзыва.
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"` // UpdatedAt - время последнего изменения отзыва.
+}
+
+// ReviewIn - структура, представляющая получаемый отзыв пользователя о фильме.
+type ReviewIn struct {
+	Rating *int   `json:"rating" db:"rating"` // Rating - оценка фильма.
+	Text   string `json:"text" db:"text"`     // Text - необязательный текст отзыва.
+}
+
+// Check - проверка корректности данных отзыва.
+//
+// Возвращает: ошибку.
+func (r *ReviewIn) Check() error {
+	errs := make([]error, 0, 2)
+	if r.Rating == nil {
+		errs = append(errs, fieldError("rating", "rating must not be null"))
+	} else if *r.Rating < 0 || *r.Rating > 10 {
+		errs = append(errs, fieldError("rating", "rating must in range 0 - 10"))
+	}
+	if utf8.RuneCountInString(r.Text) > ReviewTextMaxLen {
+		errs = append(errs, fieldError("text", "review text must be less than 5000 chars"))
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package models
+
+// Права пользователей.
+const (
+	// PermCatalogRead - право просматривать фильмы и актёров.
+	PermCatalogRead = "catalog:read"
+	// PermCatalogWrite - право изменять фильмы и актёров.
+	PermCatalogWrite = "catalog:write"
+	// PermUsersManage - право управлять пользователями.
+	PermUsersManage = "users:manage"
+)
+
+// Роли пользователей.
+const (
+	// RoleViewer - зритель, может только просматривать фильмотеку.
+	RoleViewer = "viewer"
+	// RoleEditor - редактор фильмов и актёров.
+	RoleEditor = "editor"
+	// RoleUserAdmin - администратор пользователей.
+	RoleUserAdmin = "user_admin"
+	// RoleAdmin - администратор, обладающий всеми правами.
+	RoleAdmin = "admin"
+)
+
+// Role - структура, представляющая роль пользователя.
+type Role struct {
+	Name        string   `json:"name" db:"name"`     // Name - название роли.
+	Permissions []string `json:"permissions" db:"-"` // Permissions - список прав роли.
+}
+
+// rolesByAdminFlag - получение ролей по устаревшему флагу администратора.
+func rolesByAdminFlag(isAdmin bool) []string {
+	if isAdmin {
+		return []string{RoleAdmin}
+	}
+	return []string{RoleViewer}
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Ограничения поискового запроса.
+const (
+	// SearchQueryMaxLen - максимальная длина поискового запроса в символах.
+	SearchQueryMaxLen = 200
+	// DefaultSearchLimit - количество результатов каждого типа по умолчанию.
+	DefaultSearchLimit = 10
+	// MaxSearchLimit - максимальное количество результатов каждого типа.
+	MaxSearchLimit = 50
+	// DefaultSimilarity - порог схожести имён по умолчанию.
+	DefaultSimilarity = 0.4
+	// SuggestPrefixMaxLen - максимальная длина префикса подсказок в символах.
+	SuggestPrefixMaxLen = 100
+)
+
+// Типы подсказок.
+const (
+	// SuggestActor - подсказки актёров.
+	SuggestActor = "actor"
+	// SuggestMovie - подсказки фильмов.
+	SuggestMovie = "movie"
+)
+
+// NameSearch - структура, представляющая нечёткий поиск по имени актёра или названию фильма.
+type NameSearch struct {
+	Name       string  // Name - искомое имя или его фрагмент.
+	Similarity float64 // Similarity - минимальная триграммная схожесть имени с запросом от 0 до 1.
+}
+
+// Check - проверка корректности поиска по имени.
+//
+// Возвращает: ошибку.
+func (n *NameSearch) Check() error {
+	errs := make([]error, 0, 2)
+	if strings.TrimSpace(n.Name) == "" {
+		errs = append(errs, fieldError("name", "name must not be empty"))
+	} else if utf8.RuneCountInString(n.Name) > SearchQueryMaxLen {
+		errs = append(errs, fieldError("name", fmt.Sprintf("name must be less than %d chars", SearchQueryMaxLen)))
+	}
+	if n.Similarity <= 0 || n.Similarity > 1 {
+		errs = append(errs, fieldError("similarity", "similarity must be in range (0, 1]"))
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// SearchQuery - структура, представляющая поисковый запрос.
+type SearchQuery struct {
+	Query string // Query - текст запроса.
+	Limit int    // Limit - максимальное количество результатов каждого типа.
+}
+
+// Check - проверка корректности поискового запроса.
+//
+// Возвращает: ошибку.
+func (q *SearchQuery) Check() error {
+	errs := make([]error, 0, 2)
+	if strings.TrimSpace(q.Query) == "" {
+		errs = append(errs, fieldError("q", "search query must not be empty"))
+	} else if utf8.RuneCountInString(q.Query) > SearchQueryMaxLen {
+		errs = append(errs, fieldError("q", fmt.Sprintf("search query must be less than %d chars", SearchQueryMaxLen)))
+	}
+	if q.Limit < 1 || q.Limit > MaxSearchLimit {
+		errs = append(errs, fieldError("limit", fmt.Sprintf("limit must be in range 1 - %d", MaxSearchLimit)))
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// MovieHit - структура, представляющая найденный фильм.
+type MovieHit struct {
+	Id                   int       `json:"id" db:"id"`                                       // Id - id фильма.
+	Name                 string    `json:"name" db:"name"`                                   // Name - название фильма.
+	ReleaseDate          time.Time `json:"release_date" db:"release_date"`                   // ReleaseDate - дата выпуска фильма.
+	Rating               int       `json:"rating" db:"rating"`                               // Rating - рэйтинг фильма.
+	Rank                 float64   `json:"rank" db:"rank"`                                   // Rank - релевантность фильма запросу.
+	NameHighlight        string    `json:"name_highlight" db:"name_highlight"`               // NameHighlight - экранированное для HTML название с выделенными тегами <b></b> совпадениями.
+	DescriptionHighlight string    `json:"description_highlight" db:"description_highlight"` // DescriptionHighlight - экранированные для HTML фрагменты описания с выделенными совпадениями.
+}
+
+// ActorHit - структура, представляющая найденного актёра.
+type ActorHit struct {
+	Id            int     `json:"id" db:"id"`                         // Id - id актёра.
+	Name          string  `json:"name" db:"name"`                     // Name - имя актёра.
+	Rank          float64 `json:"rank" db:"rank"`                     // Rank - релевантность актёра запросу.
+	NameHighlight string  `json:"name_highlight" db:"name_highlight"` // NameHighlight - экранированное для HTML имя с выделенными тегами <b></b> совпадениями.
+}
+
+// SearchResult - структура, представляющая результаты поиска, отсортированные по убыванию релевантности.
+type SearchResult struct {
+	Movies []MovieHit `json:"movies"` // Movies - найденные фильмы.
+	Actors []ActorHit `json:"actors"` // Actors - найденные актёры.
+}
+
+// SuggestQuery - структура, представляющая запрос подсказок.
+type SuggestQuery struct {
+	Prefix string // Prefix - начало имени или слова в имени.
+	Type   string // Type - тип подсказок (actor или movie).
+	Limit  int    // Limit - максимальное количество подсказок.
+}
+
+// Check - проверка корректности запроса подсказок.
+//
+// Возвращает: ошибку.
+func (q *SuggestQuery) Check() error {
+	errs := make([]error, 0, 3)
+	if strings.TrimSpace(q.Prefix) == "" {
+		errs = append(errs, fieldError("prefix", "prefix must not be empty"))
+	} else if utf8.RuneCountInString(q.Prefix) > SuggestPrefixMaxLen {
+		errs = append(errs, fieldError("prefix", fmt.Sprintf("prefix must be less than %d chars", SuggestPrefixMaxLen)))
+	}
+	if q.Type != SuggestActor && q.Type != SuggestMovie {
+		errs = append(errs, fieldError("type", "type must be actor or movie"))
+	}
+	if q.Limit < 1 || q.Limit > MaxSearchLimit {
+		errs = append(errs, fieldError("limit", fmt.Sprintf("limit must be in range 1 - %d", MaxSearchLimit)))
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// Suggestion - структура, представляющая подсказку.
+type Suggestion struct {
+	Id   int    `json:"id" db:"id"`     // Id - id актёра или фильма.
+	Name string `json:"name" db:"name"` // Name - имя актёра или название фильма.
+}
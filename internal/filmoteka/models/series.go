@@ -0,0 +1,210 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"time"
+	"unicode/utf8"
+)
+
+// SeriesOut - структура, представляющая отправляемый сериал.
+type SeriesOut struct {
+	Id          int         `json:"id" db:"id"`                     // Id - id сериала.
+	Name        string      `json:"name" db:"name"`                 // Name - название сериала.
+	Description string      `json:"description" db:"description"`   // Description - описание сериала.
+	ReleaseDate time.Time   `json:"release_date" db:"release_date"` // ReleaseDate - дата премьеры сериала.
+	Rating      int         `json:"rating" db:"rating"`             // Rating - рэйтинг сериала.
+	Seasons     []SeasonOut `json:"seasons,omitempty" db:"-"`       // Seasons - сезоны сериала по номерам, только при получении одного сериала.
+}
+
+// SeriesIn - структура, представляющая получаемый сериал.
+type SeriesIn struct {
+	Name        string    `json:"name" db:"name"`                 // Name - название сериала.
+	Description string    `json:"description" db:"description"`   // Description - описание сериала.
+	ReleaseDate time.Time `json:"release_date" db:"release_date"` // ReleaseDate - дата премьеры сериала.
+	Rating      *int      `json:"rating" db:"rating"`             // Rating - рэйтинг сериала.
+}
+
+// Check - проверка корректности данных сериала.
+//
+// Возвращает: ошибку.
+func (s *SeriesIn) Check() error {
+	errs := make([]error, 0, 4)
+	if s.Name == "" {
+		errs = append(errs, fieldError("name", "name must not be null"))
+	}
+	if s.Description == "" {
+		errs = append(errs, fieldError("description", "description must not be null"))
+	}
+	if s.ReleaseDate.IsZero() {
+		errs = append(errs, fieldError("release_date", "date of release must not be null"))
+	}
+	if s.Rating == nil {
+		errs = append(errs, fieldError("rating", "rating must not be null"))
+	}
+	if err := s.CheckPatch(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// CheckPatch - проверка корректности изменяемых данных сериала.
+//
+// В отличие от Check, не требует заполнения всех полей.
+//
+// Возвращает: ошибку.
+func (s *SeriesIn) CheckPatch() error {
+	errs := make([]error, 0, 3)
+	if utf8.RuneCountInString(s.Name) > 150 {
+		errs = append(errs, fieldError("name", "series name must be less than 150 chars"))
+	}
+	if utf8.RuneCountInString(s.Description) > 1000 {
+		errs = append(errs, fieldError("description", "series description must be less than 1000 chars"))
+	}
+	if s.Rating != nil && (*s.Rating < 0 || *s.Rating > 10) {
+		errs = append(errs, fieldError("rating", "rating must in range 0 - 10"))
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// SeasonOut - структура, представляющая отправляемый сезон сериала.
+type SeasonOut struct {
+	Id       int          `json:"id" db:"id"`               // Id - id сезона.
+	SeriesId int          `json:"series_id" db:"series_id"` // SeriesId - id сериала.
+	Number   int          `json:"number" db:"number"`       // Number - номер сезона в сериале.
+	Name     string       `json:"name" db:"name"`           // Name - название сезона, может быть пустым.
+	Episodes []EpisodeOut `json:"episodes" db:"-"`          // Episodes - серии сезона по номерам.
+}
+
+// SeasonIn - структура, представляющая получаемый сезон сериала.
+type SeasonIn struct {
+	Number int    `json:"number" db:"number"` // Number - номер сезона в сериале, начиная с 1.
+	Name   string `json:"name" db:"name"`     // Name - необязательное название сезона.
+}
+
+// Check - проверка корректности данных сезона.
+//
+// Возвращает: ошибку.
+func (s *SeasonIn) Check() error {
+	if s.Number == 0 {
+		return errors.Join(fieldError("number", "number must not be null"), s.CheckPatch())
+	}
+	return s.CheckPatch()
+}
+
+// CheckPatch - проверка корректности изменяемых данных сезона.
+//
+// В отличие от Check, не требует заполнения номера.
+//
+// Возвращает: ошибку.
+func (s *SeasonIn) CheckPatch() error {
+	errs := make([]error, 0, 2)
+	if s.Number < 0 {
+		errs = append(errs, fieldError("number", "number must be positive"))
+	}
+	if utf8.RuneCountInString(s.Name) > 150 {
+		errs = append(errs, fieldError("name", "season name must be less than 150 chars"))
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// EpisodeOut - структура, представляющая отправляемую серию сезона.
+type EpisodeOut struct {
+	Id          int          `json:"id" db:"id"`                     // Id - id серии.
+	SeasonId    int          `json:"season_id" db:"season_id"`       // SeasonId - id сезона.
+	Number      int          `json:"number" db:"number"`             // Number - номер серии в сезоне.
+	Name        string       `json:"name" db:"name"`                 // Name - название серии.
+	Description string       `json:"description" db:"description"`   // Description - описание серии.
+	ReleaseDate time.Time    `json:"release_date" db:"release_date"` // ReleaseDate - дата выхода серии.
+	Rating      int          `json:"rating" db:"rating"`             // Rating - рэйтинг серии.
+	Cast        []CastMember `json:"cast" db:"-"`                    // Cast - приглашённые актёры серии в порядке титров.
+}
+
+// EpisodeIn - структура, представляющая получаемую серию сезона.
+type EpisodeIn struct {
+	Number      int          `json:"number" db:"number"`             // Number - номер серии в сезоне, начиная с 1.
+	Name        string       `json:"name" db:"name"`                 // Name - название серии.
+	Description string       `json:"description" db:"description"`   // Description - необязательное описание серии.
+	ReleaseDate time.Time    `json:"release_date" db:"release_date"` // ReleaseDate - дата выхода серии.
+	Rating      *int         `json:"rating" db:"rating"`             // Rating - рэйтинг серии.
+	Cast        []CastMember `json:"cast" db:"-"`                    // Cast - приглашённые актёры серии, заменяет актёров при обновлении.
+}
+
+// Check - проверка корректности данных серии.
+//
+// Возвращает: ошибку.
+func (e *EpisodeIn) Check() error {
+	errs := make([]error, 0, 5)
+	if e.Number == 0 {
+		errs = append(errs, fieldError("number", "number must not be null"))
+	}
+	if e.Name == "" {
+		errs = append(errs, fieldError("name", "name must not be null"))
+	}
+	if e.ReleaseDate.IsZero() {
+		errs = append(errs, fieldError("release_date", "date of release must not be null"))
+	}
+	if e.Rating == nil {
+		errs = append(errs, fieldError("rating", "rating must not be null"))
+	}
+	if err := e.CheckPatch(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// CheckPatch - проверка корректности изменяемых данных серии.
+//
+// В отличие от Check, не требует заполнения всех полей.
+//
+// Возвращает: ошибку.
+func (e *EpisodeIn) CheckPatch() error {
+	errs := make([]error, 0, 5)
+	if e.Number < 0 {
+		errs = append(errs, fieldError("number", "number must be positive"))
+	}
+	if utf8.RuneCountInString(e.Name) > 150 {
+		errs = append(errs, fieldError("name", "episode name must be less than 150 chars"))
+	}
+	if utf8.RuneCountInString(e.Description) > 1000 {
+		errs = append(errs, fieldError("description", "episode description must be less than 1000 chars"))
+	}
+	if e.Rating != nil && (*e.Rating < 0 || *e.Rating > 10) {
+		errs = append(errs, fieldError("rating", "rating must in range 0 - 10"))
+	}
+	if err := checkCast(e.Cast); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// CastList - получение приглашённых актёров серии.
+//
+// Незаданные позиции в титрах идут по порядку в списке после заданных.
+//
+// Возвращает: список ролей.
+func (e *EpisodeIn) CastList() []CastMember {
+	cast := slices.Clone(e.Cast)
+	numberBilling(cast)
+	return cast
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Session - структура, представляющая сессию пользователя.
+type Session struct {
+	Id        string    `json:"-" db:"id"`                  // Id - id сессии.
+	Nickname  string    `json:"nickname" db:"nickname"`     // Nickname - никнейм (логин) пользователя.
+	Superuser bool      `json:"superuser" db:"superuser"`   // Superuser - флаг сессии базового администратора (-default_admin), обладающего всеми правами.
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"` // ExpiresAt - время истечения сессии.
+}
+
+// Tokens - структура, представляющая выдаваемые пользователю токены.
+type Tokens struct {
+	AccessToken  string `json:"access_token"`            // AccessToken - токен доступа.
+	RefreshToken string `json:"refresh_token,omitempty"` // RefreshToken - токен обновления.
+	TokenType    string `json:"token_type"`              // TokenType - тип токена (Bearer).
+	ExpiresIn    int    `json:"expires_in"`              // ExpiresIn - время жизни токена доступа в секундах.
+}
+
+// RefreshRequest - структура, представляющая запрос на обновление токена доступа.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"` // RefreshToken - токен обновления.
+}
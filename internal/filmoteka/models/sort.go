@@ -1,11 +1,91 @@
 package models
 
-// Указатели на тип сортировки.
-const (
-	// SortByRating - сортировка по рэйтингу.
-	SortByRating = iota
-	// SortByName - сортировка по названию.
-	SortByName
-	// SortByName - сортировка по дате релиза.
-	SortByReleaseDate
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 )
+
+// Поля сортировки списков.
+var (
+	// MovieSortFields - поля, по которым можно сортировать фильмы.
+	MovieSortFields = []string{"id", "name", "release_date", "rating"}
+	// ActorSortFields - поля, по которым можно сортировать актёров.
+	ActorSortFields = []string{"id", "name", "gender", "date_of_birth"}
+	// SeriesSortFields - поля, по которым можно сортировать сериалы.
+	SeriesSortFields = []string{"id", "name", "release_date", "rating"}
+)
+
+// Сортировки списков по умолчанию.
+var (
+	// DefaultMovieSort - сортировка фильмов по умолчанию: по убыванию рэйтинга.
+	DefaultMovieSort = Sort{{Field: "rating", Desc: true}}
+	// DefaultActorSort - сортировка актёров по умолчанию: по id.
+	DefaultActorSort = Sort{{Field: "id"}}
+	// DefaultFilmographySort - сортировка фильмографии по умолчанию: по дате выпуска.
+	DefaultFilmographySort = Sort{{Field: "release_date"}}
+	// DefaultSeriesSort - сортировка сериалов по умолчанию: по убыванию рэйтинга.
+	DefaultSeriesSort = Sort{{Field: "rating", Desc: true}}
+)
+
+// sortAliases - устаревшие названия полей сортировки.
+var sortAliases = map[string]string{
+	"release": "release_date",
+}
+
+// SortKey - структура, представляющая ключ сортировки.
+type SortKey struct {
+	Field string // Field - поле сортировки.
+	Desc  bool   // Desc - сортировка по убыванию.
+}
+
+// Sort - сортировка по нескольким ключам в порядке их приоритета.
+type Sort []SortKey
+
+// String - запись сортировки в формате query параметра sort.
+func (s Sort) String() string {
+	keys := make([]string, len(s))
+	for i, key := range s {
+		keys[i] = key.Field
+		if key.Desc {
+			keys[i] = "-" + key.Field
+		}
+	}
+	return strings.Join(keys, ",")
+}
+
+// ParseSort - разбор сортировки.
+//
+// Принимает: ключи через запятую (знак - перед полем означает сортировку по убыванию) и допустимые поля.
+//
+// Возвращает: сортировку и ошибку.
+func ParseSort(s string, fields []string) (Sort, error) {
+	keys := strings.Split(s, ",")
+	res := make(Sort, 0, len(keys))
+	errs := make([]error, 0)
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		desc := strings.HasPrefix(key, "-")
+		field := strings.TrimPrefix(strings.TrimPrefix(key, "-"), "+")
+		if alias, ok := sortAliases[field]; ok {
+			field = alias
+		}
+
+		switch {
+		case field == "":
+			errs = append(errs, fieldError("sort", "sort field must not be empty"))
+		case !slices.Contains(fields, field):
+			errs = append(errs, fieldError("sort", fmt.Sprintf("unknown sort field %q, expected one of %s", field, strings.Join(fields, ", "))))
+		case slices.ContainsFunc(res, func(k SortKey) bool { return k.Field == field }):
+			errs = append(errs, fieldError("sort", fmt.Sprintf("sort field %q is repeated", field)))
+		default:
+			res = append(res, SortKey{Field: field, Desc: desc})
+		}
+	}
+
+	if len(errs) != 0 {
+		return nil, errors.Join(errs...)
+	}
+	return res, nil
+}
@@ -1,29 +1,180 @@
 package models
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
 
-// User - структура, представляющая пользователя.
+// Ограничения на никнейм и пароль пользователя.
+const (
+	// NicknameMinLen - минимальная длина никнейма.
+	NicknameMinLen = 3
+	// NicknameMaxLen - максимальная длина никнейма.
+	NicknameMaxLen = 32
+	// PasswordMinLen - минимальная длина пароля.
+	PasswordMinLen = 8
+	// PasswordMaxLen - максимальная длина пароля в байтах (ограничение bcrypt).
+	PasswordMaxLen = 72
+)
+
+// User - структура, представляющая получаемого пользователя.
 type User struct {
-	Id       int    `json:"-" db:"id"`              // Id - id пользователя.
-	Nickname string `json:"nickname" db:"nickname"` // Nickname - никнейм (логин) пользователя.
-	Password string `json:"password" db:"password"` // Password - пароль пользователя.
-	IsAdmin  bool   `json:"is_admin" db:"is_admin"` // IsAdmin - флаг, указывающий на то, является ли пользователь администратором.
+	Id       int      `json:"-" db:"id"`              // Id - id пользователя.
+	Nickname string   `json:"nickname" db:"nickname"` // Nickname - никнейм (логин) пользователя.
+	Password string   `json:"password" db:"password"` // Password - пароль пользователя.
+	IsAdmin  bool     `json:"is_admin" db:"is_admin"` // IsAdmin - устаревший флаг администратора, используется, если роли не указаны.
+	Roles    []string `json:"roles" db:"-"`           // Roles - список ролей пользователя.
 }
 
-// Check - проверка корректности данных пользователя.
+// RoleNames - получение ролей пользователя.
+//
+// Возвращает: указанные роли, а если их нет - роль, соответствующую флагу IsAdmin.
+func (u *User) RoleNames() []string {
+	if len(u.Roles) != 0 {
+		return u.Roles
+	}
+	return rolesByAdminFlag(u.IsAdmin)
+}
+
+// Check - проверка корректности данных нового пользователя.
+//
+// Никнейм должен быть длиной от NicknameMinLen до NicknameMaxLen символов и состоять из латинских букв, цифр, '_', '-' и '.'.
+// Пароль должен быть длиной от PasswordMinLen до PasswordMaxLen байт и содержать хотя бы одну букву и одну цифру.
 //
 // Возвращает: ошибку.
 func (u *User) Check() error {
-	errs := make([]error, 0, 3)
+	if err := u.CheckCredentials(); err != nil {
+		return err
+	}
+
+	errs := make([]error, 0, 2)
+	if err := checkNickname(u.Nickname); err != nil {
+		errs = append(errs, fieldError("nickname", err.Error()))
+	}
+	if err := checkPassword(u.Password); err != nil {
+		errs = append(errs, fieldError("password", err.Error()))
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// CheckCredentials - проверка наличия никнейма и пароля пользователя.
+//
+// Используется при входе, чтобы пользователи, созданные до введения правил, могли авторизоваться.
+//
+// Возвращает: ошибку.
+func (u *User) CheckCredentials() error {
+	errs := make([]error, 0, 2)
 	if u.Nickname == "" {
-		errs = append(errs, errors.New("name must not be null"))
+		errs = append(errs, fieldError("nickname", "name must not be null"))
 	}
 	if u.Password == "" {
-		errs = append(errs, errors.New("password must not be null"))
+		errs = append(errs, fieldError("password", "password must not be null"))
+	}
+
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// checkNickname - проверка длины и символов никнейма.
+func checkNickname(nickname string) error {
+	if n := len([]rune(nickname)); n < NicknameMinLen || n > NicknameMaxLen {
+		return fmt.Errorf("name must be from %d to %d characters long", NicknameMinLen, NicknameMaxLen)
 	}
+	for _, r := range nickname {
+		alnum := r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+		if !alnum && !strings.ContainsRune("_-.", r) {
+			return errors.New("name may contain only latin letters, digits, '_', '-' and '.'")
+		}
+	}
+	return nil
+}
+
+// checkPassword - проверка длины и сложности пароля.
+func checkPassword(password string) error {
+	if len(password) < PasswordMinLen || len(password) > PasswordMaxLen {
+		return fmt.Errorf("password must be from %d to %d bytes long", PasswordMinLen, PasswordMaxLen)
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		hasLetter = hasLetter || unicode.IsLetter(r)
+		hasDigit = hasDigit || unicode.IsDigit(r)
+	}
+	if !hasLetter || !hasDigit {
+		return errors.New("password must contain at least one letter and one digit")
+	}
+	return nil
+}
+
+// UserOut - структура, представляющая отправляемого пользователя (без пароля).
+type UserOut struct {
+	Id       int      `json:"id" db:"id"`             // Id - id пользователя.
+	Nickname string   `json:"nickname" db:"nickname"` // Nickname - никнейм (логин) пользователя.
+	Roles    []string `json:"roles" db:"-"`           // Roles - список ролей пользователя.
+}
+
+// UserPatch - структура, представляющая изменения пользователя.
+type UserPatch struct {
+	IsAdmin *bool    `json:"is_admin"` // IsAdmin - устаревший флаг администратора, используется, если роли не указаны.
+	Roles   []string `json:"roles"`    // Roles - новый список ролей пользователя.
+}
+
+// RoleNames - получение новых ролей пользователя.
+//
+// Возвращает: указанные роли, роль, соответствующую флагу IsAdmin, или nil, если роли не меняются.
+func (p *UserPatch) RoleNames() []string {
+	switch {
+	case p.Roles != nil:
+		return p.Roles
+	case p.IsAdmin != nil:
+		return rolesByAdminFlag(*p.IsAdmin)
+	default:
+		return nil
+	}
+}
 
+// PasswordChange - структура, представляющая запрос на смену пароля.
+type PasswordChange struct {
+	OldPassword string `json:"old_password"` // OldPassword - текущий пароль пользователя.
+	NewPassword string `json:"new_password"` // NewPassword - новый пароль пользователя.
+}
+
+// Check - проверка корректности запроса на смену пароля.
+//
+// Возвращает: ошибку.
+func (p *PasswordChange) Check() error {
+	errs := make([]error, 0, 2)
+	if p.OldPassword == "" {
+		errs = append(errs, fieldError("old_password", "old password must not be null"))
+	}
+	if p.NewPassword == "" {
+		errs = append(errs, fieldError("new_password", "new password must not be null"))
+	} else if err := checkPassword(p.NewPassword); err != nil {
+		errs = append(errs, fieldError("new_password", err.Error()))
+	}
 	if len(errs) != 0 {
 		return errors.Join(errs...)
 	}
 	return nil
 }
+
+// Registration - структура, представляющая запрос на самостоятельную регистрацию.
+type Registration struct {
+	Nickname   string `json:"nickname"`    // Nickname - никнейм (логин) пользователя.
+	Password   string `json:"password"`    // Password - пароль пользователя.
+	InviteCode string `json:"invite_code"` // InviteCode - код приглашения, нужен, если регистрация только по приглашениям.
+}
+
+// User - получение нового пользователя из запроса на регистрацию.
+//
+// Возвращает: пользователя с ролью viewer.
+func (r *Registration) User() User {
+	return User{Nickname: r.Nickname, Password: r.Password, Roles: []string{RoleViewer}}
+}
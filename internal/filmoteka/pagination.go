@@ -0,0 +1,93 @@
+package filmoteka
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+)
+
+// totalCountHeader - заголовок ответа с общим количеством элементов списка.
+const totalCountHeader = "X-Total-Count"
+
+// parsePage - получение страницы списка из query параметров limit и offset запроса.
+//
+// Принимает: запрос.
+//
+// Возвращает: страницу и ошибку.
+func parsePage(r *http.Request) (models.Page, error) {
+	page := models.Page{Limit: models.DefaultPageLimit}
+	query := r.URL.Query()
+	errs := make([]error, 0, 2)
+	if s := query.Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil {
+			errs = append(errs, &models.FieldError{Field: "limit", Message: "limit must be an integer"})
+		}
+		page.Limit = limit
+	}
+	if s := query.Get("offset"); s != "" {
+		offset, err := strconv.Atoi(s)
+		if err != nil {
+			errs = append(errs, &models.FieldError{Field: "offset", Message: "offset must be an integer"})
+		}
+		page.Offset = offset
+	}
+	if len(errs) != 0 {
+		return models.Page{}, errors.Join(errs...)
+	}
+
+	if err := page.Check(); err != nil {
+		return models.Page{}, err
+	}
+	return page, nil
+}
+
+// sendPage - отправка страницы списка в формате JSON вместе с метаданными пагинации.
+//
+// Принимает: http.ResponseWriter, запрос, страницу, общее количество элементов и элементы страницы.
+//
+// В заголовке X-Total-Count передаётся общее количество элементов,
+// в заголовке Link - ссылки на первую, предыдущую, следующую и последнюю страницы.
+func (app *App) sendPage(w http.ResponseWriter, r *http.Request, page models.Page, total int, obj any) {
+	w.Header().Set(totalCountHeader, strconv.Itoa(total))
+	if link := pageLinks(r.URL, page, total); link != "" {
+		w.Header().Set("Link", link)
+	}
+	app.sendJson(w, r, obj)
+}
+
+// pageLinks - формирование значения заголовка Link (RFC 8288) для страницы списка.
+//
+// Принимает: URL запроса, страницу и общее количество элементов.
+//
+// Возвращает: значение заголовка.
+func pageLinks(u *url.URL, page models.Page, total int) string {
+	last := 0
+	if total > 0 {
+		last = (total - 1) / page.Limit * page.Limit
+	}
+
+	links := make([]string, 0, 4)
+	add := func(rel string, offset int) {
+		query := u.Query()
+		query.Set("limit", strconv.Itoa(page.Limit))
+		query.Set("offset", strconv.Itoa(offset))
+		ref := url.URL{Path: u.Path, RawQuery: query.Encode()}
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, ref.String(), rel))
+	}
+
+	add("first", 0)
+	if page.Offset > 0 {
+		add("prev", max(page.Offset-page.Limit, 0))
+	}
+	if page.Offset+page.Limit < total {
+		add("next", page.Offset+page.Limit)
+	}
+	add("last", last)
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,70 @@
+package filmoteka
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePage(t *testing.T) {
+	t.Run("default page", func(t *testing.T) {
+		page, err := parsePage(httptest.NewRequest(http.MethodGet, "/movies", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, models.Page{Limit: models.DefaultPageLimit}, page)
+	})
+
+	t.Run("custom page", func(t *testing.T) {
+		page, err := parsePage(httptest.NewRequest(http.MethodGet, "/movies?limit=10&offset=20", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, models.Page{Limit: 10, Offset: 20}, page)
+	})
+
+	t.Run("not integers", func(t *testing.T) {
+		_, err := parsePage(httptest.NewRequest(http.MethodGet, "/movies?limit=a&offset=b", nil))
+		assert.Equal(t, []models.FieldError{
+			{Field: "limit", Message: "limit must be an integer"},
+			{Field: "offset", Message: "offset must be an integer"},
+		}, models.FieldErrors(err))
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		_, err := parsePage(httptest.NewRequest(http.MethodGet, "/movies?limit=0", nil))
+		assert.Equal(t, "limit", models.FieldErrors(err)[0].Field)
+	})
+}
+
+func TestSendPage(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	app := CreateApp(":8080", logger, logger, nil, false, nil, RegistrationPolicy{}, nil)
+
+	t.Run("middle page", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/movies?sort=name&limit=10&offset=10", nil)
+
+		app.sendPage(w, r, models.Page{Limit: 10, Offset: 10}, 35, []int{1})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "35", w.Header().Get(totalCountHeader))
+		assert.Equal(t, `</movies?limit=10&offset=0&sort=name>; rel="first", `+
+			`</movies?limit=10&offset=0&sort=name>; rel="prev", `+
+			`</movies?limit=10&offset=20&sort=name>; rel="next", `+
+			`</movies?limit=10&offset=30&sort=name>; rel="last"`, w.Header().Get("Link"))
+		assert.Equal(t, "[1]", w.Body.String())
+	})
+
+	t.Run("single page", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/actors", nil)
+
+		app.sendPage(w, r, models.Page{Limit: 50}, 0, []int{})
+
+		assert.Equal(t, "0", w.Header().Get(totalCountHeader))
+		assert.Equal(t, `</actors?limit=50&offset=0>; rel="first", </actors?limit=50&offset=0>; rel="last"`, w.Header().Get("Link"))
+		assert.Equal(t, "[]", w.Body.String())
+	})
+}
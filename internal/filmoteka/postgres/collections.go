@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// AddCollection - добавление коллекции фильмов в БД.
+func (d dbProcessor) AddCollection(nickname string, c models.CollectionIn) (int, error) {
+	wrapErr := errors.New("error while inserting collection")
+	tx, err := d.db.Beginx()
+	if err != nil {
+		return 0, errors.Join(wrapErr, errBeginTx, err)
+	}
+	defer tx.Rollback()
+
+	if c.Kind == "" {
+		c.Kind = models.CollectionKindCollection
+	}
+	public := c.Public != nil && *c.Public
+	var id int
+	if err = tx.QueryRow(addCollection, c.Name, c.Description, c.Kind, public, nickname).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.Join(err, ErrUnknownUser)
+		}
+		return 0, errors.Join(wrapErr, err)
+	}
+	if err = d.addCollectionMovies(tx, id, c.Movies); err != nil {
+		return 0, errors.Join(wrapErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, errors.Join(wrapErr, errCommitTx, err)
+	}
+	return id, nil
+}
+
+// UpdateCollection - обновление коллекции фильмов в БД.
+func (d dbProcessor) UpdateCollection(a models.CollectionAccess, id int, c models.CollectionIn) error {
+	wrapErr := fmt.Errorf("error while updating collection %d", id)
+	tx, err := d.db.Beginx()
+	if err != nil {
+		return errors.Join(wrapErr, errBeginTx, err)
+	}
+	defer tx.Rollback()
+
+	if err = execAffected(tx, ErrUnknownCollection, updateCollection, id, a.Nickname, a.Moderator,
+		c.Name, c.Description, c.Kind, c.Public); err != nil {
+		return errors.Join(wrapErr, err)
+	}
+	if c.Movies != nil {
+		if err = d.replaceCollectionMovies(tx, id, c.Movies); err != nil {
+			return errors.Join(wrapErr, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Join(wrapErr, errCommitTx, err)
+	}
+	return nil
+}
+
+// SetCollectionMovies - замена фильмов коллекции и их порядка в БД.
+func (d dbProcessor) SetCollectionMovies(a models.CollectionAccess, id int, movies []int) error {
+	wrapErr := fmt.Errorf("error while setting movies of collection %d", id)
+	tx, err := d.db.Beginx()
+	if err != nil {
+		return errors.Join(wrapErr, errBeginTx, err)
+	}
+	defer tx.Rollback()
+
+	if err = execAffected(tx, ErrUnknownCollection, touchCollection, id, a.Nickname, a.Moderator); err != nil {
+		return errors.Join(wrapErr, err)
+	}
+	if err = d.replaceCollectionMovies(tx, id, movies); err != nil {
+		return errors.Join(wrapErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Join(wrapErr, errCommitTx, err)
+	}
+	return nil
+}
+
+// DeleteCollection - удаление коллекции фильмов из БД.
+func (d dbProcessor) DeleteCollection(a models.CollectionAccess, id int) error {
+	return d.deleteSmth(removeCollection, fmt.Sprintf("error while deleting collection %d", id), ErrUnknownCollection,
+		id, a.Nickname, a.Moderator)
+}
+
+// GetCollection - получение коллекции фильмов из БД.
+func (d dbProcessor) GetCollection(a models.CollectionAccess, id int) (models.CollectionOut, error) {
+	wrapErr := fmt.Errorf("error while getting collection %d", id)
+	var collection models.CollectionOut
+	if err := d.db.Get(&collection, getCollection, id, a.Nickname, a.Moderator); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.Join(err, ErrUnknownCollection)
+		}
+		return models.CollectionOut{}, errors.Join(wrapErr, err)
+	}
+
+	collection.Movies = []int{}
+	if err := d.db.Select(&collection.Movies, getCollectionMovies, id); err != nil {
+		return models.CollectionOut{}, errors.Join(wrapErr, errors.New("error while getting collection's movies"), err)
+	}
+	return collection, nil
+}
+
+// GetCollections - получение страницы видимых пользователю коллекций фильмов из БД.
+func (d dbProcessor) GetCollections(a models.CollectionAccess, page models.Page) ([]models.CollectionOut, int, error) {
+	wrapErr := errors.New("error while getting collections")
+	collections := []models.CollectionOut{}
+	if err := d.db.Select(&collections, getCollections, a.Nickname, a.Moderator, page.Limit, page.Offset); err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+
+	if err := d.fillCollections(collections); err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	total, err := countSmth(d.db, page, len(collections), countCollections, a.Nickname, a.Moderator)
+	if err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	return collections, total, nil
+}
+
+// collectionMovie - строка фильма коллекции.
+type collectionMovie struct {
+	CollectionId int `db:"collection_id"`
+	MovieId      int `db:"movie_id"`
+}
+
+// fillCollections - заполнение фильмов коллекций одним запросом.
+func (d dbProcessor) fillCollections(collections []models.CollectionOut) error {
+	if len(collections) == 0 {
+		return nil
+	}
+	ids := make([]int, len(collections))
+	index := make(map[int]int, len(collections))
+	for i := range collections {
+		ids[i] = collections[i].Id
+		index[collections[i].Id] = i
+		collections[i].Movies = []int{}
+	}
+
+	var rows []collectionMovie
+	if err := d.db.Select(&rows, getCollectionsMovies, pq.Array(ids)); err != nil {
+		return errors.Join(errors.New("error while getting collections' movies"), err)
+	}
+	for _, r := range rows {
+		if i, ok := index[r.CollectionId]; ok {
+			collections[i].Movies = append(collections[i].Movies, r.MovieId)
+		}
+	}
+	return nil
+}
+
+// replaceCollectionMovies - замена фильмов коллекции в рамках транзакции.
+func (d dbProcessor) replaceCollectionMovies(tx *sqlx.Tx, id int, movies []int) error {
+	if _, err := tx.Exec(removeCollectionMovies, id); err != nil {
+		return errors.Join(fmt.Errorf("error while removing movies from collection %d", id), err)
+	}
+	return d.addCollectionMovies(tx, id, movies)
+}
+
+// addCollectionMovies - добавление фильмов в коллекцию в указанном порядке.
+func (d dbProcessor) addCollectionMovies(tx *sqlx.Tx, id int, movies []int) error {
+	for i, movieId := range movies {
+		if _, err := tx.Exec(addMovieToCollection, id, movieId, i+1); err != nil {
+			switch {
+			case isForeignKeyViolation(err) && strings.Contains(violatedConstraint(err), "collection_id"):
+				err = errors.Join(err, ErrUnknownCollection)
+			case isForeignKeyViolation(err):
+				err = errors.Join(err, newKindError(ErrForeignKey, fmt.Sprintf("movie %d does not exist", movieId)))
+			default:
+				err = classify(err)
+			}
+			return errors.Join(fmt.Errorf("error while adding movie %d to collection %d", movieId, id), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+)
+
+// GetFilmography - получение страницы фильмов, в создании которых участвовал человек, из БД.
+func (d dbProcessor) GetFilmography(personId int, role string, sort models.Sort, page models.Page) ([]models.MovieOut, int, error) {
+	wrapErr := fmt.Errorf("error while getting filmography of person %d", personId)
+	order, err := orderBy(sort, movieSortColumns)
+	if err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	builder := &queryBuilder{}
+	if role == "" {
+		builder.where("id IN (SELECT movie_id FROM movie_actors WHERE actor_id = ?)", personId)
+	} else {
+		builder.where("id IN (SELECT movie_id FROM movie_actors WHERE actor_id = ? AND credit = ?)", personId, role)
+	}
+	query, args := builder.build(selectMovies, order+" LIMIT ? OFFSET ?", page.Limit, page.Offset)
+	var movies []models.MovieOut
+	if err := d.db.Select(&movies, query, args...); err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+
+	if len(movies) == 0 && page.Offset == 0 {
+		var exists bool
+		if err := d.db.Get(&exists, personExists, personId); err != nil {
+			return nil, 0, errors.Join(wrapErr, err)
+		}
+		if !exists {
+			return nil, 0, errors.Join(wrapErr, ErrUnknownActor)
+		}
+	}
+	if err := d.fillMovies(movies); err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	query, args = builder.build(countMovies, "")
+	total, err := countSmth(d.db, page, len(movies), query, args...)
+	if err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	return movies, total, nil
+}
@@ -21,92 +21,487 @@ type DbHandler interface {
 	//
 	// Принимает: id актёра и обновлённые данные актёра.
 	//
-	// Возвращает: ошибку.
+	// Возвращает: ошибку (ErrUnknownActor, если актёра нет).
 	UpdateActor(id int, a models.ActorIn) error
 
 	// DeleteActor - удаляет актёра из базы данных.
 	//
 	// Принимает: id актёра.
 	//
-	// Возвращает: ошибку.
-	DeleteActor(id int) error
+	// Возвращает: ключ файла фотографии (пустой, если его нет) и ошибку (ErrUnknownActor, если актёра нет).
+	DeleteActor(id int) (string, error)
 
 	// GetActor - получает актёра из базы данных.
 	//
 	// Принимает: id актёра.
 	//
-	// Возвращает: актёра и ошибку.
+	// Возвращает: актёра и ошибку (ErrUnknownActor, если актёра нет).
 	GetActor(id int) (models.ActorOut, error)
 
-	// GetActors - получает всех актёров из базы данных.
+	// GetActors - получает отсортированную страницу актёров из базы данных.
+	//
+	// Принимает: сортировку и страницу.
+	//
+	// Возвращает: актёров страницы, общее количество актёров и ошибку.
+	GetActors(sort models.Sort, page models.Page) ([]models.ActorOut, int, error)
+
+	// GetActorsByName - получает страницу актёров по фрагменту или похожему имени, в том числе
+	// записанному другим алфавитом, из базы данных.
 	//
-	// Возвращает: всех актёров и ошибку.
-	GetActors() ([]models.ActorOut, error)
+	// Принимает: поиск по имени актёра и страницу.
+	//
+	// Возвращает: актёров страницы по убыванию схожести, общее количество найденных актёров и ошибку.
+	GetActorsByName(n models.NameSearch, page models.Page) ([]models.ActorOut, int, error)
 
 	// AddMovie - добавляет фильм в базу данных.
 	//
 	// Принимает: фильм.
 	//
-	// Возвращает: id добавленного фильма и ошибку.
+	// Возвращает: id добавленного фильма и ошибку (ErrForeignKey, если актёра нет).
 	AddMovie(m models.MovieIn) (int, error)
 
 	// UpdateMovie - обновляет фильм в базе данных.
 	//
 	// Принимает: id фильма и обновлённые данные фильма.
 	//
-	// Возвращает: ошибку.
+	// Возвращает: ошибку (ErrUnknownMovie, если фильма нет, ErrForeignKey, если актёра нет).
 	UpdateMovie(id int, m models.MovieIn) error
 
 	// DeleteMovie - удаляет фильм из базы данных.
 	//
 	// Принимает: id фильма.
 	//
-	// Возвращает: ошибку.
-	DeleteMovie(id int) error
+	// Возвращает: ключ файла постера (пустой, если его нет) и ошибку (ErrUnknownMovie, если фильма нет).
+	DeleteMovie(id int) (string, error)
 
 	// GetMovie - получает фильм из базы данных.
 	//
 	// Принимает: id фильма.
 	//
-	// Возвращает: фильм и ошибку.
+	// Возвращает: фильм и ошибку (ErrUnknownMovie, если фильма нет).
 	GetMovie(id int) (models.MovieOut, error)
 
-	// GetMovies - получает все фильмы из базы данных.
+	// GetMovies - получает страницу фильмов, удовлетворяющих фильтру, из базы данных.
+	//
+	// Принимает: сортировку, фильтр и страницу.
+	//
+	// Возвращает: фильмы страницы, общее количество отфильтрованных фильмов и ошибку.
+	GetMovies(sort models.Sort, filter models.MovieFilter, page models.Page) ([]models.MovieOut, int, error)
+
+	// GetMoviesByActor - получает страницу фильмов с участием актёра, найденного по фрагменту или похожему имени, из базы данных.
+	//
+	// Принимает: поиск по имени актёра и страницу.
+	//
+	// Возвращает: фильмы страницы по убыванию схожести, общее количество найденных фильмов и ошибку.
+	GetMoviesByActor(n models.NameSearch, page models.Page) ([]models.MovieOut, int, error)
+
+	// GetMoviesByName - получает страницу фильмов по фрагменту или похожему названию из базы данных.
+	//
+	// Принимает: поиск по названию фильма и страницу.
+	//
+	// Возвращает: фильмы страницы по убыванию схожести, общее количество найденных фильмов и ошибку.
+	GetMoviesByName(n models.NameSearch, page models.Page) ([]models.MovieOut, int, error)
+
+	// GetFilmography - получает страницу фильмов, в создании которых участвовал человек, из базы данных.
+	//
+	// Принимает: id человека, тип участия (пустая строка - любой), сортировку и страницу.
+	//
+	// Возвращает: фильмы, общее количество фильмов и ошибку (ErrUnknownActor, если человека нет).
+	GetFilmography(personId int, role string, sort models.Sort, page models.Page) ([]models.MovieOut, int, error)
+
+	// SetMoviePoster - заменяет ключ файла постера фильма в базе данных.
+	//
+	// Принимает: id фильма и ключ файла (пустая строка - удалить постер).
+	//
+	// Возвращает: прежний ключ файла (пустая строка, если постера не было) и ошибку (ErrUnknownMovie, если фильма нет).
+	SetMoviePoster(id int, key string) (string, error)
+
+	// SetActorPhoto - заменяет ключ файла фотографии актёра в базе данных.
+	//
+	// Принимает: id актёра и ключ файла (пустая строка - удалить фотографию).
+	//
+	// Возвращает: прежний ключ файла (пустая строка, если фотографии не было) и ошибку (ErrUnknownActor, если актёра нет).
+	SetActorPhoto(id int, key string) (string, error)
+
+	// AddGenre - добавляет жанр в базу данных.
+	//
+	// Принимает: жанр.
+	//
+	// Возвращает: id добавленного жанра и ошибку (ErrGenreTaken, если жанр уже есть).
+	AddGenre(g models.GenreIn) (int, error)
+
+	// UpdateGenre - обновляет жанр в базе данных.
+	//
+	// Принимает: id жанра и обновлённые данные жанра.
+	//
+	// Возвращает: ошибку (ErrUnknownGenre, если жанра нет, ErrGenreTaken, если название занято).
+	UpdateGenre(id int, g models.GenreIn) error
+
+	// DeleteGenre - удаляет жанр вместе с его связями с фильмами из базы данных.
+	//
+	// Принимает: id жанра.
+	//
+	// Возвращает: ошибку (ErrUnknownGenre, если жанра нет).
+	DeleteGenre(id int) error
+
+	// GetGenre - получает жанр из базы данных.
+	//
+	// Принимает: id жанра.
+	//
+	// Возвращает: жанр и ошибку (ErrUnknownGenre, если жанра нет).
+	GetGenre(id int) (models.GenreOut, error)
+
+	// GetGenres - получает все жанры из базы данных.
+	//
+	// Возвращает: жанры, отсортированные по названию, и ошибку.
+	GetGenres() ([]models.GenreOut, error)
+
+	// AddReview - добавляет оценку и отзыв пользователя о фильме в базу данных.
+	//
+	// Принимает: никнейм автора, id фильма и отзыв.
+	//
+	// Возвращает: id добавленного отзыва и ошибку (ErrUnknownUser, если пользователя нет,
+	// ErrUnknownMovie, если фильма нет, ErrReviewExists, если пользователь уже оценил фильм).
+	AddReview(nickname string, movieId int, r models.ReviewIn) (int, error)
+
+	// UpdateReview - обновляет отзыв пользователя в базе данных.
+	//
+	// Принимает: никнейм автора, id отзыва и обновлённый отзыв.
+	//
+	// Возвращает: ошибку (ErrUnknownReview, если у пользователя нет такого отзыва).
+	UpdateReview(nickname string, id int, r models.ReviewIn) error
+
+	// DeleteReview - удаляет отзыв пользователя из базы данных.
+	//
+	// Принимает: никнейм автора и id отзыва.
+	//
+	// Возвращает: ошибку (ErrUnknownReview, если у пользователя нет такого отзыва).
+	DeleteReview(nickname string, id int) error
+
+	// GetMovieReviews - получает страницу отзывов о фильме из базы данных.
+	//
+	// Принимает: id фильма и страницу.
+	//
+	// Возвращает: отзывы, начиная с последних изменённых, общее количество отзывов и ошибку
+	// (ErrUnknownMovie, если фильма нет).
+	GetMovieReviews(movieId int, page models.Page) ([]models.ReviewOut, int, error)
+
+	// GetUserReviews - получает страницу отзывов пользователя из базы данных.
+	//
+	// Принимает: никнейм автора и страницу.
+	//
+	// Возвращает: отзывы, начиная с последних изменённых, общее количество отзывов и ошибку.
+	GetUserReviews(nickname string, page models.Page) ([]models.ReviewOut, int, error)
+
+	// AddToList - добавляет фильм в личный список пользователя в базе данных.
+	//
+	// Принимает: никнейм пользователя, название списка, id фильма и данные фильма в списке.
+	//
+	// Возвращает: ошибку (ErrUnknownUser, если пользователя нет, ErrUnknownMovie, если фильма нет).
+	AddToList(nickname, list string, movieId int, e models.ListEntryIn) error
+
+	// RemoveFromList - удаляет фильм из личного списка пользователя в базе данных.
+	//
+	// Принимает: никнейм пользователя, название списка и id фильма.
+	//
+	// Возвращает: ошибку (ErrNotInList, если фильма нет в списке).
+	RemoveFromList(nickname, list string, movieId int) error
+
+	// GetList - получает страницу фильмов личного списка пользователя из базы данных.
+	//
+	// Принимает: никнейм пользователя, название списка и страницу.
+	//
+	// Возвращает: фильмы вместе с их положением в списках пользователя, общее количество фильмов списка и ошибку.
+	GetList(nickname, list string, page models.Page) ([]models.MovieOut, int, error)
+
+	// FillMovieStatuses - заполняет положение фильмов в личных списках пользователя из базы данных.
+	//
+	// Принимает: никнейм пользователя и фильмы.
+	//
+	// Возвращает: ошибку.
+	FillMovieStatuses(nickname string, movies []models.MovieOut) error
+
+	// AddCollection - добавляет коллекцию фильмов в базу данных.
+	//
+	// Принимает: никнейм владельца и коллекцию.
+	//
+	// Возвращает: id добавленной коллекции и ошибку (ErrUnknownUser, если пользователя нет,
+	// ErrForeignKey, если фильма нет).
+	AddCollection(nickname string, c models.CollectionIn) (int, error)
+
+	// UpdateCollection - обновляет коллекцию фильмов в базе данных.
+	//
+	// Принимает: пользователя, id коллекции и обновлённые данные коллекции.
+	//
+	// Возвращает: ошибку (ErrUnknownCollection, если коллекции нет или пользователь не может её изменять,
+	// ErrForeignKey, если фильма нет).
+	UpdateCollection(a models.CollectionAccess, id int, c models.CollectionIn) error
+
+	// SetCollectionMovies - заменяет фильмы коллекции и их порядок в базе данных.
+	//
+	// Принимает: пользователя, id коллекции и id фильмов в новом порядке.
+	//
+	// Возвращает: ошибку (ErrUnknownCollection, если коллекции нет или пользователь не может её изменять,
+	// ErrForeignKey, если фильма нет).
+	SetCollectionMovies(a models.CollectionAccess, id int, movies []int) error
+
+	// DeleteCollection - удаляет коллекцию фильмов из базы данных.
+	//
+	// Принимает: пользователя и id коллекции.
+	//
+	// Возвращает: ошибку (ErrUnknownCollection, если коллекции нет или пользователь не может её удалять).
+	DeleteCollection(a models.CollectionAccess, id int) error
+
+	// GetCollection - получает коллекцию фильмов из базы данных.
+	//
+	// Принимает: пользователя и id коллекции.
+	//
+	// Возвращает: коллекцию и ошибку (ErrUnknownCollection, если коллекции нет или она скрыта от пользователя).
+	GetCollection(a models.CollectionAccess, id int) (models.CollectionOut, error)
+
+	// GetCollections - получает страницу видимых пользователю коллекций фильмов из базы данных.
+	//
+	// Принимает: пользователя и страницу.
+	//
+	// Возвращает: коллекции, отсортированные по названию, общее количество коллекций и ошибку.
+	GetCollections(a models.CollectionAccess, page models.Page) ([]models.CollectionOut, int, error)
+
+	// AddSeries - добавляет сериал в базу данных.
+	//
+	// Принимает: сериал.
+	//
+	// Возвращает: id добавленного сериала и ошибку.
+	AddSeries(s models.SeriesIn) (int, error)
+
+	// UpdateSeries - обновляет сериал в базе данных.
+	//
+	// Принимает: id сериала и обновлённые данные сериала.
+	//
+	// Возвращает: ошибку (ErrUnknownSeries, если сериала нет).
+	UpdateSeries(id int, s models.SeriesIn) error
+
+	// DeleteSeries - удаляет сериал вместе с сезонами и сериями из базы данных.
+	//
+	// Принимает: id сериала.
+	//
+	// Возвращает: ошибку (ErrUnknownSeries, если сериала нет).
+	DeleteSeries(id int) error
+
+	// GetSeries - получает сериал вместе с сезонами и сериями из базы данных.
+	//
+	// Принимает: id сериала.
+	//
+	// Возвращает: сериал и ошибку (ErrUnknownSeries, если сериала нет).
+	GetSeries(id int) (models.SeriesOut, error)
+
+	// GetSeriesList - получает отсортированную страницу сериалов без сезонов из базы данных.
+	//
+	// Принимает: сортировку и страницу.
+	//
+	// Возвращает: сериалы страницы, общее количество сериалов и ошибку.
+	GetSeriesList(sort models.Sort, page models.Page) ([]models.SeriesOut, int, error)
+
+	// AddSeason - добавляет сезон в сериал в базе данных.
+	//
+	// Принимает: id сериала и сезон.
+	//
+	// Возвращает: id добавленного сезона и ошибку (ErrUnknownSeries, если сериала нет,
+	// ErrSeasonExists, если сезон с таким номером уже есть).
+	AddSeason(seriesId int, s models.SeasonIn) (int, error)
+
+	// UpdateSeason - обновляет сезон в базе данных.
+	//
+	// Принимает: id сезона и обновлённые данные сезона.
+	//
+	// Возвращает: ошибку (ErrUnknownSeason, если сезона нет, ErrSeasonExists, если сезон с таким номером уже есть).
+	UpdateSeason(id int, s models.SeasonIn) error
+
+	// DeleteSeason - удаляет сезон вместе с сериями из базы данных.
+	//
+	// Принимает: id сезона.
+	//
+	// Возвращает: ошибку (ErrUnknownSeason, если сезона нет).
+	DeleteSeason(id int) error
+
+	// GetSeason - получает сезон вместе с сериями из базы данных.
+	//
+	// Принимает: id сезона.
+	//
+	// Возвращает: сезон и ошибку (ErrUnknownSeason, если сезона нет).
+	GetSeason(id int) (models.SeasonOut, error)
+
+	// AddEpisode - добавляет серию в сезон в базе данных.
+	//
+	// Принимает: id сезона и серию.
+	//
+	// Возвращает: id добавленной серии и ошибку (ErrUnknownSeason, если сезона нет,
+	// ErrEpisodeExists, если серия с таким номером уже есть, ErrForeignKey, если актёра нет).
+	AddEpisode(seasonId int, e models.EpisodeIn) (int, error)
+
+	// UpdateEpisode - обновляет серию в базе данных.
+	//
+	// Принимает: id серии и обновлённые данные серии.
+	//
+	// Возвращает: ошибку (ErrUnknownEpisode, если серии нет, ErrEpisodeExists, если серия с таким номером уже есть,
+	// ErrForeignKey, если актёра нет).
+	UpdateEpisode(id int, e models.EpisodeIn) error
+
+	// DeleteEpisode - удаляет серию из базы данных.
+	//
+	// Принимает: id серии.
+	//
+	// Возвращает: ошибку (ErrUnknownEpisode, если серии нет).
+	DeleteEpisode(id int) error
+
+	// GetEpisode - получает серию из базы данных.
 	//
-	// Принимает: тип сортировки.
+	// Принимает: id серии.
 	//
-	// Возвращает: все фильмы и ошибку.
-	GetMovies(sortType int) ([]models.MovieOut, error)
+	// Возвращает: серию и ошибку (ErrUnknownEpisode, если серии нет).
+	GetEpisode(id int) (models.EpisodeOut, error)
 
-	// GetMoviesByActor - получает все фильмы с участием актёра из базы данных.
+	// Search - выполняет полнотекстовый поиск фильмов и актёров в базе данных.
 	//
-	// Принимает: имя актёра.
+	// Принимает: поисковый запрос.
 	//
-	// Возвращает: все фильмы и ошибку.
-	GetMoviesByActor(name string) ([]models.MovieOut, error)
+	// Возвращает: найденные фильмы и актёров, отсортированные по релевантности, и ошибку.
+	Search(q models.SearchQuery) (models.SearchResult, error)
 
-	// GetMoviesByName - получает все фильмы с именем из базы данных.
+	// Suggest - получает подсказки актёров или фильмов, имя которых или слово в имени начинается с префикса, из базы данных.
 	//
-	// Принимает: имя фильма.
+	// Принимает: запрос подсказок.
 	//
-	// Возвращает: все фильмы и ошибку.
-	GetMoviesByName(name string) ([]models.MovieOut, error)
+	// Возвращает: подсказки (сначала совпадения с началом имени) и ошибку.
+	Suggest(q models.SuggestQuery) ([]models.Suggestion, error)
 
-	// AddUser - добавляет пользователя в базу данных.
+	// AddUser - добавляет пользователя вместе с ролями в базу данных.
 	//
 	// Принимает: пользователя.
 	//
-	// Возвращает: id добавленного пользователя и ошибку.
+	// Возвращает: id добавленного пользователя и ошибку (ErrNicknameTaken, если никнейм занят,
+	// ErrUnknownRole, если роль не существует).
 	AddUser(u models.User) (int, error)
 
-	// UpdateUser - обновляет пользователя в базе данных.
+	// GetUser - получает пользователя из базы данных.
+	//
+	// Принимает: id пользователя.
+	//
+	// Возвращает: пользователя и ошибку (ErrUnknownUser, если пользователя нет).
+	GetUser(id int) (models.UserOut, error)
+
+	// GetUsers - получает всех пользователей из базы данных.
+	//
+	// Возвращает: всех пользователей и ошибку.
+	GetUsers() ([]models.UserOut, error)
+
+	// UpdateUser - обновляет роли пользователя в базе данных.
 	//
-	// Принимает: id пользователя и обновлённые данные пользователя.
+	// Принимает: id пользователя и изменения пользователя.
+	//
+	// Возвращает: ошибку (ErrUnknownUser, если пользователя нет, ErrUnknownRole, если роль не существует).
+	UpdateUser(id int, u models.UserPatch) error
+
+	// UpdateUserPassword - обновляет пароль пользователя в базе данных и отзывает его сессии.
+	//
+	// Принимает: никнейм пользователя и хэш нового пароля.
+	//
+	// Возвращает: ошибку (ErrUnknownUser, если пользователя нет).
+	UpdateUserPassword(name, password string) error
+
+	// DeleteUser - удаляет пользователя и его сессии из базы данных.
+	//
+	// Принимает: id пользователя.
+	//
+	// Возвращает: ошибку (ErrUnknownUser, если пользователя нет).
+	DeleteUser(id int) error
+
+	// GetUserByNickname - получает пользователя вместе с хэшем пароля из базы данных.
+	//
+	// Принимает: никнейм пользователя.
+	//
+	// Возвращает: пользователя и ошибку (ErrUnknownUser, если пользователя нет).
+	GetUserByNickname(name string) (models.User, error)
+
+	// CheckUserPassword - проверяет пароль пользователя.
+	//
+	// Принимает: никнейм и пароль пользователя в открытом виде.
+	//
+	// Возвращает: ошибку (ErrInvalidCredentials, если пользователя нет или пароль не совпадает).
+	CheckUserPassword(name, password string) error
+
+	// GetUserPermissions - получает права пользователя, выданные через его роли.
+	//
+	// Принимает: никнейм пользователя.
+	//
+	// Возвращает: список прав и ошибку.
+	GetUserPermissions(name string) ([]string, error)
+
+	// GetRoles - получает все роли вместе с их правами из базы данных.
+	//
+	// Возвращает: все роли и ошибку.
+	GetRoles() ([]models.Role, error)
+
+	// AddSession - добавляет сессию пользователя в базу данных.
+	//
+	// Принимает: сессию.
 	//
 	// Возвращает: ошибку.
-	CheckUserRole(name, password string) (bool, error)
+	AddSession(s models.Session) error
+
+	// GetSession - получает действующую сессию из базы данных.
+	//
+	// Принимает: id сессии.
+	//
+	// Возвращает: сессию и ошибку (ErrUnknownSession, если сессия не найдена или истекла).
+	GetSession(id string) (models.Session, error)
+
+	// DeleteSession - удаляет сессию из базы данных.
+	//
+	// Принимает: id сессии.
+	//
+	// Возвращает: ошибку.
+	DeleteSession(id string) error
 }
 
+var (
+	// ErrUnknownUser - ошибка отсутствия пользователя с указанным никнеймом или id.
+	ErrUnknownUser = newKindError(ErrNotFound, "unknown user")
+	// ErrInvalidCredentials - ошибка проверки пароля: пользователя нет или пароль не совпадает.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrNicknameTaken - ошибка добавления пользователя с уже занятым никнеймом.
+	ErrNicknameTaken = newKindError(ErrConflict, "nickname is already taken")
+	// ErrUnknownRole - ошибка назначения несуществующей роли.
+	ErrUnknownRole = newKindError(ErrForeignKey, "unknown role")
+	// ErrUnknownActor - ошибка отсутствия актёра с указанным id.
+	ErrUnknownActor = newKindError(ErrNotFound, "unknown actor")
+	// ErrUnknownMovie - ошибка отсутствия фильма с указанным id.
+	ErrUnknownMovie = newKindError(ErrNotFound, "unknown movie")
+	// ErrUnknownGenre - ошибка отсутствия жанра с указанным id.
+	ErrUnknownGenre = newKindError(ErrNotFound, "unknown genre")
+	// ErrGenreTaken - ошибка добавления жанра с уже существующим названием.
+	ErrGenreTaken = newKindError(ErrConflict, "genre already exists")
+	// ErrUnknownReview - ошибка отсутствия отзыва с указанным id у пользователя.
+	ErrUnknownReview = newKindError(ErrNotFound, "unknown review")
+	// ErrReviewExists - ошибка повторного отзыва пользователя о фильме.
+	ErrReviewExists = newKindError(ErrConflict, "movie is already reviewed by user")
+	// ErrNotInList - ошибка отсутствия фильма в личном списке пользователя.
+	ErrNotInList = newKindError(ErrNotFound, "movie is not in the list")
+	// ErrUnknownCollection - ошибка отсутствия доступной пользователю коллекции с указанным id.
+	ErrUnknownCollection = newKindError(ErrNotFound, "unknown collection")
+	// ErrUnknownSeries - ошибка отсутствия сериала с указанным id.
+	ErrUnknownSeries = newKindError(ErrNotFound, "unknown series")
+	// ErrUnknownSeason - ошибка отсутствия сезона с указанным id.
+	ErrUnknownSeason = newKindError(ErrNotFound, "unknown season")
+	// ErrUnknownEpisode - ошибка отсутствия серии с указанным id.
+	ErrUnknownEpisode = newKindError(ErrNotFound, "unknown episode")
+	// ErrSeasonExists - ошибка добавления сезона с уже существующим в сериале номером.
+	ErrSeasonExists = newKindError(ErrConflict, "season with this number already exists")
+	// ErrEpisodeExists - ошибка добавления серии с уже существующим в сезоне номером.
+	ErrEpisodeExists = newKindError(ErrConflict, "episode with this number already exists")
+	// ErrUnknownSession - ошибка отсутствия действующей сессии.
+	ErrUnknownSession = errors.New("session does not exist or is expired")
+)
+
 // GetHandler - возвращает обработчик базы данных фильмотеки.
 //
 // Принимает: подключение к базе данных и флаг пересоздания таблиц.
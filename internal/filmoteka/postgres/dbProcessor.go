@@ -4,10 +4,15 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/famusovsky/VkTestTask/pkg/translit"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // overrideDB - функция, перезаписывающая таблицы фильмотеки в БД.
@@ -24,7 +29,8 @@ func overrideDB(db *sql.DB) error {
 
 // dropTables - функция, удаляющая таблицы фильмотеки в БД.
 func dropTables(db *sql.DB) error {
-	q := strings.Join([]string{dropMovieActors, dropActors, dropMovies, dropUsers}, " ")
+	q := strings.Join([]string{dropEpisodeActors, dropEpisodes, dropSeasons, dropSeries, dropCollectionMovies, dropCollections, dropUserMovies, dropReviews, dropSessions, dropUserRoles, dropRolePermissions, dropRoles, dropMovieGenres, dropGenres,
+		dropMovieActors, dropActors, dropMovies, dropUsers}, " ")
 	if _, err := db.Exec(q); err != nil {
 		return errors.Join(fmt.Errorf("error while dropping tables: %s", err))
 	}
@@ -33,7 +39,9 @@ func dropTables(db *sql.DB) error {
 
 // createTables - функция, добавляющая таблицы фильмотеки в БД.
 func createTables(db *sql.DB) error {
-	q := strings.Join([]string{createActors, createMovies, createUsers, createActorMovieRelations}, " ")
+	q := strings.Join([]string{createActors, createMovies, createUsers, createActorMovieRelations, createCredits, createCast, createImages, createMovieMeta, createGenres, createMovieGenres, createReviews, createUserMovies,
+		createCollections, createCollectionMovies, createSeries, createSeasons, createEpisodes, createEpisodeActors, createSessions,
+		createRoles, createRolePermissions, createUserRoles, seedRoles, seedRolePermissions, createSearchIndexes, createFullTextSearch}, " ")
 	if _, err := db.Exec(q); err != nil {
 		return errors.Join(fmt.Errorf("error while creating tables: %s", err))
 	}
@@ -57,9 +65,31 @@ func (d dbProcessor) AddActor(a models.ActorIn) (int, error) {
 	return d.addSmthWithId(addActor, "error while inserting actor", a.Name, a.Gender, a.DateOfBirth)
 }
 
-// AddUser - добавление пользователя в БД.
+// AddUser - добавление пользователя вместе с ролями в БД.
 func (d dbProcessor) AddUser(u models.User) (int, error) {
-	return d.addSmthWithId(addUser, "error while inserting user", u.Nickname, u.Password, u.IsAdmin)
+	wrapErr := errors.New("error while inserting user")
+	tx, err := d.db.Beginx()
+	if err != nil {
+		return 0, errors.Join(wrapErr, errBeginTx, err)
+	}
+	defer tx.Rollback()
+
+	roles := u.RoleNames()
+	var id int
+	if err = tx.QueryRow(addUser, u.Nickname, u.Password, slices.Contains(roles, models.RoleAdmin)).Scan(&id); err != nil {
+		if isUniqueViolation(err) {
+			err = errors.Join(err, ErrNicknameTaken)
+		}
+		return 0, errors.Join(wrapErr, err)
+	}
+	if err = d.setUserRoles(tx, id, roles); err != nil {
+		return 0, errors.Join(wrapErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, errors.Join(wrapErr, errCommitTx, err)
+	}
+	return id, nil
 }
 
 // AddMovie - добавление фильма в БД.
@@ -75,9 +105,23 @@ func (d dbProcessor) AddMovie(m models.MovieIn) (int, error) {
 	if err = tx.QueryRow(addMovie, m.Name, m.Description, m.ReleaseDate, *m.Rating).Scan(&id); err != nil {
 		return 0, errors.Join(wrapErr, err)
 	}
-	for _, aId := range m.Actors {
-		err = errors.Join(d.addActorToMovie(tx, aId, id))
-		if err != nil {
+	if !m.MovieMeta.IsZero() {
+		if err = setMovieMeta(tx, id, m.MovieMeta); err != nil {
+			return 0, errors.Join(wrapErr, err)
+		}
+	}
+	for _, c := range m.CastList() {
+		if err = d.addCastToMovie(tx, c, id); err != nil {
+			return 0, errors.Join(wrapErr, err)
+		}
+	}
+	for _, c := range m.CrewList() {
+		if err = d.addCreditToMovie(tx, c, id); err != nil {
+			return 0, errors.Join(wrapErr, err)
+		}
+	}
+	for _, gId := range m.Genres {
+		if err = d.addGenreToMovie(tx, gId, id); err != nil {
 			return 0, errors.Join(wrapErr, err)
 		}
 	}
@@ -89,23 +133,63 @@ func (d dbProcessor) AddMovie(m models.MovieIn) (int, error) {
 	return id, err
 }
 
-// CheckUserRole - проверка роли пользователя.
-func (d dbProcessor) CheckUserRole(name string, password string) (bool, error) {
-	var isAdmin bool
-	if err := d.db.Get(&isAdmin, checkUserRole, name, password); err != nil {
-		return false, errors.Join(errors.New("error while checking user's role"), err)
+// dummyPasswordHash - хэш пароля, с которым сравнивается пароль несуществующего пользователя,
+// чтобы проверка занимала столько же времени, сколько для существующего.
+const dummyPasswordHash = "$2a$08$/tyhZcod9GFvxlv6vv3iZux/.6H33bE4OVP.1qTaAfVzgmoIevG3u"
+
+// CheckUserPassword - проверка пароля пользователя.
+//
+// Неизвестный пользователь и неверный пароль не различаются, чтобы по ответу нельзя было узнать, существует ли пользователь.
+func (d dbProcessor) CheckUserPassword(name string, password string) error {
+	wrapErr := errors.New("error while checking user's password")
+	hash := dummyPasswordHash
+	user, err := d.GetUserByNickname(name)
+	switch {
+	case err == nil:
+		hash = user.Password
+	case !errors.Is(err, ErrUnknownUser):
+		return errors.Join(wrapErr, err)
 	}
-	return isAdmin, nil
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil || err != nil {
+		return errors.Join(wrapErr, ErrInvalidCredentials)
+	}
+	return nil
 }
 
 // DeleteActor - удаление актёра из БД.
-func (d dbProcessor) DeleteActor(id int) error {
-	return d.deleteSmth(removeActor, fmt.Sprintf("error while deleting actor %d", id), id)
+func (d dbProcessor) DeleteActor(id int) (string, error) {
+	return d.deleteWithKey(removeActor, fmt.Sprintf("error while deleting actor %d", id), ErrUnknownActor, id)
 }
 
 // DeleteMovie - удаление фильма из БД.
-func (d dbProcessor) DeleteMovie(id int) error {
-	return d.deleteSmth(removeMovie, fmt.Sprintf("error while deleting movie %d", id), id)
+func (d dbProcessor) DeleteMovie(id int) (string, error) {
+	return d.deleteWithKey(removeMovie, fmt.Sprintf("error while deleting movie %d", id), ErrUnknownMovie, id)
+}
+
+// DeleteUser - удаление пользователя и его сессий из БД.
+func (d dbProcessor) DeleteUser(id int) error {
+	wrapErr := fmt.Errorf("error while deleting user %d", id)
+	tx, err := d.db.Begin()
+	if err != nil {
+		return errors.Join(wrapErr, errBeginTx, err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec(removeUserSessions, id); err != nil {
+		return errors.Join(wrapErr, err)
+	}
+	res, err := tx.Exec(removeUser, id)
+	if err == nil {
+		err = checkAffected(res, ErrUnknownUser)
+	}
+	if err != nil {
+		return errors.Join(wrapErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Join(wrapErr, errCommitTx, err)
+	}
+	return nil
 }
 
 // GetActor - получение актёра из БД.
@@ -113,26 +197,119 @@ func (d dbProcessor) GetActor(id int) (models.ActorOut, error) {
 	wrapErr := fmt.Errorf("error while getting actor %d", id)
 	var actor models.ActorOut
 	if err := d.db.Get(&actor, getActor, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.Join(err, ErrUnknownActor)
+		}
 		return models.ActorOut{}, errors.Join(wrapErr, err)
 	}
-	if err := d.db.Select(&actor.Movies, getActorMovies, id); err != nil {
+	var roles []movieActor
+	if err := d.db.Select(&roles, getActorMovies, id); err != nil {
 		return actor, errors.Join(wrapErr, errors.New("error while getting actors's movies"), err)
 	}
+	for _, role := range roles {
+		role.addTo(&actor)
+	}
 	return actor, nil
 }
 
-// GetActors - получение актёров из БД.
-func (d dbProcessor) GetActors() ([]models.ActorOut, error) {
+// GetActors - получение отсортированной страницы актёров из БД.
+func (d dbProcessor) GetActors(sort models.Sort, page models.Page) ([]models.ActorOut, int, error) {
+	wrapErr := errors.New("error while getting actors")
+	order, err := orderBy(sort, actorSortColumns)
+	if err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	query, args := (&queryBuilder{}).build(selectActors, order+" LIMIT ? OFFSET ?", page.Limit, page.Offset)
 	var actors []models.ActorOut
-	if err := d.db.Select(&actors, getActors); err != nil {
-		return nil, errors.Join(errors.New("error while getting actors"), err)
+	if err := d.db.Select(&actors, query, args...); err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	if err := d.fillActors(actors); err != nil {
+		return nil, 0, errors.Join(wrapErr, errors.New("error while getting actors' movies"), err)
+	}
+	total, err := countSmth(d.db, page, len(actors), countActors)
+	if err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
 	}
-	for i := range actors {
-		if err := d.db.Select(&actors[i].Movies, getActorMovies, actors[i].Id); err != nil {
-			return nil, errors.Join(errors.New("error while getting actors' movies"), err)
+	return actors, total, nil
+}
+
+// GetActorsByName - нечёткий поиск страницы актёров по имени в БД.
+func (d dbProcessor) GetActorsByName(n models.NameSearch, page models.Page) ([]models.ActorOut, int, error) {
+	wrapErr := errors.New("error while getting actors by name")
+	actors, total, err := selectByName[models.ActorOut](d, getActorsByName, countActorsByName, n, page)
+	if err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	if err := d.fillActors(actors); err != nil {
+		return nil, 0, errors.Join(wrapErr, errors.New("error while getting actors' movies"), err)
+	}
+	return actors, total, nil
+}
+
+// Search - полнотекстовый поиск фильмов и актёров в БД.
+func (d dbProcessor) Search(q models.SearchQuery) (models.SearchResult, error) {
+	wrapErr := errors.New("error while searching")
+	res := models.SearchResult{Movies: []models.MovieHit{}, Actors: []models.ActorHit{}}
+	if err := d.db.Select(&res.Movies, searchMovies, q.Query, q.Limit); err != nil {
+		return models.SearchResult{}, errors.Join(wrapErr, errors.New("error while searching movies"), err)
+	}
+	if err := d.db.Select(&res.Actors, searchActors, q.Query, q.Limit); err != nil {
+		return models.SearchResult{}, errors.Join(wrapErr, errors.New("error while searching actors"), err)
+	}
+	return res, nil
+}
+
+// Suggest - получение подсказок актёров или фильмов по префиксу из БД.
+func (d dbProcessor) Suggest(q models.SuggestQuery) ([]models.Suggestion, error) {
+	wrapErr := fmt.Errorf("error while getting %s suggestions", q.Type)
+	query := suggestMovies
+	if q.Type == models.SuggestActor {
+		query = suggestActors
+	}
+	prefix := likeEscaper.Replace(q.Prefix)
+	res := []models.Suggestion{}
+	if err := d.db.Select(&res, query, strings.ToLower(prefix)+"%", "% "+prefix+"%", q.Limit); err != nil {
+		return nil, errors.Join(wrapErr, err)
+	}
+	return res, nil
+}
+
+// GetUser - получение пользователя из БД.
+func (d dbProcessor) GetUser(id int) (models.UserOut, error) {
+	var row userRow
+	if err := d.db.Get(&row, getUser, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrUnknownUser
 		}
+		return models.UserOut{}, errors.Join(fmt.Errorf("error while getting user %d", id), err)
+	}
+	return row.toUser(), nil
+}
+
+// GetUsers - получение пользователей из БД.
+func (d dbProcessor) GetUsers() ([]models.UserOut, error) {
+	var rows []userRow
+	if err := d.db.Select(&rows, getUsers); err != nil {
+		return nil, errors.Join(errors.New("error while getting users"), err)
 	}
-	return actors, nil
+	users := make([]models.UserOut, len(rows))
+	for i := range rows {
+		users[i] = rows[i].toUser()
+	}
+	return users, nil
+}
+
+// GetUserByNickname - получение пользователя по никнейму из БД.
+func (d dbProcessor) GetUserByNickname(name string) (models.User, error) {
+	var user models.User
+	if err := d.db.Get(&user, getUserByName, name); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrUnknownUser
+		}
+		return models.User{}, errors.Join(fmt.Errorf("error while getting user %s", name), err)
+	}
+	return user, nil
 }
 
 // GetMovie - получение фильма из БД.
@@ -140,61 +317,73 @@ func (d dbProcessor) GetMovie(id int) (models.MovieOut, error) {
 	wrapErr := fmt.Errorf("error while getting movie %d", id)
 	var movie models.MovieOut
 	if err := d.db.Get(&movie, getMovie, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.Join(err, ErrUnknownMovie)
+		}
 		return models.MovieOut{}, errors.Join(wrapErr, err)
 	}
-	if err := d.db.Select(&movie.Actors, getMovieActors, id); err != nil {
+	var credits []movieCredit
+	if err := d.db.Select(&credits, getMovieCredits, id); err != nil {
 		return movie, errors.Join(wrapErr, errors.New("error while getting movie's actors"), err)
 	}
+	for _, c := range credits {
+		c.addTo(&movie)
+	}
+	if err := d.db.Select(&movie.Genres, getMovieGenres, id); err != nil {
+		return movie, errors.Join(wrapErr, errors.New("error while getting movie's genres"), err)
+	}
 	return movie, nil
 }
 
-// GetMovies - получение фильмов из БД.
-func (d dbProcessor) GetMovies(sortType int) ([]models.MovieOut, error) {
+// GetMovies - получение отфильтрованной страницы фильмов из БД.
+func (d dbProcessor) GetMovies(sort models.Sort, filter models.MovieFilter, page models.Page) ([]models.MovieOut, int, error) {
 	wrapErr := errors.New("error while getting movies")
-	var movies []models.MovieOut
-	var err error
-	switch sortType {
-	case models.SortByRating:
-		err = d.db.Select(&movies, getMoviesSortByRating)
-	case models.SortByName:
-		err = d.db.Select(&movies, getMoviesSortByName)
-	case models.SortByReleaseDate:
-		err = d.db.Select(&movies, getMoviesSortByReleaseDate)
-	}
+	order, err := orderBy(sort, movieSortColumns)
 	if err != nil {
-		return nil, errors.Join(wrapErr, err)
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	builder := movieFilterQuery(filter)
+	query, args := builder.build(selectMovies, order+" LIMIT ? OFFSET ?", page.Limit, page.Offset)
+	var movies []models.MovieOut
+	if err := d.db.Select(&movies, query, args...); err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
 	}
 
-	if err = d.fillMovies(movies); err != nil {
-		return nil, errors.Join(wrapErr, errors.New("error while getting movie's actors"), err)
+	if err := d.fillMovies(movies); err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	query, args = builder.build(countMovies, "")
+	total, err := countSmth(d.db, page, len(movies), query, args...)
+	if err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
 	}
-	return movies, nil
+	return movies, total, nil
 }
 
-// GetMoviesByActor - получение фильмов, в которых играл актёр, из БД.
-func (d dbProcessor) GetMoviesByActor(name string) ([]models.MovieOut, error) {
+// GetMoviesByActor - нечёткий поиск страницы фильмов, в которых играл актёр, по его имени в БД.
+func (d dbProcessor) GetMoviesByActor(n models.NameSearch, page models.Page) ([]models.MovieOut, int, error) {
 	wrapErr := errors.New("error while getting movies by actor")
-	var movies []models.MovieOut
-	if err := d.db.Select(&movies, getMoviesByActor, name); err != nil {
-		return nil, errors.Join(wrapErr, err)
+	movies, total, err := selectByName[models.MovieOut](d, getMoviesByActor, countMoviesByActor, n, page)
+	if err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
 	}
 	if err := d.fillMovies(movies); err != nil {
-		return nil, errors.Join(wrapErr, errors.New("error while getting movie's actors"), err)
+		return nil, 0, errors.Join(wrapErr, err)
 	}
-	return movies, nil
+	return movies, total, nil
 }
 
-// GetMoviesByName - получение фильмов по фрагменту названия из БД.
-func (d dbProcessor) GetMoviesByName(name string) ([]models.MovieOut, error) {
+// GetMoviesByName - нечёткий поиск страницы фильмов по названию в БД.
+func (d dbProcessor) GetMoviesByName(n models.NameSearch, page models.Page) ([]models.MovieOut, int, error) {
 	wrapErr := errors.New("error while getting movies by name")
-	var movies []models.MovieOut
-	if err := d.db.Select(&movies, getMoviesByName, name); err != nil {
-		return nil, errors.Join(wrapErr, err)
+	movies, total, err := selectByName[models.MovieOut](d, getMoviesByName, countMoviesByName, n, page)
+	if err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
 	}
 	if err := d.fillMovies(movies); err != nil {
-		return nil, errors.Join(wrapErr, errors.New("error while getting movie's actors"), err)
+		return nil, 0, errors.Join(wrapErr, err)
 	}
-	return movies, nil
+	return movies, total, nil
 }
 
 // UpdateActor - обновление актёра в БД.
@@ -206,18 +395,23 @@ func (d dbProcessor) UpdateActor(id int, a models.ActorIn) error {
 	}
 	defer tx.Rollback()
 
+	if a.IsZero() {
+		if err = lockRow(tx, ErrUnknownActor, lockActor, id); err != nil {
+			return errors.Join(wrapErr, err)
+		}
+	}
 	if a.Name != "" {
-		if _, err = tx.Exec(updateActorName, id, a.Name); err != nil {
+		if err = execAffected(tx, ErrUnknownActor, updateActorName, id, a.Name); err != nil {
 			return errors.Join(wrapErr, err)
 		}
 	}
 	if a.Gender != "" {
-		if _, err = tx.Exec(updateActorGender, id, a.Gender); err != nil {
+		if err = execAffected(tx, ErrUnknownActor, updateActorGender, id, a.Gender); err != nil {
 			return errors.Join(wrapErr, err)
 		}
 	}
 	if !a.DateOfBirth.IsZero() {
-		if _, err = tx.Exec(updateActorDateOfBirth, id, a.DateOfBirth); err != nil {
+		if err = execAffected(tx, ErrUnknownActor, updateActorDateOfBirth, id, a.DateOfBirth); err != nil {
 			return errors.Join(wrapErr, err)
 		}
 	}
@@ -237,51 +431,197 @@ func (d dbProcessor) UpdateMovie(id int, m models.MovieIn) error {
 	}
 	defer tx.Rollback()
 
+	// Если меняются только участники и жанры, отсутствие фильма не обнаружится при их замене.
+	if m.Name == "" && m.Description == "" && m.ReleaseDate.IsZero() && m.Rating == nil && m.MovieMeta.IsZero() {
+		if err = lockRow(tx, ErrUnknownMovie, lockMovie, id); err != nil {
+			return errors.Join(wrapErr, err)
+		}
+	}
 	if m.Name != "" {
-		if _, err = tx.Exec(updateMovieName, id, m.Name); err != nil {
+		if err = execAffected(tx, ErrUnknownMovie, updateMovieName, id, m.Name); err != nil {
 			return errors.Join(wrapErr, err)
 		}
 	}
 	if m.Description != "" {
-		if _, err = tx.Exec(updateMovieDescription, id, m.Description); err != nil {
+		if err = execAffected(tx, ErrUnknownMovie, updateMovieDescription, id, m.Description); err != nil {
 			return errors.Join(wrapErr, err)
 		}
 	}
 	if !m.ReleaseDate.IsZero() {
-		if _, err = tx.Exec(updateMovieReleaseDate, id, m.ReleaseDate); err != nil {
+		if err = execAffected(tx, ErrUnknownMovie, updateMovieReleaseDate, id, m.ReleaseDate); err != nil {
 			return errors.Join(wrapErr, err)
 		}
 	}
 	if m.Rating != nil {
-		if _, err = tx.Exec(updateMovieRating, id, *m.Rating); err != nil {
+		if err = execAffected(tx, ErrUnknownMovie, updateMovieRating, id, *m.Rating); err != nil {
+			return errors.Join(wrapErr, err)
+		}
+	}
+	if !m.MovieMeta.IsZero() {
+		if err = setMovieMeta(tx, id, m.MovieMeta); err != nil {
 			return errors.Join(wrapErr, err)
 		}
 	}
 
-	if m.Actors != nil {
-		if _, err = tx.Exec(removeMovieFromActors, id); err != nil {
+	if m.Credits != nil || m.HasCast() {
+		// Credits заменяет всех участников фильма, а Cast или Actors без Credits - только актёров.
+		remove := removeMovieFromActors
+		if m.Credits != nil {
+			remove = removeMovieCredits
+		}
+		if _, err = tx.Exec(remove, id); err != nil {
 			return errors.Join(wrapErr, err)
 		}
-		for _, aId := range m.Actors {
-			err = errors.Join(d.addActorToMovie(tx, aId, id))
-			if err != nil {
+		for _, c := range m.CastList() {
+			if err = d.addCastToMovie(tx, c, id); err != nil {
+				return errors.Join(wrapErr, err)
+			}
+		}
+		for _, c := range m.CrewList() {
+			if err = d.addCreditToMovie(tx, c, id); err != nil {
 				return errors.Join(wrapErr, err)
 			}
 		}
 	}
 
+	if m.Genres != nil {
+		if _, err = tx.Exec(removeMovieGenres, id); err != nil {
+			return errors.Join(wrapErr, err)
+		}
+		for _, gId := range m.Genres {
+			if err = d.addGenreToMovie(tx, gId, id); err != nil {
+				return errors.Join(wrapErr, err)
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Join(wrapErr, errCommitTx, err)
+	}
+
+	return nil
+}
+
+// UpdateUser - обновление ролей пользователя в БД.
+func (d dbProcessor) UpdateUser(id int, u models.UserPatch) error {
+	wrapErr := fmt.Errorf("error while updating user %d", id)
+	tx, err := d.db.Beginx()
+	if err != nil {
+		return errors.Join(wrapErr, errBeginTx, err)
+	}
+	defer tx.Rollback()
+
+	roles := u.RoleNames()
+	if roles == nil {
+		if err = lockRow(tx, ErrUnknownUser, lockUser, id); err != nil {
+			return errors.Join(wrapErr, err)
+		}
+	} else {
+		res, err := tx.Exec(updateUserIsAdmin, id, slices.Contains(roles, models.RoleAdmin))
+		if err == nil {
+			err = checkAffected(res, ErrUnknownUser)
+		}
+		if err == nil {
+			_, err = tx.Exec(removeUserRoles, id)
+		}
+		if err == nil {
+			err = d.setUserRoles(tx, id, roles)
+		}
+		if err != nil {
+			return errors.Join(wrapErr, err)
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		return errors.Join(wrapErr, errCommitTx, err)
 	}
+	return nil
+}
+
+// UpdateUserPassword - обновление пароля пользователя в БД.
+//
+// Сессии пользователя отзываются.
+func (d dbProcessor) UpdateUserPassword(name, password string) error {
+	wrapErr := fmt.Errorf("error while updating password of user %s", name)
+	tx, err := d.db.Begin()
+	if err != nil {
+		return errors.Join(wrapErr, errBeginTx, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(updateUserPassword, name, password)
+	if err == nil {
+		err = checkAffected(res, ErrUnknownUser)
+	}
+	if err != nil {
+		return errors.Join(wrapErr, err)
+	}
+	if _, err = tx.Exec(removeUserSessionsByName, name); err != nil {
+		return errors.Join(wrapErr, err)
+	}
 
+	if err = tx.Commit(); err != nil {
+		return errors.Join(wrapErr, errCommitTx, err)
+	}
 	return nil
 }
 
-// addActorToMovie - добавление актёра в фильм.
-func (d dbProcessor) addActorToMovie(tx *sqlx.Tx, actorId, movieId int) error {
-	_, err := tx.Exec(addActorToMovie, movieId, actorId)
+// addCastToMovie - добавление роли актёра в фильм.
+func (d dbProcessor) addCastToMovie(tx *sqlx.Tx, c models.CastMember, movieId int) error {
+	_, err := tx.Exec(addCastToMovie, movieId, c.ActorId, c.Character, c.Billing, c.Cameo, c.Voice)
+	return creditError(err, models.Credit{PersonId: c.ActorId, Role: models.CreditActor}, movieId)
+}
+
+// addCreditToMovie - добавление участника, кроме актёра, в фильм.
+func (d dbProcessor) addCreditToMovie(tx *sqlx.Tx, c models.Credit, movieId int) error {
+	_, err := tx.Exec(addCreditToMovie, movieId, c.PersonId, c.Role)
+	return creditError(err, c, movieId)
+}
+
+// creditError - получение ошибки добавления участника в фильм.
+//
+// Принимает: ошибку запроса, участника и id фильма.
+//
+// Возвращает: ошибку с причиной или nil, если ошибки запроса нет.
+func creditError(err error, c models.Credit, movieId int) error {
 	if err != nil {
-		return errors.Join(fmt.Errorf("error while adding actor %d to movie %d", actorId, movieId), err)
+		switch {
+		case isForeignKeyViolation(err) && strings.Contains(violatedConstraint(err), "movie_id"):
+			err = errors.Join(err, ErrUnknownMovie)
+		case isForeignKeyViolation(err):
+			err = errors.Join(err, newKindError(ErrForeignKey, fmt.Sprintf("%s %d does not exist", c.Role, c.PersonId)))
+		default:
+			err = classify(err)
+		}
+		return errors.Join(fmt.Errorf("error while adding %s %d to movie %d", c.Role, c.PersonId, movieId), err)
+	}
+
+	return nil
+}
+
+// setMovieMeta - изменение заданных дополнительных сведений о фильме.
+func setMovieMeta(tx *sqlx.Tx, id int, m models.MovieMeta) error {
+	err := execAffected(tx, ErrUnknownMovie, updateMovieMeta, id, m.Runtime, m.Countries, m.OriginalLanguage, m.Languages,
+		m.AgeRating, m.Budget, m.BoxOffice, m.Currency)
+	if err != nil {
+		return errors.Join(errors.New("error while setting movie's details"), err)
+	}
+	return nil
+}
+
+// addGenreToMovie - добавление жанра фильму.
+func (d dbProcessor) addGenreToMovie(tx *sqlx.Tx, genreId, movieId int) error {
+	_, err := tx.Exec(addGenreToMovie, movieId, genreId)
+	if err != nil {
+		switch {
+		case isForeignKeyViolation(err) && strings.Contains(violatedConstraint(err), "movie_id"):
+			err = errors.Join(err, ErrUnknownMovie)
+		case isForeignKeyViolation(err):
+			err = errors.Join(err, newKindError(ErrForeignKey, fmt.Sprintf("genre %d does not exist", genreId)))
+		default:
+			err = classify(err)
+		}
+		return errors.Join(fmt.Errorf("error while adding genre %d to movie %d", genreId, movieId), err)
 	}
 
 	return nil
@@ -299,7 +639,7 @@ func (d dbProcessor) addSmthWithId(query string, wrap string, args ...any) (int,
 	var id int
 	err = tx.QueryRow(query, args...).Scan(&id)
 	if err != nil {
-		return 0, errors.Join(wrapErr, err)
+		return 0, errors.Join(wrapErr, classify(err))
 	}
 
 	err = tx.Commit()
@@ -310,8 +650,79 @@ func (d dbProcessor) addSmthWithId(query string, wrap string, args ...any) (int,
 	return id, nil
 }
 
+// likeEscaper - экранирование спецсимволов шаблона LIKE.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern - получение шаблона ILIKE для поиска подстроки.
+//
+// Принимает: подстроку.
+//
+// Возвращает: шаблон, в котором символы %, _ и \ подстроки совпадают только сами с собой.
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
+
+// nameArgs - получение аргументов запросов поиска по имени.
+//
+// Принимает: искомое имя.
+//
+// Возвращает: шаблон ILIKE и варианты написания имени: исходный, латиницей и кириллицей.
+func nameArgs(name string) []any {
+	return []any{containsPattern(name), name, translit.ToLatin(name), translit.ToCyrillic(name)}
+}
+
+// selectByName - получение страницы элементов по шаблону имени или его триграммной схожести
+// с вариантами написания, отсортированной по убыванию схожести.
+//
+// Принимает: обработчик БД, запрос страницы и запрос подсчёта, поиск по имени и страницу.
+//
+// Возвращает: элементы страницы, общее количество найденных элементов и ошибку.
+//
+// Порог схожести устанавливается только для транзакции, в которой выполняются запросы.
+func selectByName[T any](d dbProcessor, query, countQuery string, n models.NameSearch, page models.Page) ([]T, int, error) {
+	tx, err := d.db.Beginx()
+	if err != nil {
+		return nil, 0, errors.Join(errBeginTx, err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec(setSimilarity, strconv.FormatFloat(n.Similarity, 'f', -1, 64)); err != nil {
+		return nil, 0, errors.Join(errors.New("error while setting similarity threshold"), err)
+	}
+	args := nameArgs(n.Name)
+	var res []T
+	if err = tx.Select(&res, query, append(args, page.Limit, page.Offset)...); err != nil {
+		return nil, 0, err
+	}
+	total, err := countSmth(tx, page, len(res), countQuery, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, 0, errors.Join(errCommitTx, err)
+	}
+	return res, total, nil
+}
+
+// countSmth - получение общего количества элементов списка.
+//
+// Принимает: БД или транзакцию, запрошенную страницу, количество полученных элементов, запрос подсчёта и его аргументы.
+//
+// Запрос подсчёта выполняется, только если количество нельзя вычислить по неполной странице.
+func countSmth(q sqlx.Queryer, page models.Page, got int, query string, args ...any) (int, error) {
+	if got < page.Limit && (got > 0 || page.Offset == 0) {
+		return page.Offset + got, nil
+	}
+	var total int
+	if err := sqlx.Get(q, &total, query, args...); err != nil {
+		return 0, errors.Join(errors.New("error while counting rows"), err)
+	}
+	return total, nil
+}
+
 // deleteSmth - удаление чего-либо из БД.
-func (d dbProcessor) deleteSmth(query, errTxt string, args ...any) error {
+func (d dbProcessor) deleteSmth(query, errTxt string, notFound error, args ...any) error {
 	wrapErr := errors.New(errTxt)
 	tx, err := d.db.Begin()
 	if err != nil {
@@ -319,9 +730,12 @@ func (d dbProcessor) deleteSmth(query, errTxt string, args ...any) error {
 	}
 	defer tx.Rollback()
 
-	_, err = tx.Exec(query, args...)
+	res, err := tx.Exec(query, args...)
+	if err == nil {
+		err = checkAffected(res, notFound)
+	}
 	if err != nil {
-		return errors.Join(wrapErr, err)
+		return errors.Join(wrapErr, classify(err))
 	}
 
 	err = tx.Commit()
@@ -332,14 +746,151 @@ func (d dbProcessor) deleteSmth(query, errTxt string, args ...any) error {
 	return nil
 }
 
-// fillMovies - заполнение фильмов актёрами.
+// movieActor - строка роли актёра в фильме.
+type movieActor struct {
+	ActorId int `db:"actor_id"`
+	models.CastRole
+}
+
+// addTo - добавление роли актёру.
+func (r movieActor) addTo(actor *models.ActorOut) {
+	actor.Movies = append(actor.Movies, r.MovieId)
+	actor.Roles = append(actor.Roles, r.CastRole)
+}
+
+// movieCredit - строка участия человека в фильме.
+type movieCredit struct {
+	MovieId int    `db:"movie_id"`
+	Role    string `db:"credit"`
+	models.CastMember
+}
+
+// addTo - добавление участника фильму, актёры также попадают в Actors и Cast.
+func (c movieCredit) addTo(movie *models.MovieOut) {
+	movie.Credits = append(movie.Credits, models.Credit{PersonId: c.ActorId, Role: c.Role})
+	if c.Role == models.CreditActor {
+		movie.Actors = append(movie.Actors, c.ActorId)
+		movie.Cast = append(movie.Cast, c.CastMember)
+	}
+}
+
+// movieGenre - строка связи фильма и жанра.
+type movieGenre struct {
+	MovieId int `db:"movie_id"`
+	GenreId int `db:"genre_id"`
+}
+
+// fillMovies - заполнение фильмов участниками и жанрами.
+//
+// Связи всех фильмов загружаются двумя запросами, независимо от количества фильмов.
 func (d dbProcessor) fillMovies(movies []models.MovieOut) error {
-	for i := range len(movies) {
-		err := d.db.Select(&movies[i].Actors, getMovieActors, movies[i].Id)
-		if err != nil {
-			return err
+	if len(movies) == 0 {
+		return nil
+	}
+	ids := make([]int, len(movies))
+	index := make(map[int]int, len(movies))
+	for i, movie := range movies {
+		ids[i] = movie.Id
+		index[movie.Id] = i
+	}
+
+	var credits []movieCredit
+	if err := d.db.Select(&credits, getMoviesCredits, pq.Array(ids)); err != nil {
+		return errors.Join(errors.New("error while getting movie's actors"), err)
+	}
+	for _, c := range credits {
+		if i, ok := index[c.MovieId]; ok {
+			c.addTo(&movies[i])
+		}
+	}
+
+	var genres []movieGenre
+	if err := d.db.Select(&genres, getMoviesGenres, pq.Array(ids)); err != nil {
+		return errors.Join(errors.New("error while getting movie's genres"), err)
+	}
+	for _, link := range genres {
+		if i, ok := index[link.MovieId]; ok {
+			movies[i].Genres = append(movies[i].Genres, link.GenreId)
+		}
+	}
+
+	return nil
+}
+
+// fillActors - заполнение актёров фильмами.
+//
+// Связи всех актёров загружаются одним запросом, независимо от количества актёров.
+func (d dbProcessor) fillActors(actors []models.ActorOut) error {
+	if len(actors) == 0 {
+		return nil
+	}
+	ids := make([]int, len(actors))
+	index := make(map[int]int, len(actors))
+	for i, actor := range actors {
+		ids[i] = actor.Id
+		index[actor.Id] = i
+	}
+
+	var links []movieActor
+	if err := d.db.Select(&links, getActorsMovies, pq.Array(ids)); err != nil {
+		return err
+	}
+	for _, link := range links {
+		if i, ok := index[link.ActorId]; ok {
+			link.addTo(&actors[i])
 		}
 	}
 
 	return nil
 }
+
+// checkAffected - проверка того, что запрос затронул хотя бы одну строку.
+//
+// Принимает: результат запроса и ошибку, возвращаемую, если строк не затронуто.
+func checkAffected(res sql.Result, notFound error) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// execer - интерфейс транзакции, выполняющей запросы.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// rowQuerier - интерфейс транзакции, выполняющей запросы, возвращающие одну строку.
+type rowQuerier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// lockRow - блокировка записи до конца транзакции с проверкой её существования.
+//
+// Нужна, когда изменение затрагивает только связанные таблицы и не может само обнаружить отсутствие записи.
+//
+// Принимает: транзакцию, ошибку, возвращаемую, если записи нет, запрос блокировки и id записи.
+func lockRow(tx rowQuerier, notFound error, query string, id int) error {
+	var locked int
+	if err := tx.QueryRow(query, id).Scan(&locked); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.Join(err, notFound)
+		}
+		return err
+	}
+	return nil
+}
+
+// execAffected - выполнение запроса с проверкой того, что он затронул хотя бы одну строку.
+//
+// Принимает: транзакцию, ошибку, возвращаемую, если строк не затронуто, запрос и его аргументы.
+func execAffected(tx execer, notFound error, query string, args ...any) error {
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		return classify(err)
+	}
+	return checkAffected(res, notFound)
+}
@@ -1,13 +1,19 @@
 package postgres
 
 import (
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestOverrideDB(t *testing.T) {
@@ -18,6 +24,20 @@ func TestOverrideDB(t *testing.T) {
 	defer mockDB.Close()
 	mock.MatchExpectationsInOrder(false)
 
+	mock.ExpectExec(dropSessions).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(dropCollectionMovies).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(dropCollections).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(dropEpisodeActors).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(dropEpisodes).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(dropSeasons).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(dropSeries).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(dropUserMovies).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(dropReviews).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(dropUserRoles).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(dropRolePermissions).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(dropRoles).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(dropMovieGenres).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(dropGenres).WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec(dropMovieActors).WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec(dropActors).WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec(dropMovies).WillReturnResult(sqlmock.NewResult(0, 0))
@@ -27,11 +47,102 @@ func TestOverrideDB(t *testing.T) {
 	mock.ExpectExec("CREATE TABLE IF NOT EXISTS movies").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("CREATE TABLE IF NOT EXISTS users").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec("CREATE TABLE IF NOT EXISTS movie_actors").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS genres").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS movie_genres").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS reviews").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS user_movies").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS collections").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS collection_movies").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS series").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS seasons").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS episodes").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS episode_actors").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS sessions").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS roles").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS role_permissions").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS user_roles").WillReturnResult(sqlmock.NewResult(0, 0))
 
 	err = overrideDB(mockDB)
 	assert.NoError(t, err)
 }
 
+func TestMigrateRoles(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+		mock.ExpectQuery("to_regclass").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS roles(.+)INSERT INTO user_roles").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = MigrateRoles(mockDB)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("roles are not assigned again", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+		mock.ExpectQuery(userRolesExist).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectExec(strings.Join([]string{createRoles, createRolePermissions, createUserRoles, seedRoles, seedRolePermissions}, " ")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = MigrateRoles(mockDB)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+		errTxt := "migrate error"
+		mock.ExpectQuery("to_regclass").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS roles").WillReturnError(errors.New(errTxt))
+
+		err = MigrateRoles(mockDB)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), errTxt)
+		assert.Contains(t, err.Error(), "error while migrating roles:")
+	})
+}
+
+func TestMigrateUsers(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+		mock.ExpectExec("CREATE UNIQUE INDEX IF NOT EXISTS users_name_key ON users").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = MigrateUsers(mockDB)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("duplicate names", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+		errTxt := "could not create unique index"
+		mock.ExpectExec("CREATE UNIQUE INDEX IF NOT EXISTS users_name_key ON users").WillReturnError(errors.New(errTxt))
+
+		err = MigrateUsers(mockDB)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), errTxt)
+		assert.Contains(t, err.Error(), "error while migrating users:")
+	})
+}
+
 func TestDropTables(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockDB, mock, err := sqlmock.New()
@@ -39,6 +150,20 @@ func TestDropTables(t *testing.T) {
 			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 		}
 		defer mockDB.Close()
+		mock.ExpectExec(dropSessions).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropCollectionMovies).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropCollections).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropEpisodeActors).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropEpisodes).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropSeasons).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropSeries).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropUserMovies).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropReviews).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropUserRoles).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropRolePermissions).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropRoles).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropMovieGenres).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropGenres).WillReturnResult(sqlmock.NewResult(0, 0))
 		mock.ExpectExec(dropMovieActors).WillReturnResult(sqlmock.NewResult(0, 0))
 		mock.ExpectExec(dropActors).WillReturnResult(sqlmock.NewResult(0, 0))
 		mock.ExpectExec(dropMovies).WillReturnResult(sqlmock.NewResult(0, 0))
@@ -55,7 +180,21 @@ func TestDropTables(t *testing.T) {
 		}
 		defer mockDB.Close()
 		errTxt := "drop error"
-		mock.ExpectExec(dropMovieActors).WillReturnError(errors.New(errTxt))
+		mock.ExpectExec(dropSessions).WillReturnError(errors.New(errTxt))
+		mock.ExpectExec(dropCollectionMovies).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropCollections).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropEpisodeActors).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropEpisodes).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropSeasons).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropSeries).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropUserMovies).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropReviews).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropUserRoles).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropRolePermissions).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropRoles).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropMovieGenres).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropGenres).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(dropMovieActors).WillReturnResult(sqlmock.NewResult(0, 0))
 		mock.ExpectExec(dropActors).WillReturnResult(sqlmock.NewResult(0, 0))
 		mock.ExpectExec(dropMovies).WillReturnResult(sqlmock.NewResult(0, 0))
 		mock.ExpectExec(dropUsers).WillReturnResult(sqlmock.NewResult(0, 0))
@@ -78,6 +217,20 @@ func TestCreateTables(t *testing.T) {
 		mock.ExpectExec("CREATE TABLE IF NOT EXISTS movies").WillReturnResult(sqlmock.NewResult(0, 0))
 		mock.ExpectExec("CREATE TABLE IF NOT EXISTS users").WillReturnResult(sqlmock.NewResult(0, 0))
 		mock.ExpectExec("CREATE TABLE IF NOT EXISTS movie_actors").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS genres").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS movie_genres").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS reviews").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS user_movies").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS collections").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS collection_movies").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS series").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS seasons").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS episodes").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS episode_actors").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS sessions").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS roles").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS role_permissions").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS user_roles").WillReturnResult(sqlmock.NewResult(0, 0))
 
 		err = createTables(mockDB)
 		assert.NoError(t, err)
@@ -94,6 +247,20 @@ func TestCreateTables(t *testing.T) {
 		mock.ExpectExec("CREATE TABLE IF NOT EXISTS movies").WillReturnResult(sqlmock.NewResult(0, 0))
 		mock.ExpectExec("CREATE TABLE IF NOT EXISTS users").WillReturnResult(sqlmock.NewResult(0, 0))
 		mock.ExpectExec("CREATE TABLE IF NOT EXISTS movie_actors").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS genres").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS movie_genres").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS reviews").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS user_movies").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS collections").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS collection_movies").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS series").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS seasons").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS episodes").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS episode_actors").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS sessions").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS roles").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS role_permissions").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS user_roles").WillReturnResult(sqlmock.NewResult(0, 0))
 
 		err = createTables(mockDB)
 		assert.Error(t, err)
@@ -187,12 +354,27 @@ func TestAddUser(t *testing.T) {
 		id := 15
 		mock.ExpectBegin()
 		mock.ExpectQuery("INSERT INTO user").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+		mock.ExpectExec("INSERT INTO user_roles").WithArgs(id, models.RoleViewer).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit()
 
 		id, err := processor.AddUser(user)
 		assert.NoError(t, err)
 		assert.Equal(t, id, id)
 	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		user := models.User{Nickname: "user", Roles: []string{"director"}}
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO user").WithArgs("user", "", false).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("INSERT INTO user_roles").WithArgs(1, "director").WillReturnError(&pq.Error{Code: "23503"})
+		mock.ExpectRollback()
+
+		_, err := processor.AddUser(user)
+		assert.ErrorIs(t, err, ErrUnknownRole)
+	})
 }
 
 func TestAddMovie(t *testing.T) {
@@ -216,8 +398,8 @@ func TestAddMovie(t *testing.T) {
 		processor := dbProcessor{db: db}
 		mock.ExpectBegin()
 		mock.ExpectQuery(q1).WithArgs(m.Name, m.Description, m.ReleaseDate, m.Rating).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
-		for _, a := range m.Actors {
-			mock.ExpectExec(q2).WithArgs(1, a).WillReturnResult(sqlmock.NewResult(0, 0))
+		for i, a := range m.Actors {
+			mock.ExpectExec(q2).WithArgs(1, a, "", i+1, false, false).WillReturnResult(sqlmock.NewResult(0, 0))
 		}
 		mock.ExpectCommit()
 
@@ -226,6 +408,40 @@ func TestAddMovie(t *testing.T) {
 		assert.Equal(t, 1, id)
 	})
 
+	t.Run("success with genres", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		withGenres := m
+		withGenres.Genres = []int{4}
+		mock.ExpectBegin()
+		mock.ExpectQuery(q1).WithArgs(m.Name, m.Description, m.ReleaseDate, m.Rating).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		for i, a := range m.Actors {
+			mock.ExpectExec(q2).WithArgs(1, a, "", i+1, false, false).WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+		mock.ExpectExec("INSERT INTO movie_genres").WithArgs(1, 4).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		id, err := processor.AddMovie(withGenres)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown genre", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery(q1).WithArgs("title", "", time.Time{}, 0).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("INSERT INTO movie_genres").WithArgs(1, 4).WillReturnError(&pq.Error{Code: "23503", Constraint: "movie_genres_genre_id_fkey"})
+		mock.ExpectRollback()
+
+		_, err := processor.AddMovie(models.MovieIn{Name: "title", Rating: new(int), Genres: []int{4}})
+		assert.ErrorIs(t, err, ErrForeignKey)
+		assert.Contains(t, err.Error(), "genre 4 does not exist")
+	})
+
 	t.Run("error while starting", func(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
@@ -262,8 +478,8 @@ func TestAddMovie(t *testing.T) {
 		errTxt := "insert error"
 		mock.ExpectBegin()
 		mock.ExpectQuery(q1).WithArgs(m.Name, m.Description, m.ReleaseDate, m.Rating).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
-		mock.ExpectExec(q2).WithArgs(1, m.Actors[0]).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec(q2).WithArgs(1, m.Actors[1]).WillReturnError(errors.New(errTxt))
+		mock.ExpectExec(q2).WithArgs(1, m.Actors[0], "", 1, false, false).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(q2).WithArgs(1, m.Actors[1], "", 2, false, false).WillReturnError(errors.New(errTxt))
 		mock.ExpectRollback()
 
 		_, err := processor.AddMovie(m)
@@ -279,8 +495,8 @@ func TestAddMovie(t *testing.T) {
 		errTxt := "commit error"
 		mock.ExpectBegin()
 		mock.ExpectQuery(q1).WithArgs(m.Name, m.Description, m.ReleaseDate, m.Rating).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
-		for _, a := range m.Actors {
-			mock.ExpectExec(q2).WithArgs(1, a).WillReturnResult(sqlmock.NewResult(0, 0))
+		for i, a := range m.Actors {
+			mock.ExpectExec(q2).WithArgs(1, a, "", i+1, false, false).WillReturnResult(sqlmock.NewResult(0, 0))
 		}
 		mock.ExpectCommit().WillReturnError(errors.New(errTxt))
 
@@ -302,10 +518,11 @@ func TestGetActor(t *testing.T) {
 			Name:        "name",
 			DateOfBirth: time.Time{},
 			Movies:      []int{1},
+			Roles:       []models.CastRole{{MovieId: 1, Character: "Шерлок Холмс", Billing: 1}},
 		}
 
 		mock.ExpectQuery("SELECT").WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "date_of_birth"}).AddRow(actor.Id, actor.Name, actor.DateOfBirth))
-		mock.ExpectQuery("SELECT").WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery("SELECT").WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"movie_id", "character_name", "billing", "cameo", "voice"}).AddRow(1, "Шерлок Холмс", 1, false, false))
 
 		a, err := processor.GetActor(id)
 		assert.NoError(t, err)
@@ -341,6 +558,17 @@ func TestGetActor(t *testing.T) {
 		assert.Contains(t, err.Error(), "error while getting actor 15")
 		assert.Contains(t, err.Error(), "error while getting actors's movies")
 	})
+
+	t.Run("unknown actor", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("SELECT").WithArgs(15).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "gender", "date_of_birth"}))
+
+		_, err := processor.GetActor(15)
+		assert.ErrorIs(t, err, ErrUnknownActor)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
 }
 
 func TestGetActors(t *testing.T) {
@@ -348,27 +576,30 @@ func TestGetActors(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
 		processor := dbProcessor{db: db}
+		page := models.Page{Limit: 10}
 		actors := []models.ActorOut{
 			{
 				Id:          1,
 				Name:        "name1",
 				DateOfBirth: time.Time{},
 				Movies:      []int{1},
+				Roles:       []models.CastRole{{MovieId: 1}},
 			},
 			{
 				Id:          2,
 				Name:        "name2",
 				DateOfBirth: time.Time{},
 				Movies:      []int{2},
+				Roles:       []models.CastRole{{MovieId: 2}},
 			},
 		}
 
-		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "date_of_birth"}).AddRow(actors[0].Id, actors[0].Name, actors[0].DateOfBirth).AddRow(actors[1].Id, actors[1].Name, actors[1].DateOfBirth))
-		mock.ExpectQuery("SELECT").WithArgs(actors[0].Id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
-		mock.ExpectQuery("SELECT").WithArgs(actors[1].Id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectQuery("SELECT").WithArgs(page.Limit, page.Offset).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "date_of_birth"}).AddRow(actors[0].Id, actors[0].Name, actors[0].DateOfBirth).AddRow(actors[1].Id, actors[1].Name, actors[1].DateOfBirth))
+		mock.ExpectQuery("SELECT").WithArgs("{1,2}").WillReturnRows(sqlmock.NewRows([]string{"actor_id", "movie_id"}).AddRow(1, 1).AddRow(2, 2))
 
-		a, err := processor.GetActors()
+		a, total, err := processor.GetActors(models.DefaultActorSort, page)
 		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
 		assert.Equal(t, actors, a)
 	})
 
@@ -376,10 +607,11 @@ func TestGetActors(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
 		processor := dbProcessor{db: db}
+		page := models.Page{Limit: 10}
 		errTxt := "select error"
-		mock.ExpectQuery("SELECT").WillReturnError(errors.New(errTxt))
+		mock.ExpectQuery("SELECT").WithArgs(page.Limit, page.Offset).WillReturnError(errors.New(errTxt))
 
-		_, err := processor.GetActors()
+		_, _, err := processor.GetActors(models.DefaultActorSort, page)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), errTxt)
 		assert.Contains(t, err.Error(), "error while getting actors")
@@ -389,34 +621,282 @@ func TestGetActors(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
 		processor := dbProcessor{db: db}
+		page := models.Page{Limit: 10}
 		errTxt := "select error"
-		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "date_of_birth"}).AddRow(1, "name", time.Time{}))
-		mock.ExpectQuery("SELECT").WithArgs(1).WillReturnError(errors.New(errTxt))
+		mock.ExpectQuery("SELECT").WithArgs(page.Limit, page.Offset).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "date_of_birth"}).AddRow(1, "name", time.Time{}))
+		mock.ExpectQuery("SELECT").WithArgs("{1}").WillReturnError(errors.New(errTxt))
 
-		_, err := processor.GetActors()
+		_, _, err := processor.GetActors(models.DefaultActorSort, page)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), errTxt)
 		assert.Contains(t, err.Error(), "error while getting actors' movies")
 	})
 }
 
+func TestGetActorsByName(t *testing.T) {
+	search := models.NameSearch{Name: "Bezrukov", Similarity: 0.4}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		page := models.Page{Limit: 1}
+		actors := []models.ActorOut{{Id: 1, Name: "Сергей Безруков", Movies: []int{3}, Roles: []models.CastRole{{MovieId: 3}}}}
+		mock.ExpectBegin()
+		mock.ExpectExec("set_config").WithArgs("0.4").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT (.+) FROM actors WHERE name ILIKE").WithArgs("%Bezrukov%", "Bezrukov", "Bezrukov", "Безруков", page.Limit, page.Offset).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "date_of_birth"}).AddRow(1, "Сергей Безруков", time.Time{}))
+		mock.ExpectQuery("SELECT COUNT").WithArgs("%Bezrukov%", "Bezrukov", "Bezrukov", "Безруков").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+		mock.ExpectCommit()
+		mock.ExpectQuery("SELECT").WithArgs("{1}").WillReturnRows(sqlmock.NewRows([]string{"actor_id", "movie_id"}).AddRow(1, 3))
+
+		a, total, err := processor.GetActorsByName(search, page)
+		assert.NoError(t, err)
+		assert.Equal(t, actors, a)
+		assert.Equal(t, 4, total)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("escaped pattern", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		errTxt := "select error"
+		mock.ExpectBegin()
+		mock.ExpectExec("set_config").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT").WithArgs(`%50\%%`, "50%", "50%", "50%", 10, 0).WillReturnError(errors.New(errTxt))
+		mock.ExpectRollback()
+
+		_, _, err := processor.GetActorsByName(models.NameSearch{Name: "50%", Similarity: 0.4}, models.Page{Limit: 10})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), errTxt)
+		assert.Contains(t, err.Error(), "error while getting actors by name")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error while setting similarity", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		errTxt := "set error"
+		mock.ExpectBegin()
+		mock.ExpectExec("set_config").WillReturnError(errors.New(errTxt))
+		mock.ExpectRollback()
+
+		_, _, err := processor.GetActorsByName(search, models.Page{Limit: 10})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), errTxt)
+		assert.Contains(t, err.Error(), "error while setting similarity threshold")
+	})
+
+	t.Run("error while starting transaction", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin().WillReturnError(errors.New("begin error"))
+
+		_, _, err := processor.GetActorsByName(search, models.Page{Limit: 10})
+		assert.ErrorIs(t, err, errBeginTx)
+	})
+}
+func TestContainsPattern(t *testing.T) {
+	assert.Equal(t, "%john%", containsPattern("john"))
+	assert.Equal(t, `%100\%\_\\%`, containsPattern(`100%_\`))
+	assert.Equal(t, "%%", containsPattern(""))
+}
+
+func TestSearch(t *testing.T) {
+	query := models.SearchQuery{Query: "matrix", Limit: 10}
+
+	t.Run("highlights are built from escaped text", func(t *testing.T) {
+		escaped := regexp.MustCompile(`ts_headline\('russian', replace\(replace\(replace\(replace\([a-z.]+, '&', '&amp;'\), '<', '&lt;'\), '>', '&gt;'\), '"', '&quot;'\)`)
+		assert.Len(t, escaped.FindAllString(searchMovies, -1), 2)
+		assert.Len(t, escaped.FindAllString(searchActors, -1), 1)
+		assert.Equal(t, 2, strings.Count(searchMovies, "ts_headline"))
+		assert.Equal(t, 1, strings.Count(searchActors, "ts_headline"))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("SELECT (.+) FROM q, movies m").WithArgs(query.Query, query.Limit).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "release_date", "rating", "rank", "name_highlight", "description_highlight"}).AddRow(1, "The Matrix", time.Time{}, 9, 0.5, "The <b>Matrix</b>", "<b>Matrix</b> is everywhere"))
+		mock.ExpectQuery("SELECT (.+) FROM q, actors a").WithArgs(query.Query, query.Limit).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "rank", "name_highlight"}))
+
+		res, err := processor.Search(query)
+		assert.NoError(t, err)
+		assert.Equal(t, models.SearchResult{
+			Movies: []models.MovieHit{{Id: 1, Name: "The Matrix", Rating: 9, Rank: 0.5, NameHighlight: "The <b>Matrix</b>", DescriptionHighlight: "<b>Matrix</b> is everywhere"}},
+			Actors: []models.ActorHit{},
+		}, res)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error while searching movies", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		errTxt := "select error"
+		mock.ExpectQuery("SELECT").WillReturnError(errors.New(errTxt))
+
+		_, err := processor.Search(query)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), errTxt)
+		assert.Contains(t, err.Error(), "error while searching movies")
+	})
+
+	t.Run("error while searching actors", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		errTxt := "select error"
+		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery("SELECT").WillReturnError(errors.New(errTxt))
+
+		_, err := processor.Search(query)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), errTxt)
+		assert.Contains(t, err.Error(), "error while searching actors")
+	})
+}
+
+func TestSuggest(t *testing.T) {
+	t.Run("escaped prefix", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("SELECT id, name FROM movies").WithArgs(`100\%%`, `% 100\%%`, 5).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "100% Love"))
+
+		res, err := processor.Suggest(models.SuggestQuery{Prefix: "100%", Type: models.SuggestMovie, Limit: 5})
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Suggestion{{Id: 1, Name: "100% Love"}}, res)
+	})
+
+	t.Run("error while getting suggestions", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		errTxt := "select error"
+		mock.ExpectQuery("SELECT id, name FROM actors").WillReturnError(errors.New(errTxt))
+
+		_, err := processor.Suggest(models.SuggestQuery{Prefix: "Bra", Type: models.SuggestActor, Limit: 5})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), errTxt)
+		assert.Contains(t, err.Error(), "error while getting actor suggestions")
+	})
+}
+
+func TestCountSmth(t *testing.T) {
+	t.Run("total from incomplete page", func(t *testing.T) {
+		db, _, _ := sqlmock.Newx()
+		defer db.Close()
+
+		total, err := countSmth(db, models.Page{Limit: 10, Offset: 20}, 3, countMovies)
+		assert.NoError(t, err)
+		assert.Equal(t, 23, total)
+	})
+
+	t.Run("count full page", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		mock.ExpectQuery("SELECT COUNT").WithArgs("name").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+		total, err := countSmth(db, models.Page{Limit: 10}, 10, countMoviesByName, "name")
+		assert.NoError(t, err)
+		assert.Equal(t, 42, total)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("count empty page after offset", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		mock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+		total, err := countSmth(db, models.Page{Limit: 10, Offset: 100}, 0, countActors)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, total)
+	})
+
+	t.Run("count error", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		errTxt := "count error"
+		mock.ExpectQuery("SELECT COUNT").WillReturnError(errors.New(errTxt))
+
+		_, err := countSmth(db, models.Page{Limit: 10}, 10, countActors)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), errTxt)
+		assert.Contains(t, err.Error(), "error while counting rows")
+	})
+}
+
+func TestFillMovies(t *testing.T) {
+	t.Run("group relations by movie", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		movies := []models.MovieOut{{Id: 3}, {Id: 1}, {Id: 2}}
+		mock.ExpectQuery("SELECT").WithArgs("{3,1,2}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "actor_id", "credit"}).AddRow(1, 4, models.CreditActor).AddRow(1, 5, models.CreditActor).AddRow(1, 6, models.CreditComposer).AddRow(3, 4, models.CreditActor))
+		mock.ExpectQuery("SELECT").WithArgs("{3,1,2}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "genre_id"}).AddRow(2, 1).AddRow(3, 2))
+
+		assert.NoError(t, processor.fillMovies(movies))
+		actor := func(id int) models.Credit { return models.Credit{PersonId: id, Role: models.CreditActor} }
+		assert.Equal(t, []models.MovieOut{
+			{Id: 3, Actors: []int{4}, Credits: []models.Credit{actor(4)}, Cast: []models.CastMember{{ActorId: 4}}, Genres: []int{2}},
+			{Id: 1, Actors: []int{4, 5}, Credits: []models.Credit{actor(4), actor(5), {PersonId: 6, Role: models.CreditComposer}},
+				Cast: []models.CastMember{{ActorId: 4}, {ActorId: 5}}},
+			{Id: 2, Genres: []int{1}},
+		}, movies)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no movies", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		assert.NoError(t, processor.fillMovies(nil))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestFillActors(t *testing.T) {
+	db, mock, _ := sqlmock.Newx()
+	defer db.Close()
+	processor := dbProcessor{db: db}
+	actors := []models.ActorOut{{Id: 1}, {Id: 2}}
+	mock.ExpectQuery("SELECT").WithArgs("{1,2}").WillReturnRows(sqlmock.NewRows([]string{"actor_id", "movie_id"}).AddRow(2, 7).AddRow(2, 8))
+
+	assert.NoError(t, processor.fillActors(actors))
+	assert.Equal(t, []models.ActorOut{{Id: 1}, {Id: 2, Movies: []int{7, 8}, Roles: []models.CastRole{{MovieId: 7}, {MovieId: 8}}}}, actors)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetMovie(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
 		processor := dbProcessor{db: db}
 		id := 15
+		userRating := 7.5
 		movie := models.MovieOut{
 			Id:          id,
 			Name:        "name",
 			Description: "description",
 			ReleaseDate: time.Time{},
 			Rating:      5,
+			UserRating:  &userRating,
+			Votes:       2,
 			Actors:      []int{1},
+			Credits:     []models.Credit{{PersonId: 1, Role: models.CreditActor}, {PersonId: 4, Role: models.CreditDirector}},
+			Cast:        []models.CastMember{{ActorId: 1, Character: "Нео", Billing: 1, Voice: true}},
+			Genres:      []int{2, 3},
 		}
 
-		mock.ExpectQuery("SELECT").WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(movie.Id, movie.Name, movie.Description, movie.ReleaseDate, movie.Rating))
-		mock.ExpectQuery("SELECT").WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery("SELECT").WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating", "user_rating", "votes"}).
+			AddRow(movie.Id, movie.Name, movie.Description, movie.ReleaseDate, movie.Rating, userRating, movie.Votes))
+		mock.ExpectQuery("SELECT").WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"actor_id", "credit", "character_name", "billing", "cameo", "voice"}).
+			AddRow(1, models.CreditActor, "Нео", 1, false, true).AddRow(4, models.CreditDirector, "", 0, false, false))
+		mock.ExpectQuery("SELECT").WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"genre_id"}).AddRow(2).AddRow(3))
 
 		m, err := processor.GetMovie(id)
 		assert.NoError(t, err)
@@ -437,6 +917,17 @@ func TestGetMovie(t *testing.T) {
 		assert.Contains(t, err.Error(), "error while getting movie")
 	})
 
+	t.Run("unknown movie", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("SELECT").WithArgs(15).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}))
+
+		_, err := processor.GetMovie(15)
+		assert.ErrorIs(t, err, ErrUnknownMovie)
+		assert.Contains(t, err.Error(), "error while getting movie 15")
+	})
+
 	t.Run("error while getting actors", func(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
@@ -459,6 +950,7 @@ func TestGetMovies(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
 		processor := dbProcessor{db: db}
+		page := models.Page{Limit: 10}
 		movies := []models.MovieOut{
 			{
 				Id:          1,
@@ -467,6 +959,8 @@ func TestGetMovies(t *testing.T) {
 				ReleaseDate: time.Time{},
 				Rating:      5,
 				Actors:      []int{1},
+				Credits:     []models.Credit{{PersonId: 1, Role: models.CreditActor}},
+				Cast:        []models.CastMember{{ActorId: 1}},
 			},
 			{
 				Id:          2,
@@ -475,15 +969,18 @@ func TestGetMovies(t *testing.T) {
 				ReleaseDate: time.Time{},
 				Rating:      4,
 				Actors:      []int{2},
+				Credits:     []models.Credit{{PersonId: 2, Role: models.CreditActor}},
+				Cast:        []models.CastMember{{ActorId: 2}},
 			},
 		}
 
-		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(movies[0].Id, movies[0].Name, movies[0].Description, movies[0].ReleaseDate, movies[0].Rating).AddRow(movies[1].Id, movies[1].Name, movies[1].Description, movies[1].ReleaseDate, movies[1].Rating))
-		mock.ExpectQuery("SELECT").WithArgs(movies[0].Id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
-		mock.ExpectQuery("SELECT").WithArgs(movies[1].Id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectQuery("SELECT").WithArgs(page.Limit, page.Offset).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(movies[0].Id, movies[0].Name, movies[0].Description, movies[0].ReleaseDate, movies[0].Rating).AddRow(movies[1].Id, movies[1].Name, movies[1].Description, movies[1].ReleaseDate, movies[1].Rating))
+		mock.ExpectQuery("SELECT").WithArgs("{1,2}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "actor_id", "credit"}).AddRow(1, 1, models.CreditActor).AddRow(2, 2, models.CreditActor))
+		mock.ExpectQuery("SELECT").WithArgs("{1,2}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "genre_id"}))
 
-		m, err := processor.GetMovies(models.SortByRating)
+		m, total, err := processor.GetMovies(models.DefaultMovieSort, models.MovieFilter{}, page)
 		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
 		assert.Equal(t, movies, m)
 	})
 
@@ -491,6 +988,7 @@ func TestGetMovies(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
 		processor := dbProcessor{db: db}
+		page := models.Page{Limit: 10}
 		movies := []models.MovieOut{
 			{
 				Id:          1,
@@ -499,6 +997,8 @@ func TestGetMovies(t *testing.T) {
 				ReleaseDate: time.Time{},
 				Rating:      5,
 				Actors:      []int{1},
+				Credits:     []models.Credit{{PersonId: 1, Role: models.CreditActor}},
+				Cast:        []models.CastMember{{ActorId: 1}},
 			},
 			{
 				Id:          2,
@@ -507,15 +1007,18 @@ func TestGetMovies(t *testing.T) {
 				ReleaseDate: time.Time{},
 				Rating:      4,
 				Actors:      []int{2},
+				Credits:     []models.Credit{{PersonId: 2, Role: models.CreditActor}},
+				Cast:        []models.CastMember{{ActorId: 2}},
 			},
 		}
 
-		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(movies[0].Id, movies[0].Name, movies[0].Description, movies[0].ReleaseDate, movies[0].Rating).AddRow(movies[1].Id, movies[1].Name, movies[1].Description, movies[1].ReleaseDate, movies[1].Rating))
-		mock.ExpectQuery("SELECT").WithArgs(movies[0].Id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
-		mock.ExpectQuery("SELECT").WithArgs(movies[1].Id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectQuery("SELECT").WithArgs(page.Limit, page.Offset).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(movies[0].Id, movies[0].Name, movies[0].Description, movies[0].ReleaseDate, movies[0].Rating).AddRow(movies[1].Id, movies[1].Name, movies[1].Description, movies[1].ReleaseDate, movies[1].Rating))
+		mock.ExpectQuery("SELECT").WithArgs("{1,2}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "actor_id", "credit"}).AddRow(1, 1, models.CreditActor).AddRow(2, 2, models.CreditActor))
+		mock.ExpectQuery("SELECT").WithArgs("{1,2}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "genre_id"}))
 
-		m, err := processor.GetMovies(models.SortByName)
+		m, total, err := processor.GetMovies(models.Sort{{Field: "name"}}, models.MovieFilter{}, page)
 		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
 		assert.Equal(t, movies, m)
 	})
 
@@ -523,6 +1026,7 @@ func TestGetMovies(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
 		processor := dbProcessor{db: db}
+		page := models.Page{Limit: 10}
 		movies := []models.MovieOut{
 			{
 				Id:          1,
@@ -531,6 +1035,8 @@ func TestGetMovies(t *testing.T) {
 				ReleaseDate: time.Time{},
 				Rating:      5,
 				Actors:      []int{1},
+				Credits:     []models.Credit{{PersonId: 1, Role: models.CreditActor}},
+				Cast:        []models.CastMember{{ActorId: 1}},
 			},
 			{
 				Id:          2,
@@ -539,15 +1045,18 @@ func TestGetMovies(t *testing.T) {
 				ReleaseDate: time.Time{},
 				Rating:      4,
 				Actors:      []int{2},
+				Credits:     []models.Credit{{PersonId: 2, Role: models.CreditActor}},
+				Cast:        []models.CastMember{{ActorId: 2}},
 			},
 		}
 
-		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(movies[0].Id, movies[0].Name, movies[0].Description, movies[0].ReleaseDate, movies[0].Rating).AddRow(movies[1].Id, movies[1].Name, movies[1].Description, movies[1].ReleaseDate, movies[1].Rating))
-		mock.ExpectQuery("SELECT").WithArgs(movies[0].Id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
-		mock.ExpectQuery("SELECT").WithArgs(movies[1].Id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectQuery("SELECT").WithArgs(page.Limit, page.Offset).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(movies[0].Id, movies[0].Name, movies[0].Description, movies[0].ReleaseDate, movies[0].Rating).AddRow(movies[1].Id, movies[1].Name, movies[1].Description, movies[1].ReleaseDate, movies[1].Rating))
+		mock.ExpectQuery("SELECT").WithArgs("{1,2}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "actor_id", "credit"}).AddRow(1, 1, models.CreditActor).AddRow(2, 2, models.CreditActor))
+		mock.ExpectQuery("SELECT").WithArgs("{1,2}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "genre_id"}))
 
-		m, err := processor.GetMovies(models.SortByReleaseDate)
+		m, total, err := processor.GetMovies(models.Sort{{Field: "release_date"}}, models.MovieFilter{}, page)
 		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
 		assert.Equal(t, movies, m)
 	})
 
@@ -555,10 +1064,11 @@ func TestGetMovies(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
 		processor := dbProcessor{db: db}
+		page := models.Page{Limit: 10}
 		errTxt := "select error"
-		mock.ExpectQuery("SELECT").WillReturnError(errors.New(errTxt))
+		mock.ExpectQuery("SELECT").WithArgs(page.Limit, page.Offset).WillReturnError(errors.New(errTxt))
 
-		_, err := processor.GetMovies(models.SortByRating)
+		_, _, err := processor.GetMovies(models.DefaultMovieSort, models.MovieFilter{}, page)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), errTxt)
 		assert.Contains(t, err.Error(), "error while getting movies")
@@ -568,11 +1078,12 @@ func TestGetMovies(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
 		processor := dbProcessor{db: db}
+		page := models.Page{Limit: 10}
 		errTxt := "select error"
-		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(1, "name", "description", time.Time{}, 5))
-		mock.ExpectQuery("SELECT").WithArgs(1).WillReturnError(errors.New(errTxt))
+		mock.ExpectQuery("SELECT").WithArgs(page.Limit, page.Offset).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(1, "name", "description", time.Time{}, 5))
+		mock.ExpectQuery("SELECT").WithArgs("{1}").WillReturnError(errors.New(errTxt))
 
-		_, err := processor.GetMovies(models.SortByRating)
+		_, _, err := processor.GetMovies(models.DefaultMovieSort, models.MovieFilter{}, page)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), errTxt)
 		assert.Contains(t, err.Error(), "error while getting movie's actors")
@@ -580,86 +1091,223 @@ func TestGetMovies(t *testing.T) {
 	})
 }
 
-func TestGetMoviesByActor(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
-		db, mock, _ := sqlmock.Newx()
-		defer db.Close()
-		processor := dbProcessor{db: db}
-		actor := "name"
-		movies := []models.MovieOut{
-			{
-				Id:          1,
-				Name:        "name1",
-				Description: "description1",
-				ReleaseDate: time.Time{},
-				Rating:      5,
-				Actors:      []int{1},
-			},
-			{
-				Id:          2,
-				Name:        "name2",
-				Description: "description2",
-				ReleaseDate: time.Time{},
-				Rating:      4,
-				Actors:      []int{2},
-			},
+func TestMovieFilterQuery(t *testing.T) {
+	t.Run("no filter", func(t *testing.T) {
+		query, args := movieFilterQuery(models.MovieFilter{}).build(countMovies, "")
+		assert.Equal(t, "SELECT COUNT(*) FROM movies;", query)
+		assert.Empty(t, args)
+	})
+
+	t.Run("all filters", func(t *testing.T) {
+		minRating, maxRating := 5, 9
+		after, before := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+		filter := models.MovieFilter{
+			MinRating:      &minRating,
+			MaxRating:      &maxRating,
+			ReleasedAfter:  &after,
+			ReleasedBefore: &before,
+			Actors:         []int{3, 1, 3},
+			ActorsMatch:    models.ActorsMatchAll,
+			Name:           "50%",
 		}
 
-		mock.ExpectQuery("SELECT").WithArgs(actor).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(movies[0].Id, movies[0].Name, movies[0].Description, movies[0].ReleaseDate, movies[0].Rating).AddRow(movies[1].Id, movies[1].Name, movies[1].Description, movies[1].ReleaseDate, movies[1].Rating))
-		mock.ExpectQuery("SELECT").WithArgs(movies[0].Id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
-		mock.ExpectQuery("SELECT").WithArgs(movies[1].Id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		query, args := movieFilterQuery(filter).build(selectMovies, "ORDER BY name, id LIMIT ? OFFSET ?", 10, 20)
+		assert.Equal(t, selectMovies+" WHERE rating >= $1 AND rating <= $2 AND release_date >= $3 AND release_date <= $4"+
+			" AND id IN (SELECT movie_id FROM movie_actors WHERE credit = 'actor' AND actor_id = ANY($5) GROUP BY movie_id HAVING COUNT(*) = $6)"+
+			" AND name ILIKE $7 ORDER BY name, id LIMIT $8 OFFSET $9;", query)
+		assert.Equal(t, []any{5, 9, after, before, pq.Array([]int{1, 3}), 2, `%50\%%`, 10, 20}, args)
+	})
 
-		m, err := processor.GetMoviesByActor(actor)
-		assert.NoError(t, err)
-		assert.Equal(t, movies, m)
+	t.Run("any actor", func(t *testing.T) {
+		query, args := movieFilterQuery(models.MovieFilter{Actors: []int{2}}).build(countMovies, "")
+		assert.Equal(t, "SELECT COUNT(*) FROM movies WHERE id IN (SELECT movie_id FROM movie_actors WHERE credit = 'actor' AND actor_id = ANY($1));", query)
+		assert.Equal(t, []any{pq.Array([]int{2})}, args)
 	})
 
-	t.Run("error while getting movies by actor", func(t *testing.T) {
-		db, mock, _ := sqlmock.Newx()
+	t.Run("metadata", func(t *testing.T) {
+		minRuntime, maxBudget := 90, int64(1000000)
+		filter := models.MovieFilter{
+			MinRuntime: &minRuntime,
+			Countries:  []string{"RU", "SU"},
+			Languages:  []string{"ru"},
+			AgeRatings: []string{"12+", "16+"},
+			Currency:   "RUB",
+			MaxBudget:  &maxBudget,
+		}
+
+		query, args := movieFilterQuery(filter).build(countMovies, "")
+		assert.Equal(t, "SELECT COUNT(*) FROM movies WHERE runtime >= $1 AND countries && $2::text[]"+
+			" AND (original_language = ANY($3) OR languages && $4::text[]) AND age_rating = ANY($5) AND currency = $6 AND budget <= $7;", query)
+		assert.Equal(t, []any{90, pq.Array([]string{"RU", "SU"}), pq.Array([]string{"ru"}), pq.Array([]string{"ru"}),
+			pq.Array([]string{"12+", "16+"}), "RUB", int64(1000000)}, args)
+	})
+}
+
+func TestOrderBy(t *testing.T) {
+	t.Run("multiple keys", func(t *testing.T) {
+		order, err := orderBy(models.Sort{{Field: "rating", Desc: true}, {Field: "name"}, {Field: "release_date"}}, movieSortColumns)
+		assert.NoError(t, err)
+		assert.Equal(t, "ORDER BY rating DESC, name, release_date, id", order)
+	})
+
+	t.Run("explicit id", func(t *testing.T) {
+		order, err := orderBy(models.Sort{{Field: "id", Desc: true}}, actorSortColumns)
+		assert.NoError(t, err)
+		assert.Equal(t, "ORDER BY id DESC", order)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := orderBy(models.Sort{{Field: "rating; DROP TABLE movies"}}, movieSortColumns)
+		assert.Error(t, err)
+	})
+}
+
+func TestGetActorsSorted(t *testing.T) {
+	db, mock, _ := sqlmock.Newx()
+	defer db.Close()
+	processor := dbProcessor{db: db}
+	mock.ExpectQuery(`SELECT (.+) FROM actors ORDER BY date_of_birth DESC, name, id LIMIT \$1 OFFSET \$2;`).WithArgs(10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "gender", "date_of_birth"}))
+
+	a, total, err := processor.GetActors(models.Sort{{Field: "date_of_birth", Desc: true}, {Field: "name"}}, models.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, a)
+	assert.Equal(t, 0, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, _, err = processor.GetActors(models.Sort{{Field: "rating"}}, models.Page{Limit: 10})
+	assert.ErrorContains(t, err, "unknown sort field")
+}
+
+func TestGetMoviesFiltered(t *testing.T) {
+	db, mock, _ := sqlmock.Newx()
+	defer db.Close()
+	processor := dbProcessor{db: db}
+	page := models.Page{Limit: 1}
+	minRating := 7
+	filter := models.MovieFilter{MinRating: &minRating, Name: "matrix"}
+	mock.ExpectQuery(`SELECT (.+) FROM movies (.+) v ON true WHERE rating >= \$1 AND name ILIKE \$2 ORDER BY release_date, id LIMIT \$3 OFFSET \$4`).
+		WithArgs(7, "%matrix%", 1, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(1, "The Matrix", "description", time.Time{}, 9))
+	mock.ExpectQuery("SELECT").WithArgs("{1}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "actor_id", "credit"}))
+	mock.ExpectQuery("SELECT").WithArgs("{1}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "genre_id"}))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM movies WHERE rating >= \$1 AND name ILIKE \$2;`).
+		WithArgs(7, "%matrix%").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	m, total, err := processor.GetMovies(models.Sort{{Field: "release_date"}}, filter, page)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.MovieOut{{Id: 1, Name: "The Matrix", Description: "description", Rating: 9}}, m)
+	assert.Equal(t, 3, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMoviesByActor(t *testing.T) {
+	search := models.NameSearch{Name: "Khabenskiy", Similarity: 0.3}
+	args := []driver.Value{"%Khabenskiy%", "Khabenskiy", "Khabenskiy", "Хабенский"}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		page := models.Page{Limit: 10}
+		movies := []models.MovieOut{
+			{
+				Id:          2,
+				Name:        "name2",
+				Description: "description2",
+				ReleaseDate: time.Time{},
+				Rating:      4,
+				Actors:      []int{2},
+				Credits:     []models.Credit{{PersonId: 2, Role: models.CreditActor}},
+				Cast:        []models.CastMember{{ActorId: 2}},
+			},
+			{
+				Id:          1,
+				Name:        "name1",
+				Description: "description1",
+				ReleaseDate: time.Time{},
+				Rating:      5,
+				Actors:      []int{1},
+				Credits:     []models.Credit{{PersonId: 1, Role: models.CreditActor}},
+				Cast:        []models.CastMember{{ActorId: 1}},
+			},
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("set_config").WithArgs("0.3").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("FROM movies m JOIN").WithArgs(append(args, page.Limit, page.Offset)...).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(movies[0].Id, movies[0].Name, movies[0].Description, movies[0].ReleaseDate, movies[0].Rating).AddRow(movies[1].Id, movies[1].Name, movies[1].Description, movies[1].ReleaseDate, movies[1].Rating))
+		mock.ExpectCommit()
+		mock.ExpectQuery("SELECT").WithArgs("{2,1}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "actor_id", "credit"}).AddRow(1, 1, models.CreditActor).AddRow(2, 2, models.CreditActor))
+		mock.ExpectQuery("SELECT").WithArgs("{2,1}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "genre_id"}))
+
+		m, total, err := processor.GetMoviesByActor(search, page)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Equal(t, movies, m)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error while getting movies by actor", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
 		processor := dbProcessor{db: db}
-		actor := "name"
 		errTxt := "select error"
-		mock.ExpectQuery("SELECT").WithArgs(actor).WillReturnError(errors.New(errTxt))
+		mock.ExpectBegin()
+		mock.ExpectExec("set_config").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT").WillReturnError(errors.New(errTxt))
+		mock.ExpectRollback()
 
-		_, err := processor.GetMoviesByActor(actor)
+		_, _, err := processor.GetMoviesByActor(search, models.Page{Limit: 10})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), errTxt)
 		assert.Contains(t, err.Error(), "error while getting movies by actor")
 	})
 
+	t.Run("error while counting", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		errTxt := "count error"
+		mock.ExpectBegin()
+		mock.ExpectExec("set_config").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(1, "name", "description", time.Time{}, 5))
+		mock.ExpectQuery("SELECT COUNT").WithArgs(args...).WillReturnError(errors.New(errTxt))
+		mock.ExpectRollback()
+
+		_, _, err := processor.GetMoviesByActor(search, models.Page{Limit: 1})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), errTxt)
+		assert.Contains(t, err.Error(), "error while counting rows")
+	})
+
 	t.Run("error while getting actors", func(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
 		processor := dbProcessor{db: db}
-		actor := "name"
 		errTxt := "select error"
-		mock.ExpectQuery("SELECT").WithArgs(actor).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(1, "name", "description", time.Time{}, 5))
-		mock.ExpectQuery("SELECT").WithArgs(1).WillReturnError(errors.New(errTxt))
+		mock.ExpectBegin()
+		mock.ExpectExec("set_config").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(1, "name", "description", time.Time{}, 5))
+		mock.ExpectCommit()
+		mock.ExpectQuery("SELECT").WithArgs("{1}").WillReturnError(errors.New(errTxt))
 
-		_, err := processor.GetMoviesByActor(actor)
+		_, _, err := processor.GetMoviesByActor(search, models.Page{Limit: 10})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), errTxt)
 		assert.Contains(t, err.Error(), "error while getting movie's actors")
 		assert.Contains(t, err.Error(), "error while getting movies by actor")
 	})
 }
-
 func TestGetMoviesByName(t *testing.T) {
+	search := models.NameSearch{Name: "Брат", Similarity: 0.3}
+	args := []driver.Value{"%Брат%", "Брат", "Brat", "Брат"}
+
 	t.Run("success", func(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
 		processor := dbProcessor{db: db}
-		name := "name"
+		page := models.Page{Limit: 10}
 		movies := []models.MovieOut{
-			{
-				Id:          1,
-				Name:        "name1",
-				Description: "description1",
-				ReleaseDate: time.Time{},
-				Rating:      5,
-				Actors:      []int{1},
-			},
 			{
 				Id:          2,
 				Name:        "name2",
@@ -667,49 +1315,86 @@ func TestGetMoviesByName(t *testing.T) {
 				ReleaseDate: time.Time{},
 				Rating:      4,
 				Actors:      []int{2},
+				Credits:     []models.Credit{{PersonId: 2, Role: models.CreditActor}},
+				Cast:        []models.CastMember{{ActorId: 2}},
+			},
+			{
+				Id:          1,
+				Name:        "name1",
+				Description: "description1",
+				ReleaseDate: time.Time{},
+				Rating:      5,
+				Actors:      []int{1},
+				Credits:     []models.Credit{{PersonId: 1, Role: models.CreditActor}},
+				Cast:        []models.CastMember{{ActorId: 1}},
 			},
 		}
 
-		mock.ExpectQuery("SELECT").WithArgs(name).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(movies[0].Id, movies[0].Name, movies[0].Description, movies[0].ReleaseDate, movies[0].Rating).AddRow(movies[1].Id, movies[1].Name, movies[1].Description, movies[1].ReleaseDate, movies[1].Rating))
-		mock.ExpectQuery("SELECT").WithArgs(movies[0].Id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
-		mock.ExpectQuery("SELECT").WithArgs(movies[1].Id).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectBegin()
+		mock.ExpectExec("set_config").WithArgs("0.3").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("v ON true WHERE name ILIKE").WithArgs(append(args, page.Limit, page.Offset)...).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(movies[0].Id, movies[0].Name, movies[0].Description, movies[0].ReleaseDate, movies[0].Rating).AddRow(movies[1].Id, movies[1].Name, movies[1].Description, movies[1].ReleaseDate, movies[1].Rating))
+		mock.ExpectCommit()
+		mock.ExpectQuery("SELECT").WithArgs("{2,1}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "actor_id", "credit"}).AddRow(1, 1, models.CreditActor).AddRow(2, 2, models.CreditActor))
+		mock.ExpectQuery("SELECT").WithArgs("{2,1}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "genre_id"}))
 
-		m, err := processor.GetMoviesByName(name)
+		m, total, err := processor.GetMoviesByName(search, page)
 		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
 		assert.Equal(t, movies, m)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("error while getting movies by name", func(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
 		processor := dbProcessor{db: db}
-		name := "name"
 		errTxt := "select error"
-		mock.ExpectQuery("SELECT").WithArgs(name).WillReturnError(errors.New(errTxt))
+		mock.ExpectBegin()
+		mock.ExpectExec("set_config").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT").WillReturnError(errors.New(errTxt))
+		mock.ExpectRollback()
 
-		_, err := processor.GetMoviesByName(name)
+		_, _, err := processor.GetMoviesByName(search, models.Page{Limit: 10})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), errTxt)
 		assert.Contains(t, err.Error(), "error while getting movies by name")
 	})
 
+	t.Run("error while counting", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		errTxt := "count error"
+		mock.ExpectBegin()
+		mock.ExpectExec("set_config").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(1, "name", "description", time.Time{}, 5))
+		mock.ExpectQuery("SELECT COUNT").WithArgs(args...).WillReturnError(errors.New(errTxt))
+		mock.ExpectRollback()
+
+		_, _, err := processor.GetMoviesByName(search, models.Page{Limit: 1})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), errTxt)
+		assert.Contains(t, err.Error(), "error while counting rows")
+	})
+
 	t.Run("error while getting actors", func(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
 		processor := dbProcessor{db: db}
-		name := "name"
 		errTxt := "select error"
-		mock.ExpectQuery("SELECT").WithArgs(name).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(1, "name", "description", time.Time{}, 5))
-		mock.ExpectQuery("SELECT").WithArgs(1).WillReturnError(errors.New(errTxt))
+		mock.ExpectBegin()
+		mock.ExpectExec("set_config").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(1, "name", "description", time.Time{}, 5))
+		mock.ExpectCommit()
+		mock.ExpectQuery("SELECT").WithArgs("{1}").WillReturnError(errors.New(errTxt))
 
-		_, err := processor.GetMoviesByName(name)
+		_, _, err := processor.GetMoviesByName(search, models.Page{Limit: 10})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), errTxt)
 		assert.Contains(t, err.Error(), "error while getting movie's actors")
 		assert.Contains(t, err.Error(), "error while getting movies by name")
 	})
 }
-
 func TestUpdateActor(t *testing.T) {
 	t.Run("success full", func(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
@@ -724,9 +1409,9 @@ func TestUpdateActor(t *testing.T) {
 		id := 1
 
 		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.Name).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.Gender).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.DateOfBirth).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.Name).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.Gender).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.DateOfBirth).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit()
 
 		err := processor.UpdateActor(id, actor)
@@ -745,8 +1430,8 @@ func TestUpdateActor(t *testing.T) {
 		id := 1
 
 		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.Name).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.Gender).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.Name).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.Gender).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit()
 
 		err := processor.UpdateActor(id, actor)
@@ -765,8 +1450,8 @@ func TestUpdateActor(t *testing.T) {
 		id := 1
 
 		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.Name).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.DateOfBirth).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.Name).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.DateOfBirth).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit()
 
 		err := processor.UpdateActor(id, actor)
@@ -833,7 +1518,7 @@ func TestUpdateActor(t *testing.T) {
 
 		errTxt := "update error"
 		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.Name).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.Name).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.Gender).WillReturnError(errors.New(errTxt))
 		mock.ExpectRollback()
 
@@ -881,7 +1566,7 @@ func TestUpdateActor(t *testing.T) {
 
 		errTxt := "commit error"
 		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.Name).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE actors").WithArgs(id, actor.Name).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit().WillReturnError(errors.New(errTxt))
 		mock.ExpectRollback()
 
@@ -890,6 +1575,34 @@ func TestUpdateActor(t *testing.T) {
 		assert.Contains(t, err.Error(), errCommitTx.Error())
 		assert.Contains(t, err.Error(), errTxt)
 	})
+
+	t.Run("unknown actor", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE actors").WithArgs(1, "name").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := processor.UpdateActor(1, models.ActorIn{Name: "name"})
+		assert.ErrorIs(t, err, ErrUnknownActor)
+		assert.Contains(t, err.Error(), "error while updating actor 1")
+	})
+
+	t.Run("empty patch of unknown actor", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM actors").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		err := processor.UpdateActor(1, models.ActorIn{})
+		assert.ErrorIs(t, err, ErrUnknownActor)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestUpdateMovie(t *testing.T) {
@@ -909,13 +1622,13 @@ func TestUpdateMovie(t *testing.T) {
 		*movie.Rating = 5
 
 		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Name).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Description).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.ReleaseDate).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE movies").WithArgs(id, *movie.Rating).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Name).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Description).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.ReleaseDate).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, *movie.Rating).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectExec("DELETE FROM movie_actors").WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
-		for _, a := range movie.Actors {
-			mock.ExpectExec("INSERT INTO movie_actors").WithArgs(id, a).WillReturnResult(sqlmock.NewResult(0, 0))
+		for i, a := range movie.Actors {
+			mock.ExpectExec("INSERT INTO movie_actors").WithArgs(id, a, "", i+1, false, false).WillReturnResult(sqlmock.NewResult(0, 0))
 		}
 		mock.ExpectCommit()
 
@@ -938,12 +1651,12 @@ func TestUpdateMovie(t *testing.T) {
 		*movie.Rating = 5
 
 		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Description).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.ReleaseDate).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE movies").WithArgs(id, *movie.Rating).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Description).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.ReleaseDate).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, *movie.Rating).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectExec("DELETE FROM movie_actors").WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
-		for _, a := range movie.Actors {
-			mock.ExpectExec("INSERT INTO movie_actors").WithArgs(id, a).WillReturnResult(sqlmock.NewResult(0, 0))
+		for i, a := range movie.Actors {
+			mock.ExpectExec("INSERT INTO movie_actors").WithArgs(id, a, "", i+1, false, false).WillReturnResult(sqlmock.NewResult(0, 0))
 		}
 		mock.ExpectCommit()
 
@@ -964,8 +1677,8 @@ func TestUpdateMovie(t *testing.T) {
 		*movie.Rating = 5
 
 		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.ReleaseDate).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE movies").WithArgs(id, *movie.Rating).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.ReleaseDate).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, *movie.Rating).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit()
 
 		err := processor.UpdateMovie(id, movie)
@@ -1039,7 +1752,7 @@ func TestUpdateMovie(t *testing.T) {
 
 		errTxt := "update error"
 		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Name).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Name).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Description).WillReturnError(errors.New(errTxt))
 		mock.ExpectRollback()
 
@@ -1094,9 +1807,9 @@ func TestUpdateMovie(t *testing.T) {
 
 		errTxt := "update error"
 		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Name).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Description).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.ReleaseDate).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Name).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Description).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.ReleaseDate).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectExec("UPDATE movies").WithArgs(id, *movie.Rating).WillReturnError(errors.New(errTxt))
 		mock.ExpectRollback()
 
@@ -1123,10 +1836,10 @@ func TestUpdateMovie(t *testing.T) {
 
 		errTxt := "update error"
 		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Name).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Description).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.ReleaseDate).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE movies").WithArgs(id, *movie.Rating).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Name).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Description).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.ReleaseDate).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, *movie.Rating).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectExec("DELETE FROM movie_actors").WithArgs(id).WillReturnError(errors.New(errTxt))
 		mock.ExpectRollback()
 
@@ -1153,13 +1866,13 @@ func TestUpdateMovie(t *testing.T) {
 
 		errTxt := "update error"
 		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Name).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Description).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.ReleaseDate).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("UPDATE movies").WithArgs(id, *movie.Rating).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Name).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Description).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.ReleaseDate).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, *movie.Rating).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectExec("DELETE FROM movie_actors").WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("INSERT INTO movie_actors").WithArgs(id, movie.Actors[0]).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectExec("INSERT INTO movie_actors").WithArgs(id, movie.Actors[1]).WillReturnError(errors.New(errTxt))
+		mock.ExpectExec("INSERT INTO movie_actors").WithArgs(id, movie.Actors[0], "", 1, false, false).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO movie_actors").WithArgs(id, movie.Actors[1], "", 2, false, false).WillReturnError(errors.New(errTxt))
 		mock.ExpectRollback()
 
 		err := processor.UpdateMovie(id, movie)
@@ -1180,7 +1893,7 @@ func TestUpdateMovie(t *testing.T) {
 
 		errTxt := "commit error"
 		mock.ExpectBegin()
-		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Name).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("UPDATE movies").WithArgs(id, movie.Name).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit().WillReturnError(errors.New(errTxt))
 		mock.ExpectRollback()
 
@@ -1189,6 +1902,152 @@ func TestUpdateMovie(t *testing.T) {
 		assert.Contains(t, err.Error(), errCommitTx.Error())
 		assert.Contains(t, err.Error(), errTxt)
 	})
+
+	t.Run("unknown movie", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE movies").WithArgs(1, "name").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := processor.UpdateMovie(1, models.MovieIn{Name: "name"})
+		assert.ErrorIs(t, err, ErrUnknownMovie)
+	})
+
+	t.Run("unknown movie of actors", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM movies").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		err := processor.UpdateMovie(1, models.MovieIn{Actors: []int{2}})
+		assert.ErrorIs(t, err, ErrUnknownMovie)
+	})
+
+	t.Run("unknown actor", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM movies").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("DELETE FROM movie_actors").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO movie_actors").WithArgs(1, 2, "", 1, false, false).WillReturnError(&pq.Error{Code: "23503", Constraint: "movie_actors_actor_id_fkey"})
+		mock.ExpectRollback()
+
+		err := processor.UpdateMovie(1, models.MovieIn{Actors: []int{2}})
+		assert.ErrorIs(t, err, ErrForeignKey)
+		assert.NotErrorIs(t, err, ErrNotFound)
+		assert.Contains(t, err.Error(), "error while adding actor 2 to movie 1")
+		public, _ := PublicError(err)
+		assert.EqualError(t, public, "actor 2 does not exist")
+	})
+}
+
+func TestUpdateMovieCredits(t *testing.T) {
+	t.Run("replace all credits", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM movies").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(`DELETE FROM movie_actors WHERE movie_id = \$1;`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec("INSERT INTO movie_actors").WithArgs(1, 2, "", 1, false, false).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO movie_actors").WithArgs(1, 3, models.CreditDirector).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := processor.UpdateMovie(1, models.MovieIn{Actors: []int{2}, Credits: []models.Credit{{PersonId: 3, Role: models.CreditDirector}}})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown person", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM movies").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("DELETE FROM movie_actors").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO movie_actors").WithArgs(1, 3, models.CreditComposer).
+			WillReturnError(&pq.Error{Code: "23503", Constraint: "movie_actors_actor_id_fkey"})
+		mock.ExpectRollback()
+
+		err := processor.UpdateMovie(1, models.MovieIn{Credits: []models.Credit{{PersonId: 3, Role: models.CreditComposer}}})
+		assert.ErrorIs(t, err, ErrForeignKey)
+		assert.Contains(t, err.Error(), "composer 3 does not exist")
+	})
+}
+
+func TestGetFilmography(t *testing.T) {
+	t.Run("by role", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery(`SELECT (.+) FROM movies (.+) v ON true WHERE id IN \(SELECT movie_id FROM movie_actors WHERE actor_id = \$1 AND credit = \$2\) ORDER BY release_date, id LIMIT \$3 OFFSET \$4;`).
+			WithArgs(5, models.CreditDirector, 10, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(1, "name", "description", time.Time{}, 7))
+		mock.ExpectQuery("SELECT movie_id, actor_id, credit").WithArgs("{1}").
+			WillReturnRows(sqlmock.NewRows([]string{"movie_id", "actor_id", "credit"}).AddRow(1, 5, models.CreditDirector))
+		mock.ExpectQuery("SELECT movie_id, genre_id").WithArgs("{1}").WillReturnRows(sqlmock.NewRows([]string{"movie_id", "genre_id"}))
+
+		m, total, err := processor.GetFilmography(5, models.CreditDirector, models.DefaultFilmographySort, models.Page{Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, []models.MovieOut{{Id: 1, Name: "name", Description: "description", Rating: 7,
+			Credits: []models.Credit{{PersonId: 5, Role: models.CreditDirector}}}}, m)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown person", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery(`v ON true WHERE id IN \(SELECT movie_id FROM movie_actors WHERE actor_id = \$1\)`).WithArgs(5, 10, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}))
+		mock.ExpectQuery("SELECT EXISTS").WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		_, _, err := processor.GetFilmography(5, "", models.DefaultFilmographySort, models.Page{Limit: 10})
+		assert.ErrorIs(t, err, ErrUnknownActor)
+		assert.Contains(t, err.Error(), "error while getting filmography of person 5")
+	})
+}
+
+func TestUpdateMovieCast(t *testing.T) {
+	db, mock, _ := sqlmock.Newx()
+	defer db.Close()
+	processor := dbProcessor{db: db}
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM movies").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(`DELETE FROM movie_actors WHERE movie_id = \$1 AND credit = 'actor';`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO movie_actors").WithArgs(1, 7, "Тринити", 2, false, false).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO movie_actors").WithArgs(1, 8, "Оракул", 3, true, false).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := processor.UpdateMovie(1, models.MovieIn{Cast: []models.CastMember{
+		{ActorId: 7, Character: "Тринити", Billing: 2},
+		{ActorId: 8, Character: "Оракул", Cameo: true},
+	}})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateMovieGenres(t *testing.T) {
+	db, mock, _ := sqlmock.Newx()
+	defer db.Close()
+	processor := dbProcessor{db: db}
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id FROM movies").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec("DELETE FROM movie_genres").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO movie_genres").WithArgs(1, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, processor.UpdateMovie(1, models.MovieIn{Genres: []int{3}}))
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestDeleteSmth(t *testing.T) {
@@ -1201,10 +2060,10 @@ func TestDeleteSmth(t *testing.T) {
 		wrap := "error"
 
 		mock.ExpectBegin()
-		mock.ExpectExec(q).WithArgs(smth).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(q).WithArgs(smth).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit()
 
-		err := processor.deleteSmth(q, wrap, smth)
+		err := processor.deleteSmth(q, wrap, ErrNotFound, smth)
 		assert.NoError(t, err)
 	})
 
@@ -1219,7 +2078,7 @@ func TestDeleteSmth(t *testing.T) {
 		errTxt := "begin error"
 		mock.ExpectBegin().WillReturnError(errors.New(errTxt))
 
-		err := processor.deleteSmth(q, wrap, smth)
+		err := processor.deleteSmth(q, wrap, ErrNotFound, smth)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), errBeginTx.Error())
 		assert.Contains(t, err.Error(), errTxt)
@@ -1238,12 +2097,29 @@ func TestDeleteSmth(t *testing.T) {
 		mock.ExpectExec(q).WithArgs(smth).WillReturnError(errors.New(errTxt))
 		mock.ExpectRollback()
 
-		err := processor.deleteSmth(q, wrap, smth)
+		err := processor.deleteSmth(q, wrap, ErrNotFound, smth)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), errTxt)
 		assert.Contains(t, err.Error(), wrap)
 	})
 
+	t.Run("nothing deleted", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		smth := true
+		q := "smth"
+		wrap := "error"
+
+		mock.ExpectBegin()
+		mock.ExpectExec(q).WithArgs(smth).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := processor.deleteSmth(q, wrap, ErrNotFound, smth)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Contains(t, err.Error(), wrap)
+	})
+
 	t.Run("error while committing transaction", func(t *testing.T) {
 		db, mock, _ := sqlmock.Newx()
 		defer db.Close()
@@ -1254,11 +2130,11 @@ func TestDeleteSmth(t *testing.T) {
 
 		errTxt := "commit error"
 		mock.ExpectBegin()
-		mock.ExpectExec(q).WithArgs(smth).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(q).WithArgs(smth).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit().WillReturnError(errors.New(errTxt))
 		mock.ExpectRollback()
 
-		err := processor.deleteSmth(q, wrap, smth)
+		err := processor.deleteSmth(q, wrap, ErrNotFound, smth)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), errCommitTx.Error())
 		assert.Contains(t, err.Error(), errTxt)
@@ -1272,12 +2148,24 @@ func TestDeleteActor(t *testing.T) {
 		processor := dbProcessor{db: db}
 		id := 1
 
-		mock.ExpectBegin()
-		mock.ExpectExec("DELETE FROM actors").WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectCommit()
+		mock.ExpectQuery("DELETE FROM actors").WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"photo"}).AddRow("actors/1/photo.png"))
 
-		err := processor.DeleteActor(id)
+		key, err := processor.DeleteActor(id)
 		assert.NoError(t, err)
+		assert.Equal(t, "actors/1/photo.png", key)
+	})
+
+	t.Run("unknown actor", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		id := 1
+
+		mock.ExpectQuery("DELETE FROM actors").WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"photo"}))
+
+		_, err := processor.DeleteActor(id)
+		assert.ErrorIs(t, err, ErrUnknownActor)
+		assert.ErrorIs(t, err, ErrNotFound)
 	})
 }
 
@@ -1288,11 +2176,1268 @@ func TestDeleteMovie(t *testing.T) {
 		processor := dbProcessor{db: db}
 		id := 1
 
+		mock.ExpectQuery("DELETE FROM movies").WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"poster"}).AddRow("movies/1/poster.png"))
+
+		key, err := processor.DeleteMovie(id)
+		assert.NoError(t, err)
+		assert.Equal(t, "movies/1/poster.png", key)
+	})
+
+	t.Run("unknown movie", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		id := 1
+
+		mock.ExpectQuery("DELETE FROM movies").WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"poster"}))
+
+		_, err := processor.DeleteMovie(id)
+		assert.ErrorIs(t, err, ErrUnknownMovie)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestAddSession(t *testing.T) {
+	s := models.Session{Id: "sid", Nickname: "user", Superuser: true, ExpiresAt: time.Now()}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
 		mock.ExpectBegin()
-		mock.ExpectExec("DELETE FROM movies").WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM sessions").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO sessions").WithArgs(s.Id, s.Nickname, s.Superuser, s.ExpiresAt).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit()
 
-		err := processor.DeleteMovie(id)
+		err := processor.AddSession(s)
 		assert.NoError(t, err)
 	})
+
+	t.Run("error while inserting session", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		errTxt := "insert error"
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM sessions").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO sessions").WillReturnError(errors.New(errTxt))
+		mock.ExpectRollback()
+
+		err := processor.AddSession(s)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), errTxt)
+		assert.Contains(t, err.Error(), "error while inserting session")
+	})
+}
+
+func TestGetSession(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		s := models.Session{Id: "sid", Nickname: "user", Superuser: true, ExpiresAt: time.Now()}
+
+		mock.ExpectQuery("SELECT").WithArgs(s.Id).WillReturnRows(sqlmock.NewRows([]string{"id", "nickname", "superuser", "expires_at"}).AddRow(s.Id, s.Nickname, s.Superuser, s.ExpiresAt))
+
+		got, err := processor.GetSession(s.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, s, got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		errTxt := "select error"
+
+		mock.ExpectQuery("SELECT").WithArgs("sid").WillReturnError(errors.New(errTxt))
+
+		_, err := processor.GetSession("sid")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), errTxt)
+		assert.Contains(t, err.Error(), "error while getting session")
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectQuery("SELECT").WithArgs("sid").WillReturnRows(sqlmock.NewRows([]string{"id", "nickname", "superuser", "expires_at"}))
+
+		_, err := processor.GetSession("sid")
+		assert.ErrorIs(t, err, ErrUnknownSession)
+	})
+}
+
+func TestDeleteSession(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM sessions").WithArgs("sid").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := processor.DeleteSession("sid")
+		assert.NoError(t, err)
+	})
+}
+
+func TestGetUserByNickname(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		user := models.User{Id: 1, Nickname: "user", Password: "hash", IsAdmin: true}
+
+		mock.ExpectQuery("SELECT").WithArgs(user.Nickname).WillReturnRows(sqlmock.NewRows([]string{"id", "nickname", "password", "is_admin"}).AddRow(user.Id, user.Nickname, user.Password, user.IsAdmin))
+
+		u, err := processor.GetUserByNickname(user.Nickname)
+		assert.NoError(t, err)
+		assert.Equal(t, user, u)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectQuery("SELECT").WithArgs("user").WillReturnRows(sqlmock.NewRows([]string{"id", "nickname", "password", "is_admin"}))
+
+		_, err := processor.GetUserByNickname("user")
+		assert.ErrorIs(t, err, ErrUnknownUser)
+	})
+}
+
+func TestCheckUserPassword(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "nickname", "password", "is_admin"}).AddRow(1, "user", string(hash), false)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("SELECT").WithArgs("user").WillReturnRows(rows())
+
+		err := processor.CheckUserPassword("user", "password")
+		assert.NoError(t, err)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("SELECT").WithArgs("user").WillReturnRows(rows())
+
+		err := processor.CheckUserPassword("user", "wrong")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("SELECT").WithArgs("user").WillReturnRows(sqlmock.NewRows([]string{"id", "nickname", "password", "is_admin"}))
+
+		err := processor.CheckUserPassword("user", "password")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+		assert.NotErrorIs(t, err, ErrUnknownUser)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("SELECT").WithArgs("user").WillReturnError(errors.New("db is down"))
+
+		err := processor.CheckUserPassword("user", "password")
+		assert.NotErrorIs(t, err, ErrInvalidCredentials)
+	})
+}
+
+func TestAddUserNicknameTaken(t *testing.T) {
+	db, mock, _ := sqlmock.Newx()
+	defer db.Close()
+	processor := dbProcessor{db: db}
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO users").WillReturnError(&pq.Error{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err := processor.AddUser(models.User{Nickname: "user"})
+	assert.ErrorIs(t, err, ErrNicknameTaken)
+}
+
+func TestGetUser(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		user := models.UserOut{Id: 1, Nickname: "user", Roles: []string{models.RoleEditor, models.RoleViewer}}
+
+		mock.ExpectQuery("SELECT").WithArgs(user.Id).WillReturnRows(sqlmock.NewRows([]string{"id", "nickname", "roles"}).AddRow(user.Id, user.Nickname, "{editor,viewer}"))
+
+		u, err := processor.GetUser(user.Id)
+		assert.NoError(t, err)
+		assert.Equal(t, user, u)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectQuery("SELECT").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "nickname", "roles"}))
+
+		_, err := processor.GetUser(1)
+		assert.ErrorIs(t, err, ErrUnknownUser)
+		assert.Contains(t, err.Error(), "error while getting user 1")
+	})
+}
+
+func TestGetUsers(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		users := []models.UserOut{{Id: 1, Nickname: "admin", Roles: []string{models.RoleAdmin}}, {Id: 2, Nickname: "user", Roles: []string{}}}
+
+		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "nickname", "roles"}).AddRow(users[0].Id, users[0].Nickname, "{admin}").AddRow(users[1].Id, users[1].Nickname, "{}"))
+
+		u, err := processor.GetUsers()
+		assert.NoError(t, err)
+		assert.Equal(t, users, u)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		errTxt := "select error"
+		mock.ExpectQuery("SELECT").WillReturnError(errors.New(errTxt))
+
+		_, err := processor.GetUsers()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), errTxt)
+		assert.Contains(t, err.Error(), "error while getting users")
+	})
+}
+
+func TestUpdateUser(t *testing.T) {
+	isAdmin := true
+	patch := models.UserPatch{IsAdmin: &isAdmin}
+
+	t.Run("empty patch", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM users").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, processor.UpdateUser(1, models.UserPatch{}))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("empty patch of unknown user", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM users").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		err := processor.UpdateUser(1, models.UserPatch{})
+		assert.ErrorIs(t, err, ErrUnknownUser)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE users").WithArgs(1, isAdmin).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM user_roles").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO user_roles").WithArgs(1, models.RoleAdmin).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := processor.UpdateUser(1, patch)
+		assert.NoError(t, err)
+	})
+
+	t.Run("roles", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE users").WithArgs(1, false).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM user_roles").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO user_roles").WithArgs(1, models.RoleEditor).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO user_roles").WithArgs(1, models.RoleUserAdmin).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := processor.UpdateUser(1, models.UserPatch{Roles: []string{models.RoleEditor, models.RoleUserAdmin}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE users").WithArgs(1, isAdmin).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := processor.UpdateUser(1, patch)
+		assert.ErrorIs(t, err, ErrUnknownUser)
+		assert.Contains(t, err.Error(), "error while updating user 1")
+	})
+}
+
+func TestUpdateUserPassword(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE users").WithArgs("user", "hash").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM sessions").WithArgs("user").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := processor.UpdateUserPassword("user", "hash")
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE users").WithArgs("user", "hash").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := processor.UpdateUserPassword("user", "hash")
+		assert.ErrorIs(t, err, ErrUnknownUser)
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM sessions").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM users").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := processor.DeleteUser(1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM sessions").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM users").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := processor.DeleteUser(1)
+		assert.ErrorIs(t, err, ErrUnknownUser)
+		assert.Contains(t, err.Error(), "error while deleting user 1")
+	})
+}
+
+func TestGetRoles(t *testing.T) {
+	db, mock, _ := sqlmock.Newx()
+	defer db.Close()
+	processor := dbProcessor{db: db}
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"name", "permissions"}).
+		AddRow(models.RoleEditor, "{catalog:read,catalog:write}").
+		AddRow(models.RoleViewer, "{catalog:read}"))
+
+	roles, err := processor.GetRoles()
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Role{
+		{Name: models.RoleEditor, Permissions: []string{models.PermCatalogRead, models.PermCatalogWrite}},
+		{Name: models.RoleViewer, Permissions: []string{models.PermCatalogRead}},
+	}, roles)
+}
+
+func TestGetUserPermissions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("SELECT").WithArgs("user").WillReturnRows(sqlmock.NewRows([]string{"permission"}).AddRow(models.PermCatalogRead))
+
+		permissions, err := processor.GetUserPermissions("user")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{models.PermCatalogRead}, permissions)
+	})
+
+	t.Run("no roles", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("SELECT").WithArgs("user").WillReturnRows(sqlmock.NewRows([]string{"permission"}))
+
+		permissions, err := processor.GetUserPermissions("user")
+		assert.NoError(t, err)
+		assert.Empty(t, permissions)
+	})
+}
+
+func TestAddGenre(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO genres").WithArgs("Драма").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectCommit()
+
+		id, err := processor.AddGenre(models.GenreIn{Name: "Драма"})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, id)
+	})
+
+	t.Run("genre taken", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO genres").WithArgs("Драма").WillReturnError(&pq.Error{Code: "23505"})
+		mock.ExpectRollback()
+
+		_, err := processor.AddGenre(models.GenreIn{Name: "Драма"})
+		assert.ErrorIs(t, err, ErrGenreTaken)
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+}
+
+func TestUpdateGenre(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectExec("UPDATE genres").WithArgs(3, "Комедия").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, processor.UpdateGenre(3, models.GenreIn{Name: "Комедия"}))
+	})
+
+	t.Run("unknown genre", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectExec("UPDATE genres").WithArgs(3, "Комедия").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := processor.UpdateGenre(3, models.GenreIn{Name: "Комедия"})
+		assert.ErrorIs(t, err, ErrUnknownGenre)
+		assert.Contains(t, err.Error(), "error while updating genre 3")
+	})
+
+	t.Run("genre taken", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectExec("UPDATE genres").WithArgs(3, "Комедия").WillReturnError(&pq.Error{Code: "23505"})
+
+		err := processor.UpdateGenre(3, models.GenreIn{Name: "Комедия"})
+		assert.ErrorIs(t, err, ErrGenreTaken)
+	})
+}
+
+func TestDeleteGenre(t *testing.T) {
+	db, mock, _ := sqlmock.Newx()
+	defer db.Close()
+	processor := dbProcessor{db: db}
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM genres").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := processor.DeleteGenre(3)
+	assert.ErrorIs(t, err, ErrUnknownGenre)
+	assert.Contains(t, err.Error(), "error while deleting genre 3")
+}
+
+func TestGetGenre(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("SELECT id, name FROM genres").WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "Драма"))
+
+		g, err := processor.GetGenre(3)
+		assert.NoError(t, err)
+		assert.Equal(t, models.GenreOut{Id: 3, Name: "Драма"}, g)
+	})
+
+	t.Run("unknown genre", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("SELECT id, name FROM genres").WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+		_, err := processor.GetGenre(3)
+		assert.ErrorIs(t, err, ErrUnknownGenre)
+	})
+}
+
+func TestGetGenres(t *testing.T) {
+	db, mock, _ := sqlmock.Newx()
+	defer db.Close()
+	processor := dbProcessor{db: db}
+	mock.ExpectQuery("SELECT id, name FROM genres ORDER BY name").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "Драма").AddRow(1, "Комедия"))
+
+	g, err := processor.GetGenres()
+	assert.NoError(t, err)
+	assert.Equal(t, []models.GenreOut{{Id: 3, Name: "Драма"}, {Id: 1, Name: "Комедия"}}, g)
+}
+
+func TestClassify(t *testing.T) {
+	assert.ErrorIs(t, classify(&pq.Error{Code: "23505"}), ErrConflict)
+	assert.ErrorIs(t, classify(&pq.Error{Code: "23503"}), ErrForeignKey)
+	err := errors.New("other")
+	assert.Equal(t, err, classify(err))
+}
+
+func TestPublicError(t *testing.T) {
+	err := errors.Join(errors.New("error while getting movie 1"), errors.New("sql: no rows in result set"), ErrUnknownMovie)
+	public, ok := PublicError(err)
+	assert.True(t, ok)
+	assert.Equal(t, ErrUnknownMovie, public)
+
+	public, ok = PublicError(errors.Join(errors.New("wrap"), &pq.Error{Code: "23505"}, ErrConflict))
+	assert.True(t, ok)
+	assert.Equal(t, ErrConflict, public)
+
+	_, ok = PublicError(errors.New("connection refused"))
+	assert.False(t, ok)
+}
+
+func TestKindError(t *testing.T) {
+	assert.ErrorIs(t, ErrUnknownUser, ErrNotFound)
+	assert.ErrorIs(t, ErrNicknameTaken, ErrConflict)
+	assert.ErrorIs(t, ErrUnknownRole, ErrForeignKey)
+	assert.Equal(t, "unknown actor", ErrUnknownActor.Error())
+	assert.NotErrorIs(t, ErrUnknownActor, ErrUnknownMovie)
+}
+
+// countingMock - создание мока БД, считающего выполненные запросы.
+func countingMock(b *testing.B, queries *int) (dbProcessor, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.Newx(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherFunc(func(expectedSQL, actualSQL string) error {
+		*queries++
+		return sqlmock.QueryMatcherRegexp.Match(expectedSQL, actualSQL)
+	})))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return dbProcessor{db: db}, mock
+}
+
+func BenchmarkGetMovies(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("movies=%d", n), func(b *testing.B) {
+			page := models.Page{Limit: n}
+			queries := 0
+			for range b.N {
+				b.StopTimer()
+				processor, mock := countingMock(b, &queries)
+				movies := sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"})
+				links := sqlmock.NewRows([]string{"movie_id", "actor_id", "credit"})
+				genres := sqlmock.NewRows([]string{"movie_id", "genre_id"})
+				for id := 1; id <= n; id++ {
+					movies.AddRow(id, "name", "description", time.Time{}, 5)
+					links.AddRow(id, id, models.CreditActor)
+					genres.AddRow(id, id%5+1)
+				}
+				mock.ExpectQuery("SELECT").WillReturnRows(movies)
+				mock.ExpectQuery("SELECT").WillReturnRows(links)
+				mock.ExpectQuery("SELECT").WillReturnRows(genres)
+				mock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(n))
+				b.StartTimer()
+
+				if _, _, err := processor.GetMovies(models.DefaultMovieSort, models.MovieFilter{}, page); err != nil {
+					b.Fatal(err)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					b.Fatal(err)
+				}
+			}
+			if queries != 4*b.N {
+				b.Fatalf("expected 4 queries per op, got %d", queries/b.N)
+			}
+			b.ReportMetric(float64(queries)/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func BenchmarkGetActors(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("actors=%d", n), func(b *testing.B) {
+			page := models.Page{Limit: n}
+			queries := 0
+			for range b.N {
+				b.StopTimer()
+				processor, mock := countingMock(b, &queries)
+				actors := sqlmock.NewRows([]string{"id", "name", "gender", "date_of_birth"})
+				links := sqlmock.NewRows([]string{"actor_id", "movie_id", "character_name", "billing", "cameo", "voice"})
+				for id := 1; id <= n; id++ {
+					actors.AddRow(id, "name", "male", time.Time{})
+					links.AddRow(id, id, "", 1, false, false)
+				}
+				mock.ExpectQuery("SELECT").WillReturnRows(actors)
+				mock.ExpectQuery("SELECT").WillReturnRows(links)
+				mock.ExpectQuery("SELECT COUNT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(n))
+				b.StartTimer()
+
+				if _, _, err := processor.GetActors(models.DefaultActorSort, page); err != nil {
+					b.Fatal(err)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					b.Fatal(err)
+				}
+			}
+			if queries != 3*b.N {
+				b.Fatalf("expected 3 queries per op, got %d", queries/b.N)
+			}
+			b.ReportMetric(float64(queries)/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func TestAddReview(t *testing.T) {
+	rating := 8
+	review := models.ReviewIn{Rating: &rating, Text: "Отличный фильм"}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO reviews").WithArgs("user", 15, rating, review.Text).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectCommit()
+
+		id, err := processor.AddReview("user", 15, review)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, id)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO reviews").WithArgs("admin", 15, rating, review.Text).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := processor.AddReview("admin", 15, review)
+		assert.ErrorIs(t, err, ErrUnknownUser)
+	})
+
+	t.Run("unknown movie", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO reviews").WithArgs("user", 15, rating, review.Text).WillReturnError(&pq.Error{Code: "23503"})
+		mock.ExpectRollback()
+
+		_, err := processor.AddReview("user", 15, review)
+		assert.ErrorIs(t, err, ErrUnknownMovie)
+		assert.Contains(t, err.Error(), "error while inserting review of movie 15")
+	})
+
+	t.Run("already reviewed", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO reviews").WithArgs("user", 15, rating, review.Text).WillReturnError(&pq.Error{Code: "23505"})
+		mock.ExpectRollback()
+
+		_, err := processor.AddReview("user", 15, review)
+		assert.ErrorIs(t, err, ErrReviewExists)
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+}
+
+func TestUpdateReview(t *testing.T) {
+	rating := 6
+	review := models.ReviewIn{Rating: &rating}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectExec("UPDATE reviews").WithArgs(4, "user", rating, "").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, processor.UpdateReview("user", 4, review))
+	})
+
+	t.Run("review of another user", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectExec("UPDATE reviews").WithArgs(4, "other", rating, "").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := processor.UpdateReview("other", 4, review)
+		assert.ErrorIs(t, err, ErrUnknownReview)
+		assert.Contains(t, err.Error(), "error while updating review 4")
+	})
+}
+
+func TestDeleteReview(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM reviews").WithArgs(4, "user").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, processor.DeleteReview("user", 4))
+	})
+
+	t.Run("unknown review", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM reviews").WithArgs(4, "user").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := processor.DeleteReview("user", 4)
+		assert.ErrorIs(t, err, ErrUnknownReview)
+		assert.Contains(t, err.Error(), "error while deleting review 4")
+	})
+}
+
+func TestGetMovieReviews(t *testing.T) {
+	columns := []string{"id", "movie_id", "nickname", "rating", "text", "created_at", "updated_at"}
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("FROM reviews r JOIN users u").WithArgs(15, 10, 0).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(4, 15, "user", 8, "Отличный фильм", created, created))
+
+		reviews, total, err := processor.GetMovieReviews(15, models.Page{Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, []models.ReviewOut{{Id: 4, MovieId: 15, Nickname: "user", Rating: 8, Text: "Отличный фильм", CreatedAt: created, UpdatedAt: created}}, reviews)
+	})
+
+	t.Run("no reviews", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("FROM reviews r JOIN users u").WithArgs(15, 10, 0).WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectQuery("SELECT EXISTS").WithArgs(15).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		reviews, total, err := processor.GetMovieReviews(15, models.Page{Limit: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, total)
+		assert.Empty(t, reviews)
+	})
+
+	t.Run("unknown movie", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("FROM reviews r JOIN users u").WithArgs(15, 10, 0).WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectQuery("SELECT EXISTS").WithArgs(15).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		_, _, err := processor.GetMovieReviews(15, models.Page{Limit: 10})
+		assert.ErrorIs(t, err, ErrUnknownMovie)
+		assert.Contains(t, err.Error(), "error while getting reviews of movie 15")
+	})
+}
+
+func TestGetUserReviews(t *testing.T) {
+	columns := []string{"id", "movie_id", "nickname", "rating", "text", "created_at", "updated_at"}
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	db, mock, _ := sqlmock.Newx()
+	defer db.Close()
+	processor := dbProcessor{db: db}
+	mock.ExpectQuery("WHERE u.name = \\$1").WithArgs("user", 1, 2).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(4, 15, "user", 8, "", created, created))
+	mock.ExpectQuery("SELECT COUNT").WithArgs("user").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	reviews, total, err := processor.GetUserReviews("user", models.Page{Limit: 1, Offset: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []models.ReviewOut{{Id: 4, MovieId: 15, Nickname: "user", Rating: 8, CreatedAt: created, UpdatedAt: created}}, reviews)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddToList(t *testing.T) {
+	watchedAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectExec("INSERT INTO user_movies").WithArgs("user", 15, models.ListWatched, watchedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, processor.AddToList("user", models.ListWatched, 15, models.ListEntryIn{WatchedAt: &watchedAt}))
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectExec("INSERT INTO user_movies").WithArgs("admin", 15, models.ListWatchlist, nil).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := processor.AddToList("admin", models.ListWatchlist, 15, models.ListEntryIn{})
+		assert.ErrorIs(t, err, ErrUnknownUser)
+		assert.Contains(t, err.Error(), "error while adding movie 15 to watchlist")
+	})
+
+	t.Run("unknown movie", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectExec("INSERT INTO user_movies").WithArgs("user", 15, models.ListFavorites, nil).WillReturnError(&pq.Error{Code: "23503"})
+
+		err := processor.AddToList("user", models.ListFavorites, 15, models.ListEntryIn{})
+		assert.ErrorIs(t, err, ErrUnknownMovie)
+	})
+}
+
+func TestRemoveFromList(t *testing.T) {
+	db, mock, _ := sqlmock.Newx()
+	defer db.Close()
+	processor := dbProcessor{db: db}
+	mock.ExpectExec("DELETE FROM user_movies").WithArgs("user", models.ListFavorites, 15).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := processor.RemoveFromList("user", models.ListFavorites, 15)
+	assert.ErrorIs(t, err, ErrNotInList)
+	assert.Contains(t, err.Error(), "error while removing movie 15 from favorites")
+}
+
+func TestGetList(t *testing.T) {
+	db, mock, _ := sqlmock.Newx()
+	defer db.Close()
+	processor := dbProcessor{db: db}
+	watchedAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("FROM user_movies um").WithArgs("user", models.ListWatched, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(15, "name", "description", time.Time{}, 7))
+	mock.ExpectQuery("SELECT (.+) FROM movie_actors").WithArgs(pq.Array([]int{15})).WillReturnRows(sqlmock.NewRows([]string{"movie_id", "actor_id", "credit"}))
+	mock.ExpectQuery("SELECT (.+) FROM movie_genres").WithArgs(pq.Array([]int{15})).WillReturnRows(sqlmock.NewRows([]string{"movie_id", "genre_id"}))
+	mock.ExpectQuery("SELECT movie_id, list, watched_at FROM user_movies").WithArgs("user", pq.Array([]int{15})).
+		WillReturnRows(sqlmock.NewRows([]string{"movie_id", "list", "watched_at"}).
+			AddRow(15, models.ListWatched, watchedAt).AddRow(15, models.ListFavorites, nil))
+
+	movies, total, err := processor.GetList("user", models.ListWatched, models.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []models.MovieOut{{Id: 15, Name: "name", Description: "description", Rating: 7,
+		Status: &models.MovieStatus{Favorite: true, Watched: true, WatchedAt: &watchedAt}}}, movies)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFillMovieStatuses(t *testing.T) {
+	t.Run("no movies", func(t *testing.T) {
+		db, _, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+
+		assert.NoError(t, processor.FillMovieStatuses("user", nil))
+	})
+
+	t.Run("movies out of lists", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("FROM user_movies").WithArgs("user", pq.Array([]int{1, 2})).
+			WillReturnRows(sqlmock.NewRows([]string{"movie_id", "list", "watched_at"}).AddRow(2, models.ListWatchlist, nil))
+
+		movies := []models.MovieOut{{Id: 1}, {Id: 2}}
+		assert.NoError(t, processor.FillMovieStatuses("user", movies))
+		assert.Equal(t, &models.MovieStatus{}, movies[0].Status)
+		assert.Equal(t, &models.MovieStatus{Watchlist: true}, movies[1].Status)
+	})
+}
+
+func TestAddCollection(t *testing.T) {
+	public := true
+	collection := models.CollectionIn{Name: "Marvel", Kind: models.CollectionKindFranchise, Public: &public, Movies: []int{7, 3}}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO collections").WithArgs("Marvel", "", models.CollectionKindFranchise, true, "user").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectExec("INSERT INTO collection_movies").WithArgs(2, 7, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO collection_movies").WithArgs(2, 3, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		id, err := processor.AddCollection("user", collection)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, id)
+	})
+
+	t.Run("default kind and unknown user", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO collections").WithArgs("Классика", "", models.CollectionKindCollection, false, "admin").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		_, err := processor.AddCollection("admin", models.CollectionIn{Name: "Классика"})
+		assert.ErrorIs(t, err, ErrUnknownUser)
+	})
+
+	t.Run("unknown movie", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO collections").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectExec("INSERT INTO collection_movies").WithArgs(2, 7, 1).
+			WillReturnError(&pq.Error{Code: "23503", Constraint: "collection_movies_movie_id_fkey"})
+		mock.ExpectRollback()
+
+		_, err := processor.AddCollection("user", collection)
+		assert.ErrorIs(t, err, ErrForeignKey)
+		assert.Contains(t, err.Error(), "movie 7 does not exist")
+	})
+}
+
+func TestUpdateCollection(t *testing.T) {
+	owner := models.CollectionAccess{Nickname: "user"}
+
+	t.Run("success with movies", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE collections").WithArgs(2, "user", false, "Marvel", "", "", nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM collection_movies").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO collection_movies").WithArgs(2, 3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, processor.UpdateCollection(owner, 2, models.CollectionIn{Name: "Marvel", Movies: []int{3}}))
+	})
+
+	t.Run("collection of another user", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE collections").WithArgs(2, "user", false, "Marvel", "", "", nil).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := processor.UpdateCollection(owner, 2, models.CollectionIn{Name: "Marvel"})
+		assert.ErrorIs(t, err, ErrUnknownCollection)
+		assert.Contains(t, err.Error(), "error while updating collection 2")
+	})
+
+	t.Run("only visibility", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		public := false
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("public = COALESCE($7::boolean, public)")).WithArgs(2, "user", false, "", "", "", false).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, processor.UpdateCollection(owner, 2, models.CollectionIn{Public: &public}))
+	})
+}
+
+func TestSetCollectionMovies(t *testing.T) {
+	moderator := models.CollectionAccess{Nickname: "editor", Moderator: true}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE collections SET updated_at").WithArgs(2, "editor", true).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM collection_movies").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO collection_movies").WithArgs(2, 3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO collection_movies").WithArgs(2, 7, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, processor.SetCollectionMovies(moderator, 2, []int{3, 7}))
+	})
+
+	t.Run("unknown collection", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE collections SET updated_at").WithArgs(2, "editor", true).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := processor.SetCollectionMovies(moderator, 2, []int{3, 7})
+		assert.ErrorIs(t, err, ErrUnknownCollection)
+		assert.Contains(t, err.Error(), "error while setting movies of collection 2")
+	})
+}
+
+func TestDeleteCollection(t *testing.T) {
+	db, mock, _ := sqlmock.Newx()
+	defer db.Close()
+	processor := dbProcessor{db: db}
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM collections").WithArgs(2, "user", false).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := processor.DeleteCollection(models.CollectionAccess{Nickname: "user"}, 2)
+	assert.ErrorIs(t, err, ErrUnknownCollection)
+	assert.Contains(t, err.Error(), "error while deleting collection 2")
+}
+
+func TestGetCollection(t *testing.T) {
+	columns := []string{"id", "name", "description", "kind", "public", "owner"}
+	access := models.CollectionAccess{Nickname: "user"}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("FROM collections c").WithArgs(2, "user", false).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "Marvel", "", models.CollectionKindFranchise, true, "editor"))
+		mock.ExpectQuery("SELECT movie_id FROM collection_movies").WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"movie_id"}).AddRow(7).AddRow(3))
+
+		collection, err := processor.GetCollection(access, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, models.CollectionOut{Id: 2, Name: "Marvel", Kind: models.CollectionKindFranchise, Public: true, Owner: "editor", Movies: []int{7, 3}}, collection)
+	})
+
+	t.Run("private collection of another user", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("FROM collections c").WithArgs(2, "user", false).WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err := processor.GetCollection(access, 2)
+		assert.ErrorIs(t, err, ErrUnknownCollection)
+	})
+}
+
+func TestGetCollections(t *testing.T) {
+	db, mock, _ := sqlmock.Newx()
+	defer db.Close()
+	processor := dbProcessor{db: db}
+	mock.ExpectQuery("FROM collections c").WithArgs("user", false, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "kind", "public", "owner"}).
+			AddRow(2, "Marvel", "", models.CollectionKindFranchise, true, "editor").
+			AddRow(5, "Моё", "", models.CollectionKindCollection, false, "user"))
+	mock.ExpectQuery("FROM collection_movies").WithArgs(pq.Array([]int{2, 5})).
+		WillReturnRows(sqlmock.NewRows([]string{"collection_id", "movie_id"}).AddRow(2, 7).AddRow(2, 3))
+
+	collections, total, err := processor.GetCollections(models.CollectionAccess{Nickname: "user"}, models.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []models.CollectionOut{
+		{Id: 2, Name: "Marvel", Kind: models.CollectionKindFranchise, Public: true, Owner: "editor", Movies: []int{7, 3}},
+		{Id: 5, Name: "Моё", Kind: models.CollectionKindCollection, Owner: "user", Movies: []int{}},
+	}, collections)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddEpisode(t *testing.T) {
+	date := time.Date(2017, 12, 1, 0, 0, 0, 0, time.UTC)
+	rating := 8
+	episode := models.EpisodeIn{Number: 1, Name: "Secrets", ReleaseDate: date, Rating: &rating,
+		Cast: []models.CastMember{{ActorId: 4, Character: "Jonas"}, {ActorId: 6, Billing: 5, Cameo: true}}}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO episodes").WithArgs(2, 1, "Secrets", "", date, 8).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectExec("INSERT INTO episode_actors").WithArgs(3, 4, "Jonas", 6, false, false).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO episode_actors").WithArgs(3, 6, "", 5, true, false).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		id, err := processor.AddEpisode(2, episode)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("taken number", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO episodes").WithArgs(2, 1, "Secrets", "", date, 8).
+			WillReturnError(&pq.Error{Code: "23505", Constraint: "episodes_season_id_number_key"})
+		mock.ExpectRollback()
+
+		_, err := processor.AddEpisode(2, episode)
+		assert.ErrorIs(t, err, ErrEpisodeExists)
+		assert.ErrorIs(t, err, ErrConflict)
+	})
+}
+
+func TestUpdateSeason(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectExec("UPDATE seasons").WithArgs(2, 0, "Finale").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, processor.UpdateSeason(2, models.SeasonIn{Name: "Finale"}))
+	})
+
+	t.Run("unknown season", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectExec("UPDATE seasons").WithArgs(2, 3, "").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := processor.UpdateSeason(2, models.SeasonIn{Number: 3})
+		assert.ErrorIs(t, err, ErrUnknownSeason)
+	})
+}
+
+func TestGetSeries(t *testing.T) {
+	date := time.Date(2017, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("FROM series WHERE id").WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(1, "Dark", "Time travel", date, 9))
+		mock.ExpectQuery("FROM seasons WHERE series_id").WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "series_id", "number", "name"}).AddRow(2, 1, 1, "").AddRow(5, 1, 2, ""))
+		mock.ExpectQuery("FROM episodes WHERE season_id").WithArgs(pq.Array([]int{2, 5})).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "season_id", "number", "name", "description", "release_date", "rating"}).
+				AddRow(3, 2, 1, "Secrets", "", date, 8))
+		mock.ExpectQuery("FROM episode_actors").WithArgs(pq.Array([]int{3})).
+			WillReturnRows(sqlmock.NewRows([]string{"episode_id", "actor_id", "character_name", "billing", "cameo", "voice"}))
+
+		series, err := processor.GetSeries(1)
+		assert.NoError(t, err)
+		assert.Equal(t, models.SeriesOut{Id: 1, Name: "Dark", Description: "Time travel", ReleaseDate: date, Rating: 9,
+			Seasons: []models.SeasonOut{
+				{Id: 2, SeriesId: 1, Number: 1, Episodes: []models.EpisodeOut{
+					{Id: 3, SeasonId: 2, Number: 1, Name: "Secrets", ReleaseDate: date, Rating: 8, Cast: []models.CastMember{}},
+				}},
+				{Id: 5, SeriesId: 1, Number: 2, Episodes: []models.EpisodeOut{}},
+			}}, series)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown series", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("FROM series WHERE id").WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}))
+
+		_, err := processor.GetSeries(1)
+		assert.ErrorIs(t, err, ErrUnknownSeries)
+	})
+}
+
+func TestGetSeriesList(t *testing.T) {
+	db, mock, _ := sqlmock.Newx()
+	defer db.Close()
+	processor := dbProcessor{db: db}
+	mock.ExpectQuery("FROM series ORDER BY rating DESC, id LIMIT").WithArgs(10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "release_date", "rating"}).AddRow(1, "Dark", "", time.Time{}, 9))
+
+	series, total, err := processor.GetSeriesList(models.DefaultSeriesSort, models.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []models.SeriesOut{{Id: 1, Name: "Dark", Rating: 9}}, series)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddMovieMeta(t *testing.T) {
+	runtime := 108
+	m := models.MovieIn{
+		Name:      "title",
+		Rating:    new(int),
+		MovieMeta: models.MovieMeta{Runtime: &runtime, Countries: pq.StringArray{"SU"}, OriginalLanguage: "ru", AgeRating: "12+"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO movies").WithArgs("title", "", time.Time{}, 0).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec("UPDATE movies SET runtime").WithArgs(1, 108, pq.StringArray{"SU"}, "ru", nil, "12+", nil, nil, "").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		id, err := processor.AddMovie(m)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown movie on update", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE movies SET runtime").WithArgs(7, 108, nil, "", nil, "", nil, nil, "").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := processor.UpdateMovie(7, models.MovieIn{MovieMeta: models.MovieMeta{Runtime: &runtime}})
+		assert.ErrorIs(t, err, ErrUnknownMovie)
+	})
+}
+
+func TestSetMoviePoster(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("UPDATE movies m SET poster").WithArgs(1, "movies/1/poster-b.png").
+			WillReturnRows(sqlmock.NewRows([]string{"poster"}).AddRow("movies/1/poster-a.png"))
+
+		old, err := processor.SetMoviePoster(1, "movies/1/poster-b.png")
+		assert.NoError(t, err)
+		assert.Equal(t, "movies/1/poster-a.png", old)
+	})
+
+	t.Run("unknown movie", func(t *testing.T) {
+		db, mock, _ := sqlmock.Newx()
+		defer db.Close()
+		processor := dbProcessor{db: db}
+		mock.ExpectQuery("UPDATE movies m SET poster").WithArgs(1, "").WillReturnRows(sqlmock.NewRows([]string{"poster"}))
+
+		_, err := processor.SetMoviePoster(1, "")
+		assert.ErrorIs(t, err, ErrUnknownMovie)
+	})
 }
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// Общие категории ошибок БД.
+//
+// Конкретные ошибки пакета относятся к одной из категорий, поэтому их можно проверять как errors.Is(err, ErrUnknownUser),
+// так и errors.Is(err, ErrNotFound).
+var (
+	// ErrNotFound - ошибка отсутствия записи.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict - ошибка нарушения уникальности записи.
+	ErrConflict = errors.New("conflict")
+	// ErrForeignKey - ошибка ссылки на несуществующую запись.
+	ErrForeignKey = errors.New("referenced record does not exist")
+)
+
+// kindError - ошибка, относящаяся к одной из общих категорий.
+type kindError struct {
+	msg  string
+	kind error
+}
+
+// newKindError - создание ошибки, относящейся к категории kind.
+func newKindError(kind error, msg string) error {
+	return &kindError{msg: msg, kind: kind}
+}
+
+// Error - текст ошибки.
+func (e *kindError) Error() string {
+	return e.msg
+}
+
+// Unwrap - категория ошибки.
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+// PublicError - получение ошибки пакета, которую можно показать клиенту вместо всей цепочки ошибок.
+//
+// Принимает: ошибку обработчика БД.
+//
+// Возвращает: ошибку пакета и true или nil и false, если в цепочке нет ошибок пакета.
+func PublicError(err error) (error, bool) {
+	var ke *kindError
+	if errors.As(err, &ke) {
+		return ke, true
+	}
+	for _, e := range []error{ErrInvalidCredentials, ErrUnknownSession, ErrNotFound, ErrConflict, ErrForeignKey} {
+		if errors.Is(err, e) {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// classify - добавление к ошибке postgres общей категории по её коду.
+//
+// Возвращает: ошибку, дополненную ErrConflict или ErrForeignKey, или исходную ошибку.
+func classify(err error) error {
+	switch {
+	case isUniqueViolation(err):
+		return errors.Join(err, ErrConflict)
+	case isForeignKeyViolation(err):
+		return errors.Join(err, ErrForeignKey)
+	default:
+		return err
+	}
+}
+
+// isUniqueViolation - проверка того, что ошибка вызвана нарушением уникальности.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// isForeignKeyViolation - проверка того, что ошибка вызвана нарушением внешнего ключа.
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+// violatedConstraint - название нарушенного ограничения.
+func violatedConstraint(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Constraint
+	}
+	return ""
+}
@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// queryBuilder - построитель SQL запроса с условиями WHERE.
+//
+// Условия добавляются с плейсхолдерами ?, значения передаются только как аргументы запроса,
+// а перед выполнением плейсхолдеры заменяются на $1, $2, ...
+type queryBuilder struct {
+	conds []string // conds - условия, объединяемые через AND.
+	args  []any    // args - аргументы условий в порядке плейсхолдеров.
+}
+
+// where - добавление условия.
+//
+// Принимает: условие с плейсхолдерами ? и значения для них.
+func (b *queryBuilder) where(cond string, args ...any) {
+	b.conds = append(b.conds, cond)
+	b.args = append(b.args, args...)
+}
+
+// build - получение запроса.
+//
+// Принимает: начало запроса (SELECT ... FROM ...), окончание запроса (ORDER BY, LIMIT) и аргументы окончания.
+//
+// Возвращает: запрос с плейсхолдерами PostgreSQL и его аргументы.
+func (b *queryBuilder) build(head, tail string, tailArgs ...any) (string, []any) {
+	var q strings.Builder
+	q.WriteString(head)
+	if len(b.conds) != 0 {
+		q.WriteString(" WHERE ")
+		q.WriteString(strings.Join(b.conds, " AND "))
+	}
+	if tail != "" {
+		q.WriteString(" ")
+		q.WriteString(tail)
+	}
+	q.WriteString(";")
+
+	args := append(slices.Clip(b.args), tailArgs...)
+	return sqlx.Rebind(sqlx.DOLLAR, q.String()), args
+}
+
+// movieFilterQuery - получение построителя запроса с условиями фильтра фильмов.
+//
+// Принимает: фильтр.
+//
+// Возвращает: построитель запроса.
+func movieFilterQuery(f models.MovieFilter) *queryBuilder {
+	b := &queryBuilder{}
+	if f.MinRating != nil {
+		b.where("rating >= ?", *f.MinRating)
+	}
+	if f.MaxRating != nil {
+		b.where("rating <= ?", *f.MaxRating)
+	}
+	if f.ReleasedAfter != nil {
+		b.where("release_date >= ?", *f.ReleasedAfter)
+	}
+	if f.ReleasedBefore != nil {
+		b.where("release_date <= ?", *f.ReleasedBefore)
+	}
+	if len(f.Actors) != 0 {
+		actors := slices.Clone(f.Actors)
+		slices.Sort(actors)
+		actors = slices.Compact(actors)
+		if f.ActorsMatch == models.ActorsMatchAll {
+			b.where("id IN (SELECT movie_id FROM movie_actors WHERE credit = 'actor' AND actor_id = ANY(?) GROUP BY movie_id HAVING COUNT(*) = ?)",
+				pq.Array(actors), len(actors))
+		} else {
+			b.where("id IN (SELECT movie_id FROM movie_actors WHERE credit = 'actor' AND actor_id = ANY(?))", pq.Array(actors))
+		}
+	}
+	if len(f.Genres) != 0 {
+		b.where("id IN (SELECT movie_id FROM movie_genres WHERE genre_id = ANY(?))", pq.Array(f.Genres))
+	}
+	if f.Name != "" {
+		b.where("name ILIKE ?", containsPattern(f.Name))
+	}
+	if f.MinRuntime != nil {
+		b.where("runtime >= ?", *f.MinRuntime)
+	}
+	if f.MaxRuntime != nil {
+		b.where("runtime <= ?", *f.MaxRuntime)
+	}
+	if len(f.Countries) != 0 {
+		b.where("countries && ?::text[]", pq.Array(f.Countries))
+	}
+	if len(f.Languages) != 0 {
+		b.where("(original_language = ANY(?) OR languages && ?::text[])", pq.Array(f.Languages), pq.Array(f.Languages))
+	}
+	if len(f.AgeRatings) != 0 {
+		b.where("age_rating = ANY(?)", pq.Array(f.AgeRatings))
+	}
+	if f.Currency != "" {
+		b.where("currency = ?", f.Currency)
+	}
+	if f.MinBudget != nil {
+		b.where("budget >= ?", *f.MinBudget)
+	}
+	if f.MaxBudget != nil {
+		b.where("budget <= ?", *f.MaxBudget)
+	}
+	if f.MinBoxOffice != nil {
+		b.where("box_office >= ?", *f.MinBoxOffice)
+	}
+	if f.MaxBoxOffice != nil {
+		b.where("box_office <= ?", *f.MaxBoxOffice)
+	}
+	return b
+}
+
+// Столбцы сортировки списков по полям сортировки.
+var (
+	// movieSortColumns - столбцы сортировки фильмов.
+	movieSortColumns = map[string]string{"id": "id", "name": "name", "release_date": "release_date", "rating": "rating"}
+	// actorSortColumns - столбцы сортировки актёров.
+	actorSortColumns = map[string]string{"id": "id", "name": "name", "gender": "gender", "date_of_birth": "date_of_birth"}
+	// seriesSortColumns - столбцы сортировки сериалов.
+	seriesSortColumns = map[string]string{"id": "id", "name": "name", "release_date": "release_date", "rating": "rating"}
+)
+
+// orderBy - получение выражения ORDER BY для сортировки.
+//
+// Принимает: сортировку и столбцы по полям сортировки.
+//
+// Возвращает: выражение, в конец которого добавлен id для однозначного порядка, и ошибку.
+func orderBy(sort models.Sort, columns map[string]string) (string, error) {
+	keys := make([]string, 0, len(sort)+1)
+	tiebreaker := true
+	for _, key := range sort {
+		column, ok := columns[key.Field]
+		if !ok {
+			return "", fmt.Errorf("unknown sort field %q", key.Field)
+		}
+		if key.Desc {
+			column += " DESC"
+		}
+		keys = append(keys, column)
+		if key.Field == "id" {
+			tiebreaker = false
+		}
+	}
+	if tiebreaker {
+		keys = append(keys, "id")
+	}
+	return "ORDER BY " + strings.Join(keys, ", "), nil
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+)
+
+// AddGenre - добавление жанра в БД.
+func (d dbProcessor) AddGenre(g models.GenreIn) (int, error) {
+	id, err := d.addSmthWithId(addGenre, "error while inserting genre", g.Name)
+	if isUniqueViolation(err) {
+		err = errors.Join(err, ErrGenreTaken)
+	}
+	return id, err
+}
+
+// UpdateGenre - обновление жанра в БД.
+func (d dbProcessor) UpdateGenre(id int, g models.GenreIn) error {
+	if err := execAffected(d.db, ErrUnknownGenre, updateGenreName, id, g.Name); err != nil {
+		if isUniqueViolation(err) {
+			err = errors.Join(err, ErrGenreTaken)
+		}
+		return errors.Join(fmt.Errorf("error while updating genre %d", id), err)
+	}
+	return nil
+}
+
+// DeleteGenre - удаление жанра из БД.
+func (d dbProcessor) DeleteGenre(id int) error {
+	return d.deleteSmth(removeGenre, fmt.Sprintf("error while deleting genre %d", id), ErrUnknownGenre, id)
+}
+
+// GetGenre - получение жанра из БД.
+func (d dbProcessor) GetGenre(id int) (models.GenreOut, error) {
+	var genre models.GenreOut
+	if err := d.db.Get(&genre, getGenre, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.Join(err, ErrUnknownGenre)
+		}
+		return models.GenreOut{}, errors.Join(fmt.Errorf("error while getting genre %d", id), err)
+	}
+	return genre, nil
+}
+
+// GetGenres - получение всех жанров из БД.
+func (d dbProcessor) GetGenres() ([]models.GenreOut, error) {
+	genres := []models.GenreOut{}
+	if err := d.db.Select(&genres, getGenres); err != nil {
+		return nil, errors.Join(errors.New("error while getting genres"), err)
+	}
+	return genres, nil
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// SetMoviePoster - замена ключа файла постера фильма в БД.
+func (d dbProcessor) SetMoviePoster(id int, key string) (string, error) {
+	return d.replaceKey(updateMoviePoster, fmt.Sprintf("error while setting poster of movie %d", id), ErrUnknownMovie, id, key)
+}
+
+// SetActorPhoto - замена ключа файла фотографии актёра в БД.
+func (d dbProcessor) SetActorPhoto(id int, key string) (string, error) {
+	return d.replaceKey(updateActorPhoto, fmt.Sprintf("error while setting photo of actor %d", id), ErrUnknownActor, id, key)
+}
+
+// replaceKey - замена ключа файла с получением прежнего ключа.
+//
+// Принимает: запрос замены, текст ошибки, ошибку отсутствия записи, id записи и новый ключ.
+//
+// Возвращает: прежний ключ и ошибку.
+func (d dbProcessor) replaceKey(query, errTxt string, notFound error, id int, key string) (string, error) {
+	var old string
+	if err := d.db.QueryRow(query, id, key).Scan(&old); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.Join(err, notFound)
+		}
+		return "", errors.Join(errors.New(errTxt), err)
+	}
+	return old, nil
+}
+
+// deleteWithKey - удаление записи с получением ключа файла её изображения.
+//
+// Принимает: запрос удаления, текст ошибки, ошибку отсутствия записи и id записи.
+//
+// Возвращает: ключ файла изображения (пустой, если изображения нет) и ошибку.
+func (d dbProcessor) deleteWithKey(query, errTxt string, notFound error, id int) (string, error) {
+	var key string
+	if err := d.db.QueryRow(query, id).Scan(&key); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.Join(err, notFound)
+		}
+		return "", errors.Join(errors.New(errTxt), classify(err))
+	}
+	return key, nil
+}
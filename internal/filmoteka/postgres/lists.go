@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/lib/pq"
+)
+
+// AddToList - добавление фильма в личный список пользователя в БД.
+func (d dbProcessor) AddToList(nickname, list string, movieId int, e models.ListEntryIn) error {
+	if err := execAffected(d.db, ErrUnknownUser, addToList, nickname, movieId, list, e.WatchedAt); err != nil {
+		if isForeignKeyViolation(err) {
+			err = errors.Join(err, ErrUnknownMovie)
+		}
+		return errors.Join(fmt.Errorf("error while adding movie %d to %s", movieId, list), err)
+	}
+	return nil
+}
+
+// RemoveFromList - удаление фильма из личного списка пользователя в БД.
+func (d dbProcessor) RemoveFromList(nickname, list string, movieId int) error {
+	if err := execAffected(d.db, ErrNotInList, removeFromList, nickname, list, movieId); err != nil {
+		return errors.Join(fmt.Errorf("error while removing movie %d from %s", movieId, list), err)
+	}
+	return nil
+}
+
+// GetList - получение страницы фильмов личного списка пользователя из БД.
+func (d dbProcessor) GetList(nickname, list string, page models.Page) ([]models.MovieOut, int, error) {
+	wrapErr := fmt.Errorf("error while getting %s of user %s", list, nickname)
+	movies := []models.MovieOut{}
+	if err := d.db.Select(&movies, getListMovies, nickname, list, page.Limit, page.Offset); err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+
+	if err := d.fillMovies(movies); err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	if err := d.FillMovieStatuses(nickname, movies); err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	total, err := countSmth(d.db, page, len(movies), countListMovies, nickname, list)
+	if err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	return movies, total, nil
+}
+
+// movieStatus - строка положения фильма в личном списке.
+type movieStatus struct {
+	MovieId   int        `db:"movie_id"`
+	List      string     `db:"list"`
+	WatchedAt *time.Time `db:"watched_at"`
+}
+
+// FillMovieStatuses - заполнение положения фильмов в личных списках пользователя из БД одним запросом.
+func (d dbProcessor) FillMovieStatuses(nickname string, movies []models.MovieOut) error {
+	if len(movies) == 0 {
+		return nil
+	}
+	ids := make([]int, len(movies))
+	index := make(map[int]int, len(movies))
+	for i := range movies {
+		ids[i] = movies[i].Id
+		index[movies[i].Id] = i
+		movies[i].Status = &models.MovieStatus{}
+	}
+
+	var statuses []movieStatus
+	if err := d.db.Select(&statuses, getMoviesStatuses, nickname, pq.Array(ids)); err != nil {
+		return errors.Join(fmt.Errorf("error while getting movie statuses of user %s", nickname), err)
+	}
+	for _, s := range statuses {
+		if i, ok := index[s.MovieId]; ok {
+			movies[i].Status.Set(s.List, s.WatchedAt)
+		}
+	}
+	return nil
+}
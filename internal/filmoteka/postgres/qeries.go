@@ -5,7 +5,7 @@ const (
 	// SQL запрос для создания таблицы пользователей.
 	createUsers = `CREATE TABLE IF NOT EXISTS users (
 		id SERIAL PRIMARY KEY,
-		name TEXT NOT NULL,
+		name TEXT NOT NULL UNIQUE,
 		password TEXT NOT NULL,
 		is_admin BOOlEAN NOT NULL
 		);`
@@ -24,14 +24,206 @@ const (
 		release_date DATE NOT NULL,
 		rating INTEGER NOT NULL
 		);`
-	// SQL запрос для создания таблицы отношений фильмов и актёров.
+	// SQL запрос для создания таблицы участия людей в фильмах.
+	// Люди хранятся в таблице actors, тип участия - в столбце credit (см. createCredits).
 	createActorMovieRelations = `CREATE TABLE IF NOT EXISTS movie_actors (
 		movie_id INTEGER NOT NULL,
 		actor_id INTEGER NOT NULL,
 		FOREIGN KEY (movie_id) REFERENCES movies(id),
-    	FOREIGN KEY (actor_id) REFERENCES actors(id),
-    	PRIMARY KEY (movie_id, actor_id)
+    	FOREIGN KEY (actor_id) REFERENCES actors(id)
+		);`
+	// SQL запрос для добавления типа участия в таблицу movie_actors.
+	// Существующие связи становятся актёрскими, а человек может участвовать в фильме в нескольких ролях.
+	createCredits = `ALTER TABLE movie_actors ADD COLUMN IF NOT EXISTS credit TEXT NOT NULL DEFAULT 'actor'
+			CHECK (credit IN ('actor', 'director', 'writer', 'producer', 'composer'));
+		ALTER TABLE movie_actors DROP CONSTRAINT IF EXISTS movie_actors_pkey;
+		CREATE UNIQUE INDEX IF NOT EXISTS movie_actors_credit_idx ON movie_actors (movie_id, actor_id, credit);
+		CREATE INDEX IF NOT EXISTS movie_actors_person_idx ON movie_actors (actor_id, credit);`
+	// SQL запрос для добавления в таблицу movie_actors данных о ролях актёров:
+	// имени персонажа, позиции в титрах и признаков камео и озвучки.
+	createCast = `ALTER TABLE movie_actors ADD COLUMN IF NOT EXISTS character_name TEXT NOT NULL DEFAULT '',
+		ADD COLUMN IF NOT EXISTS billing INTEGER,
+		ADD COLUMN IF NOT EXISTS cameo BOOLEAN NOT NULL DEFAULT false,
+		ADD COLUMN IF NOT EXISTS voice BOOLEAN NOT NULL DEFAULT false;`
+	// SQL запрос для добавления ключей файлов постеров фильмов и фотографий актёров в хранилище.
+	createImages = `ALTER TABLE movies ADD COLUMN IF NOT EXISTS poster TEXT;
+		ALTER TABLE actors ADD COLUMN IF NOT EXISTS photo TEXT;`
+	// SQL запрос для добавления в таблицу фильмов дополнительных сведений: продолжительности, стран производства,
+	// языков, возрастного рейтинга, бюджета и сборов в валюте.
+	createMovieMeta = `ALTER TABLE movies ADD COLUMN IF NOT EXISTS runtime INTEGER CHECK (runtime > 0),
+		ADD COLUMN IF NOT EXISTS countries TEXT[] NOT NULL DEFAULT '{}',
+		ADD COLUMN IF NOT EXISTS original_language TEXT,
+		ADD COLUMN IF NOT EXISTS languages TEXT[] NOT NULL DEFAULT '{}',
+		ADD COLUMN IF NOT EXISTS age_rating TEXT CHECK (age_rating IN ('0+', '6+', '12+', '16+', '18+')),
+		ADD COLUMN IF NOT EXISTS budget BIGINT CHECK (budget >= 0),
+		ADD COLUMN IF NOT EXISTS box_office BIGINT CHECK (box_office >= 0),
+		ADD COLUMN IF NOT EXISTS currency TEXT;
+		CREATE INDEX IF NOT EXISTS movies_countries_idx ON movies USING GIN (countries);
+		CREATE INDEX IF NOT EXISTS movies_languages_idx ON movies USING GIN (languages);`
+	// SQL запрос для создания таблицы жанров.
+	createGenres = `CREATE TABLE IF NOT EXISTS genres (
+		id SERIAL PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE
+		);`
+	// SQL запрос для создания таблицы отношений фильмов и жанров.
+	createMovieGenres = `CREATE TABLE IF NOT EXISTS movie_genres (
+		movie_id INTEGER NOT NULL,
+		genre_id INTEGER NOT NULL,
+		FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+		FOREIGN KEY (genre_id) REFERENCES genres(id) ON DELETE CASCADE,
+		PRIMARY KEY (movie_id, genre_id)
+		);`
+	// SQL запрос для создания таблицы оценок и отзывов пользователей о фильмах.
+	createReviews = `CREATE TABLE IF NOT EXISTS reviews (
+		id SERIAL PRIMARY KEY,
+		user_id INTEGER NOT NULL,
+		movie_id INTEGER NOT NULL,
+		rating INTEGER NOT NULL CHECK (rating BETWEEN 0 AND 10),
+		text TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+		UNIQUE (user_id, movie_id)
+		);
+		CREATE INDEX IF NOT EXISTS reviews_movie_idx ON reviews (movie_id);`
+	// SQL запрос для создания таблицы личных списков фильмов пользователей.
+	createUserMovies = `CREATE TABLE IF NOT EXISTS user_movies (
+		user_id INTEGER NOT NULL,
+		movie_id INTEGER NOT NULL,
+		list TEXT NOT NULL CHECK (list IN ('watchlist', 'favorites', 'watched')),
+		watched_at DATE,
+		added_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+		PRIMARY KEY (user_id, list, movie_id)
+		);`
+	// SQL запрос для создания таблицы коллекций фильмов.
+	createCollections = `CREATE TABLE IF NOT EXISTS collections (
+		id SERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		kind TEXT NOT NULL DEFAULT 'collection' CHECK (kind IN ('collection', 'franchise')),
+		public BOOLEAN NOT NULL DEFAULT false,
+		owner_id INTEGER NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE
+		);`
+	// SQL запрос для создания таблицы фильмов коллекций с их порядком.
+	createCollectionMovies = `CREATE TABLE IF NOT EXISTS collection_movies (
+		collection_id INTEGER NOT NULL,
+		movie_id INTEGER NOT NULL,
+		position INTEGER NOT NULL,
+		FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE,
+		FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+		PRIMARY KEY (collection_id, movie_id)
+		);`
+	// SQL запрос для создания таблицы сериалов.
+	createSeries = `CREATE TABLE IF NOT EXISTS series (
+		id SERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL,
+		release_date DATE NOT NULL,
+		rating INTEGER NOT NULL
+		);`
+	// SQL запрос для создания таблицы сезонов сериалов.
+	createSeasons = `CREATE TABLE IF NOT EXISTS seasons (
+		id SERIAL PRIMARY KEY,
+		series_id INTEGER NOT NULL,
+		number INTEGER NOT NULL CHECK (number > 0),
+		name TEXT NOT NULL DEFAULT '',
+		FOREIGN KEY (series_id) REFERENCES series(id) ON DELETE CASCADE,
+		UNIQUE (series_id, number)
+		);`
+	// SQL запрос для создания таблицы серий сезонов.
+	createEpisodes = `CREATE TABLE IF NOT EXISTS episodes (
+		id SERIAL PRIMARY KEY,
+		season_id INTEGER NOT NULL,
+		number INTEGER NOT NULL CHECK (number > 0),
+		name TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		release_date DATE NOT NULL,
+		rating INTEGER NOT NULL,
+		FOREIGN KEY (season_id) REFERENCES seasons(id) ON DELETE CASCADE,
+		UNIQUE (season_id, number)
 		);`
+	// SQL запрос для создания таблицы приглашённых актёров серий.
+	createEpisodeActors = `CREATE TABLE IF NOT EXISTS episode_actors (
+		episode_id INTEGER NOT NULL,
+		actor_id INTEGER NOT NULL,
+		character_name TEXT NOT NULL DEFAULT '',
+		billing INTEGER,
+		cameo BOOLEAN NOT NULL DEFAULT false,
+		voice BOOLEAN NOT NULL DEFAULT false,
+		FOREIGN KEY (episode_id) REFERENCES episodes(id) ON DELETE CASCADE,
+		FOREIGN KEY (actor_id) REFERENCES actors(id) ON DELETE CASCADE,
+		PRIMARY KEY (episode_id, actor_id)
+		);
+		CREATE INDEX IF NOT EXISTS episode_actors_actor_idx ON episode_actors (actor_id);`
+	// SQL запрос для создания таблицы сессий пользователей.
+	createSessions = `CREATE TABLE IF NOT EXISTS sessions (
+		id TEXT PRIMARY KEY,
+		nickname TEXT NOT NULL,
+		superuser BOOLEAN NOT NULL,
+		expires_at TIMESTAMPTZ NOT NULL
+		);`
+	// SQL запрос для создания таблицы ролей.
+	createRoles = `CREATE TABLE IF NOT EXISTS roles (
+		name TEXT PRIMARY KEY
+		);`
+	// SQL запрос для создания таблицы прав ролей.
+	createRolePermissions = `CREATE TABLE IF NOT EXISTS role_permissions (
+		role TEXT NOT NULL,
+		permission TEXT NOT NULL,
+		FOREIGN KEY (role) REFERENCES roles(name) ON DELETE CASCADE,
+		PRIMARY KEY (role, permission)
+		);`
+	// SQL запрос для создания таблицы ролей пользователей.
+	createUserRoles = `CREATE TABLE IF NOT EXISTS user_roles (
+		user_id INTEGER NOT NULL,
+		role TEXT NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (role) REFERENCES roles(name),
+		PRIMARY KEY (user_id, role)
+		);`
+	// SQL запрос для создания триграммных индексов для поиска по подстроке и индексов для поиска по префиксу.
+	createSearchIndexes = `CREATE EXTENSION IF NOT EXISTS pg_trgm;
+		CREATE INDEX IF NOT EXISTS movies_name_trgm_idx ON movies USING GIN (name gin_trgm_ops);
+		CREATE INDEX IF NOT EXISTS actors_name_trgm_idx ON actors USING GIN (name gin_trgm_ops);
+		CREATE INDEX IF NOT EXISTS movies_name_prefix_idx ON movies (lower(name) text_pattern_ops);
+		CREATE INDEX IF NOT EXISTS actors_name_prefix_idx ON actors (lower(name) text_pattern_ops);`
+	// SQL запрос для создания столбцов и индексов полнотекстового поиска.
+	// Конфигурация russian применяет русский стеммер к кириллице и английский - к латинице.
+	createFullTextSearch = `ALTER TABLE movies ADD COLUMN IF NOT EXISTS search TSVECTOR
+			GENERATED ALWAYS AS (setweight(to_tsvector('russian', name), 'A') || setweight(to_tsvector('russian', description), 'B')) STORED;
+		ALTER TABLE actors ADD COLUMN IF NOT EXISTS search TSVECTOR
+			GENERATED ALWAYS AS (to_tsvector('russian', name)) STORED;
+		CREATE INDEX IF NOT EXISTS movies_search_idx ON movies USING GIN (search);
+		CREATE INDEX IF NOT EXISTS actors_search_idx ON actors USING GIN (search);`
+)
+
+// SQL запросы для заполнения и миграции таблиц.
+const (
+	// SQL запрос для добавления встроенных ролей.
+	seedRoles = `INSERT INTO roles (name) VALUES
+		('viewer'), ('editor'), ('user_admin'), ('admin')
+		ON CONFLICT DO NOTHING;`
+	// SQL запрос для добавления прав встроенных ролей.
+	seedRolePermissions = `INSERT INTO role_permissions (role, permission) VALUES
+		('viewer', 'catalog:read'),
+		('editor', 'catalog:read'), ('editor', 'catalog:write'),
+		('user_admin', 'catalog:read'), ('user_admin', 'users:manage'),
+		('admin', 'catalog:read'), ('admin', 'catalog:write'), ('admin', 'users:manage')
+		ON CONFLICT DO NOTHING;`
+	// SQL запрос для проверки существования таблицы ролей пользователей.
+	userRolesExist = `SELECT to_regclass('user_roles') IS NOT NULL;`
+	// SQL запрос для назначения ролей пользователям без ролей по флагу is_admin.
+	migrateUserRoles = `INSERT INTO user_roles (user_id, role)
+		SELECT id, CASE WHEN is_admin THEN 'admin' ELSE 'viewer' END FROM users
+		WHERE id NOT IN (SELECT user_id FROM user_roles)
+		ON CONFLICT DO NOTHING;`
+	// SQL запрос для добавления уникального индекса имён пользователей в таблицу, созданную без него.
+	migrateUsersName = `CREATE UNIQUE INDEX IF NOT EXISTS users_name_key ON users (name);`
 )
 
 // SQL запросы для удаления таблиц.
@@ -44,6 +236,34 @@ const (
 	dropActors = `DROP TABLE IF EXISTS actors;`
 	// SQL запрос для удаления таблицы отношений фильмов и актёров.
 	dropMovieActors = `DROP TABLE IF EXISTS movie_actors;`
+	// SQL запрос для удаления таблицы отношений фильмов и жанров.
+	dropMovieGenres = `DROP TABLE IF EXISTS movie_genres;`
+	// SQL запрос для удаления таблицы жанров.
+	dropGenres = `DROP TABLE IF EXISTS genres;`
+	// SQL запрос для удаления таблицы отзывов.
+	dropReviews = `DROP TABLE IF EXISTS reviews;`
+	// SQL запрос для удаления таблицы личных списков.
+	dropUserMovies = `DROP TABLE IF EXISTS user_movies;`
+	// SQL запрос для удаления таблицы фильмов коллекций.
+	dropCollectionMovies = `DROP TABLE IF EXISTS collection_movies;`
+	// SQL запрос для удаления таблицы коллекций.
+	dropCollections = `DROP TABLE IF EXISTS collections;`
+	// SQL запрос для удаления таблицы приглашённых актёров серий.
+	dropEpisodeActors = `DROP TABLE IF EXISTS episode_actors;`
+	// SQL запрос для удаления таблицы серий.
+	dropEpisodes = `DROP TABLE IF EXISTS episodes;`
+	// SQL запрос для удаления таблицы сезонов.
+	dropSeasons = `DROP TABLE IF EXISTS seasons;`
+	// SQL запрос для удаления таблицы сериалов.
+	dropSeries = `DROP TABLE IF EXISTS series;`
+	// SQL запрос для удаления таблицы сессий.
+	dropSessions = `DROP TABLE IF EXISTS sessions;`
+	// SQL запрос для удаления таблицы ролей пользователей.
+	dropUserRoles = `DROP TABLE IF EXISTS user_roles;`
+	// SQL запрос для удаления таблицы прав ролей.
+	dropRolePermissions = `DROP TABLE IF EXISTS role_permissions;`
+	// SQL запрос для удаления таблицы ролей.
+	dropRoles = `DROP TABLE IF EXISTS roles;`
 )
 
 // SQL запросы для добавления данных в БД.
@@ -55,17 +275,85 @@ const (
 	// SQL запрос для добавления фильма по name, description, release_date, rating.
 	addMovie = `INSERT INTO movies (name, description, release_date, rating) VALUES ($1, $2, $3, $4) RETURNING id;`
 	// SQL запрос для добавления актёра в фильм по movie_id, actor_id.
-	addActorToMovie = `INSERT INTO movie_actors (movie_id, actor_id) VALUES ($1, $2);`
+	addCreditToMovie = `INSERT INTO movie_actors (movie_id, actor_id, credit) VALUES ($1, $2, $3);`
+	// SQL запрос для добавления роли актёра в фильм.
+	addCastToMovie = `INSERT INTO movie_actors (movie_id, actor_id, character_name, billing, cameo, voice) VALUES ($1, $2, $3, $4, $5, $6);`
+	// SQL запрос для добавления жанра.
+	addGenre = `INSERT INTO genres (name) VALUES ($1) RETURNING id;`
+	// SQL запрос для добавления жанра фильму.
+	addGenreToMovie = `INSERT INTO movie_genres (movie_id, genre_id) VALUES ($1, $2);`
+	// SQL запрос для добавления отзыва пользователя о фильме по name, movie_id, rating, text.
+	addReview = `INSERT INTO reviews (user_id, movie_id, rating, text)
+		SELECT id, $2::integer, $3::integer, $4::text FROM users WHERE name = $1 RETURNING id;`
+	// SQL запрос для добавления фильма в личный список пользователя по name, movie_id, list, watched_at.
+	// Повторное добавление обновляет дату просмотра.
+	addToList = `INSERT INTO user_movies (user_id, movie_id, list, watched_at)
+		SELECT id, $2::integer, $3::text, $4::date FROM users WHERE name = $1
+		ON CONFLICT (user_id, list, movie_id) DO UPDATE SET watched_at = EXCLUDED.watched_at;`
+	// SQL запрос для добавления коллекции по name, description, kind, public и name владельца.
+	addCollection = `INSERT INTO collections (name, description, kind, public, owner_id)
+		SELECT $1::text, $2::text, $3::text, $4::boolean, id FROM users WHERE name = $5 RETURNING id;`
+	// SQL запрос для добавления фильма в коллекцию по collection_id, movie_id, position.
+	addMovieToCollection = `INSERT INTO collection_movies (collection_id, movie_id, position) VALUES ($1, $2, $3);`
+	// SQL запрос для добавления сериала по name, description, release_date, rating.
+	addSeries = `INSERT INTO series (name, description, release_date, rating) VALUES ($1, $2, $3, $4) RETURNING id;`
+	// SQL запрос для добавления сезона сериала по series_id, number, name.
+	addSeason = `INSERT INTO seasons (series_id, number, name) VALUES ($1, $2, $3) RETURNING id;`
+	// SQL запрос для добавления серии сезона по season_id, number, name, description, release_date, rating.
+	addEpisode = `INSERT INTO episodes (season_id, number, name, description, release_date, rating)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`
+	// SQL запрос для добавления приглашённого актёра в серию.
+	addCastToEpisode = `INSERT INTO episode_actors (episode_id, actor_id, character_name, billing, cameo, voice) VALUES ($1, $2, $3, $4, $5, $6);`
+	// SQL запрос для добавления сессии по id, nickname, superuser, expires_at.
+	addSession = `INSERT INTO sessions (id, nickname, superuser, expires_at) VALUES ($1, $2, $3, $4);`
+	// SQL запрос для добавления роли пользователю по user_id, role.
+	addUserRole = `INSERT INTO user_roles (user_id, role) VALUES ($1, $2);`
 )
 
 // SQL запросы для удаления данных.
 const (
 	// SQL запрос для удаления актёра по id.
-	removeActor = `DELETE FROM actors WHERE id = $1; DELETE FROM movie_actors WHERE actor_id = $1;`
+	removeActor = `WITH links AS (DELETE FROM movie_actors WHERE actor_id = $1) DELETE FROM actors WHERE id = $1
+		RETURNING COALESCE(photo, '');`
 	// SQL запрос для удаления фильма по id.
-	removeMovie = `DELETE FROM movies WHERE id = $1; DELETE FROM movie_actors WHERE movie_id = $1;`
+	removeMovie = `WITH links AS (DELETE FROM movie_actors WHERE movie_id = $1) DELETE FROM movies WHERE id = $1
+		RETURNING COALESCE(poster, '');`
 	// SQL запрос для удаления фильма из работ актёров по movie_id.
-	removeMovieFromActors = `DELETE FROM movie_actors WHERE movie_id = $1;`
+	removeMovieFromActors = `DELETE FROM movie_actors WHERE movie_id = $1 AND credit = 'actor';`
+	// SQL запрос для удаления всех участников фильма.
+	removeMovieCredits = `DELETE FROM movie_actors WHERE movie_id = $1;`
+	// SQL запрос для удаления жанров фильма.
+	removeMovieGenres = `DELETE FROM movie_genres WHERE movie_id = $1;`
+	// SQL запрос для удаления жанра вместе с его связями с фильмами.
+	removeGenre = `DELETE FROM genres WHERE id = $1;`
+	// SQL запрос для удаления отзыва по id и name его автора.
+	removeReview = `DELETE FROM reviews WHERE id = $1 AND user_id = (SELECT id FROM users WHERE name = $2);`
+	// SQL запрос для удаления фильма из личного списка пользователя по name, list, movie_id.
+	removeFromList = `DELETE FROM user_movies WHERE user_id = (SELECT id FROM users WHERE name = $1) AND list = $2 AND movie_id = $3;`
+	// SQL запрос для удаления коллекции по id, name пользователя и признаку модератора.
+	removeCollection = `DELETE FROM collections WHERE id = $1 AND (owner_id = (SELECT id FROM users WHERE name = $2) OR $3);`
+	// SQL запрос для удаления фильмов коллекции.
+	removeCollectionMovies = `DELETE FROM collection_movies WHERE collection_id = $1;`
+	// SQL запрос для удаления сериала вместе с его сезонами и сериями по id.
+	removeSeries = `DELETE FROM series WHERE id = $1;`
+	// SQL запрос для удаления сезона вместе с его сериями по id.
+	removeSeason = `DELETE FROM seasons WHERE id = $1;`
+	// SQL запрос для удаления серии по id.
+	removeEpisode = `DELETE FROM episodes WHERE id = $1;`
+	// SQL запрос для удаления приглашённых актёров серии.
+	removeEpisodeCast = `DELETE FROM episode_actors WHERE episode_id = $1;`
+	// SQL запрос для удаления пользователя по id.
+	removeUser = `DELETE FROM users WHERE id = $1;`
+	// SQL запрос для удаления ролей пользователя по user_id.
+	removeUserRoles = `DELETE FROM user_roles WHERE user_id = $1;`
+	// SQL запрос для удаления сессий пользователя по id пользователя.
+	removeUserSessions = `DELETE FROM sessions WHERE nickname IN (SELECT name FROM users WHERE id = $1);`
+	// SQL запрос для удаления сессий пользователя по nickname.
+	removeUserSessionsByName = `DELETE FROM sessions WHERE nickname = $1;`
+	// SQL запрос для удаления сессии по id.
+	removeSession = `DELETE FROM sessions WHERE id = $1;`
+	// SQL запрос для удаления истёкших сессий.
+	removeExpiredSessions = `DELETE FROM sessions WHERE expires_at <= NOW();`
 )
 
 // SQL запросы для обновления данных.
@@ -78,39 +366,263 @@ const (
 	updateMovieReleaseDate = `UPDATE movies SET release_date = $2 WHERE id = $1;`
 	// SQL запрос для обновления фильма по id, rating
 	updateMovieRating = `UPDATE movies SET rating = $2 WHERE id = $1;`
+	// SQL запрос для обновления дополнительных сведений о фильме по id, runtime, countries, original_language, languages,
+	// age_rating, budget, box_office, currency. Незаданные (NULL или пустые) сведения не меняются.
+	updateMovieMeta = `UPDATE movies SET runtime = COALESCE($2::integer, runtime), countries = COALESCE($3::text[], countries),
+		original_language = COALESCE(NULLIF($4::text, ''), original_language), languages = COALESCE($5::text[], languages),
+		age_rating = COALESCE(NULLIF($6::text, ''), age_rating), budget = COALESCE($7::bigint, budget),
+		box_office = COALESCE($8::bigint, box_office), currency = COALESCE(NULLIF($9::text, ''), currency)
+		WHERE id = $1;`
+	// SQL запрос для замены ключа файла постера фильма по id, ключу (пустой - без постера) с возвращением прежнего ключа.
+	updateMoviePoster = `UPDATE movies m SET poster = NULLIF($2::text, '') FROM (SELECT id, poster FROM movies WHERE id = $1 FOR UPDATE) old
+		WHERE m.id = old.id RETURNING COALESCE(old.poster, '');`
 	// SQL запрос для обновления актёра по id, name
 	updateActorName = `UPDATE actors SET name = $2 WHERE id = $1;`
 	// SQL запрос для обновления актёра по id, gender
 	updateActorGender = `UPDATE actors SET gender = $2 WHERE id = $1;`
 	// SQL запрос для обновления актёра по id, date_of_birth
 	updateActorDateOfBirth = `UPDATE actors SET date_of_birth = $2 WHERE id = $1;`
+	// SQL запрос для замены ключа файла фотографии актёра по id, ключу (пустой - без фотографии) с возвращением прежнего ключа.
+	updateActorPhoto = `UPDATE actors a SET photo = NULLIF($2::text, '') FROM (SELECT id, photo FROM actors WHERE id = $1 FOR UPDATE) old
+		WHERE a.id = old.id RETURNING COALESCE(old.photo, '');`
+	// SQL запрос для обновления пользователя по id, is_admin
+	updateUserIsAdmin = `UPDATE users SET is_admin = $2 WHERE id = $1;`
+	// SQL запрос для обновления пароля пользователя по name, password
+	updateUserPassword = `UPDATE users SET password = $2 WHERE name = $1;`
+	// SQL запрос для обновления названия жанра.
+	updateGenreName = `UPDATE genres SET name = $2 WHERE id = $1;`
+	// SQL запрос для обновления отзыва по id, name его автора, rating, text.
+	updateReview = `UPDATE reviews SET rating = $3, text = $4, updated_at = NOW()
+		WHERE id = $1 AND user_id = (SELECT id FROM users WHERE name = $2);`
+	// SQL запрос для обновления коллекции по id, name пользователя, признаку модератора, name, description, kind, public.
+	// Пустые значения не изменяют поля.
+	updateCollection = `UPDATE collections SET name = COALESCE(NULLIF($4::text, ''), name),
+		description = COALESCE(NULLIF($5::text, ''), description), kind = COALESCE(NULLIF($6::text, ''), kind),
+		public = COALESCE($7::boolean, public), updated_at = NOW()
+		WHERE id = $1 AND (owner_id = (SELECT id FROM users WHERE name = $2) OR $3);`
+	// SQL запрос для отметки изменения коллекции по id, name пользователя и признаку модератора.
+	touchCollection = `UPDATE collections SET updated_at = NOW()
+		WHERE id = $1 AND (owner_id = (SELECT id FROM users WHERE name = $2) OR $3);`
+	// SQL запрос для обновления сериала по id, name, description, release_date, rating.
+	// Пустые значения не изменяют поля.
+	updateSeries = `UPDATE series SET name = COALESCE(NULLIF($2::text, ''), name), description = COALESCE(NULLIF($3::text, ''), description),
+		release_date = COALESCE($4::date, release_date), rating = COALESCE($5::integer, rating) WHERE id = $1;`
+	// SQL запрос для обновления сезона по id, number, name. Пустые значения не изменяют поля.
+	updateSeason = `UPDATE seasons SET number = COALESCE(NULLIF($2::integer, 0), number), name = COALESCE(NULLIF($3::text, ''), name) WHERE id = $1;`
+	// SQL запрос для обновления серии по id, number, name, description, release_date, rating.
+	// Пустые значения не изменяют поля.
+	updateEpisode = `UPDATE episodes SET number = COALESCE(NULLIF($2::integer, 0), number), name = COALESCE(NULLIF($3::text, ''), name),
+		description = COALESCE(NULLIF($4::text, ''), description), release_date = COALESCE($5::date, release_date),
+		rating = COALESCE($6::integer, rating)
+		WHERE id = $1;`
 )
 
 // SQL запросы для получения данных.
 const (
 	// SQL запрос для получения актёра по id.
-	getActor = `SELECT * FROM actors where id = $1;`
-	// SQL запрос для получения актёров.
-	getActors = `SELECT * from actors;`
-	// SQL запрос для получения фильмов, в которых играл актёр, по actor_id.
-	getActorMovies = `SELECT movie_id FROM movie_actors WHERE actor_id = $1;`
+	getActor = `SELECT id, name, gender, date_of_birth, COALESCE(photo, '') AS photo FROM actors where id = $1;`
+	// Начало SQL запроса для получения актёров, дополняемое сортировкой, limit и offset.
+	selectActors = `SELECT id, name, gender, date_of_birth, COALESCE(photo, '') AS photo FROM actors`
+	// SQL запрос для получения количества актёров.
+	countActors = `SELECT COUNT(*) FROM actors;`
+	// SQL запрос для получения страницы актёров по шаблону имени или схожести имени с вариантами написания,
+	// отсортированных по убыванию схожести, по шаблону, трём вариантам, limit, offset.
+	getActorsByName = `SELECT id, name, gender, date_of_birth, COALESCE(photo, '') AS photo FROM actors
+		WHERE name ILIKE $1 OR name %> $2 OR name %> $3 OR name %> $4
+		ORDER BY CASE WHEN name ILIKE $1 THEN 1
+			ELSE GREATEST(word_similarity($2, name), word_similarity($3, name), word_similarity($4, name)) END DESC, id
+		LIMIT $5 OFFSET $6;`
+	// SQL запрос для получения количества актёров по шаблону имени или схожести имени с вариантами написания.
+	countActorsByName = `SELECT COUNT(*) FROM actors WHERE name ILIKE $1 OR name %> $2 OR name %> $3 OR name %> $4;`
+	// SQL запрос для получения ролей актёра в порядке титров по actor_id.
+	getActorMovies = `SELECT actor_id, movie_id, character_name, COALESCE(billing, 0) AS billing, cameo, voice
+		FROM movie_actors ma WHERE actor_id = $1 AND credit = 'actor' ORDER BY ma.billing NULLS LAST, movie_id;`
+	// SQL запрос для получения ролей актёров в порядке титров по массиву actor_id.
+	getActorsMovies = `SELECT actor_id, movie_id, character_name, COALESCE(billing, 0) AS billing, cameo, voice
+		FROM movie_actors ma WHERE actor_id = ANY($1) AND credit = 'actor' ORDER BY actor_id, ma.billing NULLS LAST, movie_id;`
+	// SQL запрос для получения жанра по id.
+	getGenre = `SELECT id, name FROM genres WHERE id = $1;`
+	// SQL запрос для получения всех жанров.
+	getGenres = `SELECT id, name FROM genres ORDER BY name, id;`
+	// SQL запрос для получения жанров фильма по movie_id.
+	getMovieGenres = `SELECT genre_id FROM movie_genres WHERE movie_id = $1 ORDER BY genre_id;`
+	// SQL запрос для получения связей фильмов с жанрами по массиву movie_id.
+	getMoviesGenres = `SELECT movie_id, genre_id FROM movie_genres WHERE movie_id = ANY($1) ORDER BY movie_id, genre_id;`
+	// Столбцы фильма вместе со средней оценкой пользователей и количеством оценок
+	// для запросов к movies под псевдонимом m, соединённых с movieVotes.
+	movieColumns = `m.id, m.name, m.description, m.release_date, m.rating, v.user_rating, v.votes, COALESCE(m.poster, '') AS poster,
+		m.runtime, m.countries, COALESCE(m.original_language, '') AS original_language, m.languages, COALESCE(m.age_rating, '') AS age_rating,
+		m.budget, m.box_office, COALESCE(m.currency, '') AS currency`
+	// Соединение фильма m со средней оценкой пользователей и количеством оценок v.
+	movieVotes = `LEFT JOIN LATERAL (SELECT ROUND(AVG(r.rating), 2)::float8 AS user_rating, COUNT(*) AS votes
+			FROM reviews r WHERE r.movie_id = m.id) v ON true`
 	// SQL запрос для получения фильма по id.
-	getMovie = `SELECT * FROM movies WHERE id = $1;`
-	// SQL запрос для получения актёров, которые играли в фильме, по movie_id.
-	getMovieActors = `SELECT actor_id FROM movie_actors WHERE movie_id = $1;`
-	// SQL запрос для получения фильмов, отсортированных по рейтингу.
-	getMoviesSortByRating = `SELECT * FROM movies ORDER BY rating DESC;`
-	// SQL запрос для получения фильмов, отсортированных по дате релиза.
-	getMoviesSortByReleaseDate = `SELECT * FROM movies ORDER BY release_date;`
-	// SQL запрос для получения фильмов, отсортированных по названию.
-	getMoviesSortByName = `SELECT * FROM movies ORDER BY name;`
-	// SQL запрос для получения фильмов по фрагменту имени актёра.
-	getMoviesByActor = `SELECT * FROM movies WHERE id IN (
-		SELECT movie_id FROM movie_actors ma JOIN actors a ON ma.actor_id = a.id
-		WHERE a.name LIKE '%$1%'
-		);`
-	// SQL запрос для получения фильмов по фрагменту названия.
-	getMoviesByName = `SELECT * FROM movies WHERE name LIKE '%$1%';`
-	// SQL запрос для получения статуса пользователя по name, password.
-	checkUserRole = `SELECT is_admin FROM users WHERE name = $1 AND password = $2;`
+	getMovie = `SELECT ` + movieColumns + ` FROM movies m
+		` + movieVotes + `
+		WHERE m.id = $1;`
+	// SQL запрос для получения участников фильма по movie_id, актёры - в порядке титров.
+	getMovieCredits = `SELECT movie_id, actor_id, credit, character_name, COALESCE(billing, 0) AS billing, cameo, voice
+		FROM movie_actors ma WHERE movie_id = $1
+		ORDER BY array_position(ARRAY['actor', 'director', 'writer', 'producer', 'composer'], credit), ma.billing NULLS LAST, actor_id;`
+	// SQL запрос для получения участников фильмов по массиву movie_id, актёры - в порядке титров.
+	getMoviesCredits = `SELECT movie_id, actor_id, credit, character_name, COALESCE(billing, 0) AS billing, cameo, voice
+		FROM movie_actors ma WHERE movie_id = ANY($1)
+		ORDER BY movie_id, array_position(ARRAY['actor', 'director', 'writer', 'producer', 'composer'], credit), ma.billing NULLS LAST, actor_id;`
+	// SQL запрос для проверки существования человека по id.
+	personExists = `SELECT EXISTS (SELECT 1 FROM actors WHERE id = $1);`
+	// Начало SQL запроса для получения фильмов вместе со средней оценкой пользователей и количеством оценок,
+	// дополняемое условиями фильтра, сортировкой, limit и offset.
+	selectMovies = `SELECT ` + movieColumns + ` FROM movies m
+		` + movieVotes
+	// Начало SQL запроса для получения количества фильмов, дополняемое условиями фильтра.
+	countMovies = `SELECT COUNT(*) FROM movies`
+	// SQL запрос для получения страницы фильмов по шаблону имени или схожести имени актёра с вариантами написания,
+	// отсортированных по убыванию схожести, по шаблону, трём вариантам, limit, offset.
+	getMoviesByActor = `SELECT ` + movieColumns + ` FROM movies m JOIN (
+			SELECT ma.movie_id, MAX(CASE WHEN a.name ILIKE $1 THEN 1
+				ELSE GREATEST(word_similarity($2, a.name), word_similarity($3, a.name), word_similarity($4, a.name)) END) AS score
+			FROM actors a JOIN movie_actors ma ON ma.actor_id = a.id AND ma.credit = 'actor'
+			WHERE a.name ILIKE $1 OR a.name %> $2 OR a.name %> $3 OR a.name %> $4
+			GROUP BY ma.movie_id
+		) hits ON hits.movie_id = m.id
+		` + movieVotes + `
+		ORDER BY hits.score DESC, m.id LIMIT $5 OFFSET $6;`
+	// SQL запрос для получения количества фильмов по шаблону имени или схожести имени актёра с вариантами написания.
+	countMoviesByActor = `SELECT COUNT(DISTINCT ma.movie_id) FROM actors a JOIN movie_actors ma ON ma.actor_id = a.id AND ma.credit = 'actor'
+		WHERE a.name ILIKE $1 OR a.name %> $2 OR a.name %> $3 OR a.name %> $4;`
+	// SQL запрос для получения страницы фильмов по шаблону или схожести названия с вариантами написания,
+	// отсортированных по убыванию схожести, по шаблону, трём вариантам, limit, offset.
+	getMoviesByName = `SELECT ` + movieColumns + ` FROM movies m
+		` + movieVotes + `
+		WHERE name ILIKE $1 OR name %> $2 OR name %> $3 OR name %> $4
+		ORDER BY CASE WHEN name ILIKE $1 THEN 1
+			ELSE GREATEST(word_similarity($2, name), word_similarity($3, name), word_similarity($4, name)) END DESC, id
+		LIMIT $5 OFFSET $6;`
+	// SQL запрос для получения количества фильмов по шаблону или схожести названия с вариантами написания.
+	countMoviesByName = `SELECT COUNT(*) FROM movies WHERE name ILIKE $1 OR name %> $2 OR name %> $3 OR name %> $4;`
+	// SQL запрос для получения подсказок актёров, имя или слово в имени которых начинается с префикса,
+	// по шаблону префикса имени, шаблону префикса слова, limit.
+	suggestActors = `SELECT id, name FROM actors
+		WHERE lower(name) LIKE $1 OR name ILIKE $2
+		ORDER BY lower(name) LIKE $1 DESC, name, id LIMIT $3;`
+	// SQL запрос для получения подсказок фильмов, название или слово в названии которых начинается с префикса,
+	// по шаблону префикса названия, шаблону префикса слова, limit.
+	suggestMovies = `SELECT id, name FROM movies
+		WHERE lower(name) LIKE $1 OR name ILIKE $2
+		ORDER BY lower(name) LIKE $1 DESC, name, id LIMIT $3;`
+	// SQL запрос для установки порога схожести в рамках транзакции.
+	setSimilarity = `SELECT set_config('pg_trgm.word_similarity_threshold', $1, true);`
+	// SQL запрос для полнотекстового поиска фильмов по названию, описанию и актёрам по запросу, limit.
+	// Название и описание экранируются для HTML до выделения совпадений тегами <b></b>, поэтому выделения можно выводить как HTML.
+	searchMovies = `WITH q AS (SELECT websearch_to_tsquery('russian', $1) AS query),
+		cast_hits AS (
+			SELECT ma.movie_id, MAX(ts_rank(a.search, q.query)) AS rank
+			FROM q, actors a JOIN movie_actors ma ON ma.actor_id = a.id AND ma.credit = 'actor'
+			WHERE a.search @@ q.query
+			GROUP BY ma.movie_id
+		)
+		SELECT m.id, m.name, m.release_date, m.rating,
+			ts_rank(m.search, q.query) + COALESCE(c.rank, 0) / 2 AS rank,
+			ts_headline('russian', replace(replace(replace(replace(m.name, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), q.query, 'HighlightAll=true') AS name_highlight,
+			ts_headline('russian', replace(replace(replace(replace(m.description, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), q.query, 'MaxFragments=2') AS description_highlight
+		FROM q, movies m LEFT JOIN cast_hits c ON c.movie_id = m.id
+		WHERE m.search @@ q.query OR c.movie_id IS NOT NULL
+		ORDER BY rank DESC, m.id LIMIT $2;`
+	// SQL запрос для полнотекстового поиска актёров по имени по запросу, limit.
+	// Как и для фильмов, имя экранируется для HTML до выделения совпадений.
+	searchActors = `WITH q AS (SELECT websearch_to_tsquery('russian', $1) AS query)
+		SELECT a.id, a.name, ts_rank(a.search, q.query) AS rank,
+			ts_headline('russian', replace(replace(replace(replace(a.name, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), q.query, 'HighlightAll=true') AS name_highlight
+		FROM q, actors a
+		WHERE a.search @@ q.query
+		ORDER BY rank DESC, a.id LIMIT $2;`
+	// SQL запрос для проверки существования фильма по id.
+	movieExists = `SELECT EXISTS (SELECT 1 FROM movies WHERE id = $1);`
+	// SQL запрос для блокировки фильма по id до конца транзакции.
+	lockMovie = `SELECT id FROM movies WHERE id = $1 FOR UPDATE;`
+	// SQL запрос для блокировки актёра по id до конца транзакции.
+	lockActor = `SELECT id FROM actors WHERE id = $1 FOR UPDATE;`
+	// SQL запрос для блокировки пользователя по id до конца транзакции.
+	lockUser = `SELECT id FROM users WHERE id = $1 FOR UPDATE;`
+	// SQL запрос для получения страницы отзывов о фильме, начиная с последних изменённых, по movie_id, limit, offset.
+	getMovieReviews = `SELECT r.id, r.movie_id, u.name AS nickname, r.rating, r.text, r.created_at, r.updated_at
+		FROM reviews r JOIN users u ON u.id = r.user_id
+		WHERE r.movie_id = $1 ORDER BY r.updated_at DESC, r.id DESC LIMIT $2 OFFSET $3;`
+	// SQL запрос для получения количества отзывов о фильме по movie_id.
+	countMovieReviews = `SELECT COUNT(*) FROM reviews WHERE movie_id = $1;`
+	// SQL запрос для получения страницы отзывов пользователя, начиная с последних изменённых, по name, limit, offset.
+	getUserReviews = `SELECT r.id, r.movie_id, u.name AS nickname, r.rating, r.text, r.created_at, r.updated_at
+		FROM reviews r JOIN users u ON u.id = r.user_id
+		WHERE u.name = $1 ORDER BY r.updated_at DESC, r.id DESC LIMIT $2 OFFSET $3;`
+	// SQL запрос для получения количества отзывов пользователя по name.
+	countUserReviews = `SELECT COUNT(*) FROM reviews r JOIN users u ON u.id = r.user_id WHERE u.name = $1;`
+	// SQL запрос для получения страницы фильмов личного списка пользователя, начиная с последних просмотренных и добавленных,
+	// по name, list, limit, offset.
+	getListMovies = `SELECT ` + movieColumns + ` FROM user_movies um
+		JOIN movies m ON m.id = um.movie_id
+		` + movieVotes + `
+		WHERE um.user_id = (SELECT id FROM users WHERE name = $1) AND um.list = $2
+		ORDER BY um.watched_at DESC NULLS LAST, um.added_at DESC, m.id LIMIT $3 OFFSET $4;`
+	// SQL запрос для получения количества фильмов личного списка пользователя по name, list.
+	countListMovies = `SELECT COUNT(*) FROM user_movies WHERE user_id = (SELECT id FROM users WHERE name = $1) AND list = $2;`
+	// SQL запрос для получения положения фильмов в личных списках пользователя по name и массиву movie_id.
+	getMoviesStatuses = `SELECT movie_id, list, watched_at FROM user_movies
+		WHERE user_id = (SELECT id FROM users WHERE name = $1) AND movie_id = ANY($2);`
+	// SQL запрос для получения видимой пользователю коллекции по id, name пользователя и признаку модератора.
+	getCollection = `SELECT c.id, c.name, c.description, c.kind, c.public, u.name AS owner
+		FROM collections c JOIN users u ON u.id = c.owner_id
+		WHERE c.id = $1 AND (c.public OR u.name = $2 OR $3);`
+	// SQL запрос для получения страницы видимых пользователю коллекций по name пользователя, признаку модератора, limit, offset.
+	getCollections = `SELECT c.id, c.name, c.description, c.kind, c.public, u.name AS owner
+		FROM collections c JOIN users u ON u.id = c.owner_id
+		WHERE c.public OR u.name = $1 OR $2
+		ORDER BY c.name, c.id LIMIT $3 OFFSET $4;`
+	// SQL запрос для получения количества видимых пользователю коллекций по name пользователя и признаку модератора.
+	countCollections = `SELECT COUNT(*) FROM collections c JOIN users u ON u.id = c.owner_id WHERE c.public OR u.name = $1 OR $2;`
+	// SQL запрос для получения фильмов коллекции по порядку по collection_id.
+	getCollectionMovies = `SELECT movie_id FROM collection_movies WHERE collection_id = $1 ORDER BY position;`
+	// SQL запрос для получения фильмов коллекций по порядку по массиву collection_id.
+	getCollectionsMovies = `SELECT collection_id, movie_id FROM collection_movies WHERE collection_id = ANY($1) ORDER BY collection_id, position;`
+	// SQL запрос для получения сериала по id.
+	getSeries = `SELECT id, name, description, release_date, rating FROM series WHERE id = $1;`
+	// Начало SQL запроса для получения сериалов, дополняемое сортировкой, limit и offset.
+	selectSeries = `SELECT id, name, description, release_date, rating FROM series`
+	// SQL запрос для получения количества сериалов.
+	countSeries = `SELECT COUNT(*) FROM series;`
+	// SQL запрос для получения сезонов сериала по порядку по series_id.
+	getSeriesSeasons = `SELECT id, series_id, number, name FROM seasons WHERE series_id = $1 ORDER BY number;`
+	// SQL запрос для получения сезона по id.
+	getSeason = `SELECT id, series_id, number, name FROM seasons WHERE id = $1;`
+	// SQL запрос для получения серий сезонов по порядку по массиву season_id.
+	getSeasonsEpisodes = `SELECT id, season_id, number, name, description, release_date, rating FROM episodes
+		WHERE season_id = ANY($1) ORDER BY season_id, number;`
+	// SQL запрос для получения серии по id.
+	getEpisode = `SELECT id, season_id, number, name, description, release_date, rating FROM episodes WHERE id = $1;`
+	// SQL запрос для получения приглашённых актёров серий в порядке титров по массиву episode_id.
+	getEpisodesCast = `SELECT episode_id, actor_id, character_name, COALESCE(billing, 0) AS billing, cameo, voice
+		FROM episode_actors ea WHERE episode_id = ANY($1) ORDER BY episode_id, ea.billing NULLS LAST, actor_id;`
+	// SQL запрос для получения пользователя (вместе с хэшем пароля) по name.
+	getUserByName = `SELECT id, name AS nickname, password, is_admin FROM users WHERE name = $1;`
+	// SQL запрос для получения пользователя вместе с ролями по id.
+	getUser = `SELECT u.id, u.name AS nickname,
+		COALESCE(array_agg(ur.role ORDER BY ur.role) FILTER (WHERE ur.role IS NOT NULL), '{}') AS roles
+		FROM users u LEFT JOIN user_roles ur ON ur.user_id = u.id
+		WHERE u.id = $1 GROUP BY u.id;`
+	// SQL запрос для получения пользователей вместе с ролями.
+	getUsers = `SELECT u.id, u.name AS nickname,
+		COALESCE(array_agg(ur.role ORDER BY ur.role) FILTER (WHERE ur.role IS NOT NULL), '{}') AS roles
+		FROM users u LEFT JOIN user_roles ur ON ur.user_id = u.id
+		GROUP BY u.id ORDER BY u.id;`
+	// SQL запрос для получения прав пользователя по name.
+	getUserPermissions = `SELECT DISTINCT rp.permission FROM users u
+		JOIN user_roles ur ON ur.user_id = u.id
+		JOIN role_permissions rp ON rp.role = ur.role
+		WHERE u.name = $1 ORDER BY rp.permission;`
+	// SQL запрос для получения ролей вместе с их правами.
+	getRoles = `SELECT r.name,
+		COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}') AS permissions
+		FROM roles r LEFT JOIN role_permissions rp ON rp.role = r.name
+		GROUP BY r.name ORDER BY r.name;`
+	// SQL запрос для получения действующей сессии по id.
+	getSession = `SELECT * FROM sessions WHERE id = $1 AND expires_at > NOW();`
 )
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+)
+
+// AddReview - добавление отзыва пользователя о фильме в БД.
+func (d dbProcessor) AddReview(nickname string, movieId int, r models.ReviewIn) (int, error) {
+	id, err := d.addSmthWithId(addReview, fmt.Sprintf("error while inserting review of movie %d", movieId),
+		nickname, movieId, *r.Rating, r.Text)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		err = errors.Join(err, ErrUnknownUser)
+	case isForeignKeyViolation(err):
+		err = errors.Join(err, ErrUnknownMovie)
+	case isUniqueViolation(err):
+		err = errors.Join(err, ErrReviewExists)
+	}
+	return id, err
+}
+
+// UpdateReview - обновление отзыва пользователя в БД.
+func (d dbProcessor) UpdateReview(nickname string, id int, r models.ReviewIn) error {
+	if err := execAffected(d.db, ErrUnknownReview, updateReview, id, nickname, *r.Rating, r.Text); err != nil {
+		return errors.Join(fmt.Errorf("error while updating review %d", id), err)
+	}
+	return nil
+}
+
+// DeleteReview - удаление отзыва пользователя из БД.
+func (d dbProcessor) DeleteReview(nickname string, id int) error {
+	return d.deleteSmth(removeReview, fmt.Sprintf("error while deleting review %d", id), ErrUnknownReview, id, nickname)
+}
+
+// GetMovieReviews - получение страницы отзывов о фильме из БД.
+func (d dbProcessor) GetMovieReviews(movieId int, page models.Page) ([]models.ReviewOut, int, error) {
+	wrapErr := fmt.Errorf("error while getting reviews of movie %d", movieId)
+	reviews := []models.ReviewOut{}
+	if err := d.db.Select(&reviews, getMovieReviews, movieId, page.Limit, page.Offset); err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+
+	if len(reviews) == 0 && page.Offset == 0 {
+		var exists bool
+		if err := d.db.Get(&exists, movieExists, movieId); err != nil {
+			return nil, 0, errors.Join(wrapErr, err)
+		}
+		if !exists {
+			return nil, 0, errors.Join(wrapErr, ErrUnknownMovie)
+		}
+	}
+	total, err := countSmth(d.db, page, len(reviews), countMovieReviews, movieId)
+	if err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	return reviews, total, nil
+}
+
+// GetUserReviews - получение страницы отзывов пользователя из БД.
+func (d dbProcessor) GetUserReviews(nickname string, page models.Page) ([]models.ReviewOut, int, error) {
+	wrapErr := fmt.Errorf("error while getting reviews of user %s", nickname)
+	reviews := []models.ReviewOut{}
+	if err := d.db.Select(&reviews, getUserReviews, nickname, page.Limit, page.Offset); err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+
+	total, err := countSmth(d.db, page, len(reviews), countUserReviews, nickname)
+	if err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	return reviews, total, nil
+}
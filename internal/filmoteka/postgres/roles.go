@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// MigrateRoles - создаёт таблицы ролей и назначает роли существующим пользователям.
+//
+// Выполняется при каждом запуске. Роли назначаются, только если таблицы ролей пользователей ещё нет:
+// пользователи с is_admin получают роль admin, остальные - viewer. Иначе пользователи без ролей
+// получали бы их заново при каждом перезапуске.
+//
+// Принимает: подключение к базе данных.
+//
+// Возвращает: ошибку.
+func MigrateRoles(db *sql.DB) error {
+	var exists bool
+	if err := db.QueryRow(userRolesExist).Scan(&exists); err != nil {
+		return errors.Join(fmt.Errorf("error while migrating roles: %s", err))
+	}
+	queries := []string{createRoles, createRolePermissions, createUserRoles, seedRoles, seedRolePermissions}
+	if !exists {
+		queries = append(queries, migrateUserRoles)
+	}
+	if _, err := db.Exec(strings.Join(queries, " ")); err != nil {
+		return errors.Join(fmt.Errorf("error while migrating roles: %s", err))
+	}
+	return nil
+}
+
+// MigrateUsers - добавляет уникальный индекс имён пользователей, если таблица была создана без него.
+//
+// Запрос идемпотентен и выполняется при каждом запуске; при повторяющихся именах возвращается ошибка.
+//
+// Принимает: подключение к базе данных.
+//
+// Возвращает: ошибку.
+func MigrateUsers(db *sql.DB) error {
+	if _, err := db.Exec(migrateUsersName); err != nil {
+		return errors.Join(fmt.Errorf("error while migrating users: %s", err))
+	}
+	return nil
+}
+
+// userRow - строка пользователя вместе с ролями.
+type userRow struct {
+	Id       int            `db:"id"`
+	Nickname string         `db:"nickname"`
+	Roles    pq.StringArray `db:"roles"`
+}
+
+// toUser - преобразование строки в отправляемого пользователя.
+func (r userRow) toUser() models.UserOut {
+	return models.UserOut{Id: r.Id, Nickname: r.Nickname, Roles: []string(r.Roles)}
+}
+
+// roleRow - строка роли вместе с правами.
+type roleRow struct {
+	Name        string         `db:"name"`
+	Permissions pq.StringArray `db:"permissions"`
+}
+
+// GetRoles - получение ролей из БД.
+func (d dbProcessor) GetRoles() ([]models.Role, error) {
+	var rows []roleRow
+	if err := d.db.Select(&rows, getRoles); err != nil {
+		return nil, errors.Join(errors.New("error while getting roles"), err)
+	}
+	roles := make([]models.Role, len(rows))
+	for i, r := range rows {
+		roles[i] = models.Role{Name: r.Name, Permissions: []string(r.Permissions)}
+	}
+	return roles, nil
+}
+
+// GetUserPermissions - получение прав пользователя из БД.
+func (d dbProcessor) GetUserPermissions(name string) ([]string, error) {
+	permissions := []string{}
+	if err := d.db.Select(&permissions, getUserPermissions, name); err != nil {
+		return nil, errors.Join(fmt.Errorf("error while getting permissions of user %s", name), err)
+	}
+	return permissions, nil
+}
+
+// setUserRoles - назначение ролей пользователю.
+func (d dbProcessor) setUserRoles(tx *sqlx.Tx, userId int, roles []string) error {
+	for _, role := range roles {
+		if _, err := tx.Exec(addUserRole, userId, role); err != nil {
+			if isForeignKeyViolation(err) {
+				err = errors.Join(err, ErrUnknownRole)
+			}
+			return errors.Join(fmt.Errorf("error while adding role %s to user %d", role, userId), err)
+		}
+	}
+	return nil
+}
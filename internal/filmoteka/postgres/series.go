@@ -0,0 +1,303 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// AddSeries - добавление сериала в БД.
+func (d dbProcessor) AddSeries(s models.SeriesIn) (int, error) {
+	return d.addSmthWithId(addSeries, "error while inserting series", s.Name, s.Description, s.ReleaseDate, *s.Rating)
+}
+
+// UpdateSeries - обновление сериала в БД.
+func (d dbProcessor) UpdateSeries(id int, s models.SeriesIn) error {
+	if err := execAffected(d.db, ErrUnknownSeries, updateSeries, id, s.Name, s.Description, nullDate(s.ReleaseDate), s.Rating); err != nil {
+		return errors.Join(fmt.Errorf("error while updating series %d", id), err)
+	}
+	return nil
+}
+
+// DeleteSeries - удаление сериала вместе с сезонами и сериями из БД.
+func (d dbProcessor) DeleteSeries(id int) error {
+	return d.deleteSmth(removeSeries, fmt.Sprintf("error while deleting series %d", id), ErrUnknownSeries, id)
+}
+
+// GetSeries - получение сериала вместе с сезонами и сериями из БД.
+func (d dbProcessor) GetSeries(id int) (models.SeriesOut, error) {
+	wrapErr := fmt.Errorf("error while getting series %d", id)
+	var series models.SeriesOut
+	if err := d.db.Get(&series, getSeries, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.Join(err, ErrUnknownSeries)
+		}
+		return models.SeriesOut{}, errors.Join(wrapErr, err)
+	}
+
+	series.Seasons = []models.SeasonOut{}
+	if err := d.db.Select(&series.Seasons, getSeriesSeasons, id); err != nil {
+		return models.SeriesOut{}, errors.Join(wrapErr, errors.New("error while getting series' seasons"), err)
+	}
+	if err := d.fillSeasons(series.Seasons); err != nil {
+		return models.SeriesOut{}, errors.Join(wrapErr, err)
+	}
+	return series, nil
+}
+
+// GetSeriesList - получение отсортированной страницы сериалов из БД.
+func (d dbProcessor) GetSeriesList(sort models.Sort, page models.Page) ([]models.SeriesOut, int, error) {
+	wrapErr := errors.New("error while getting series")
+	order, err := orderBy(sort, seriesSortColumns)
+	if err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	query, args := (&queryBuilder{}).build(selectSeries, order+" LIMIT ? OFFSET ?", page.Limit, page.Offset)
+	series := []models.SeriesOut{}
+	if err := d.db.Select(&series, query, args...); err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	total, err := countSmth(d.db, page, len(series), countSeries)
+	if err != nil {
+		return nil, 0, errors.Join(wrapErr, err)
+	}
+	return series, total, nil
+}
+
+// AddSeason - добавление сезона в сериал в БД.
+func (d dbProcessor) AddSeason(seriesId int, s models.SeasonIn) (int, error) {
+	var id int
+	if err := d.db.QueryRow(addSeason, seriesId, s.Number, s.Name).Scan(&id); err != nil {
+		return 0, errors.Join(fmt.Errorf("error while inserting season to series %d", seriesId), seasonError(err))
+	}
+	return id, nil
+}
+
+// UpdateSeason - обновление сезона в БД.
+func (d dbProcessor) UpdateSeason(id int, s models.SeasonIn) error {
+	if err := execAffected(d.db, ErrUnknownSeason, updateSeason, id, s.Number, s.Name); err != nil {
+		return errors.Join(fmt.Errorf("error while updating season %d", id), seasonError(err))
+	}
+	return nil
+}
+
+// DeleteSeason - удаление сезона вместе с сериями из БД.
+func (d dbProcessor) DeleteSeason(id int) error {
+	return d.deleteSmth(removeSeason, fmt.Sprintf("error while deleting season %d", id), ErrUnknownSeason, id)
+}
+
+// GetSeason - получение сезона вместе с сериями из БД.
+func (d dbProcessor) GetSeason(id int) (models.SeasonOut, error) {
+	wrapErr := fmt.Errorf("error while getting season %d", id)
+	var season models.SeasonOut
+	if err := d.db.Get(&season, getSeason, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.Join(err, ErrUnknownSeason)
+		}
+		return models.SeasonOut{}, errors.Join(wrapErr, err)
+	}
+
+	seasons := []models.SeasonOut{season}
+	if err := d.fillSeasons(seasons); err != nil {
+		return models.SeasonOut{}, errors.Join(wrapErr, err)
+	}
+	return seasons[0], nil
+}
+
+// AddEpisode - добавление серии вместе с приглашёнными актёрами в сезон в БД.
+func (d dbProcessor) AddEpisode(seasonId int, e models.EpisodeIn) (int, error) {
+	wrapErr := fmt.Errorf("error while inserting episode to season %d", seasonId)
+	tx, err := d.db.Beginx()
+	if err != nil {
+		return 0, errors.Join(wrapErr, errBeginTx, err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	if err = tx.QueryRow(addEpisode, seasonId, e.Number, e.Name, e.Description, e.ReleaseDate, *e.Rating).Scan(&id); err != nil {
+		return 0, errors.Join(wrapErr, episodeError(err))
+	}
+	for _, c := range e.CastList() {
+		if err = d.addCastToEpisode(tx, c, id); err != nil {
+			return 0, errors.Join(wrapErr, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, errors.Join(wrapErr, errCommitTx, err)
+	}
+	return id, nil
+}
+
+// UpdateEpisode - обновление серии в БД.
+//
+// Приглашённые актёры заменяются, только если они переданы.
+func (d dbProcessor) UpdateEpisode(id int, e models.EpisodeIn) error {
+	wrapErr := fmt.Errorf("error while updating episode %d", id)
+	tx, err := d.db.Beginx()
+	if err != nil {
+		return errors.Join(wrapErr, errBeginTx, err)
+	}
+	defer tx.Rollback()
+
+	if err = execAffected(tx, ErrUnknownEpisode, updateEpisode, id, e.Number, e.Name, e.Description,
+		nullDate(e.ReleaseDate), e.Rating); err != nil {
+		return errors.Join(wrapErr, episodeError(err))
+	}
+	if e.Cast != nil {
+		if _, err = tx.Exec(removeEpisodeCast, id); err != nil {
+			return errors.Join(wrapErr, err)
+		}
+		for _, c := range e.CastList() {
+			if err = d.addCastToEpisode(tx, c, id); err != nil {
+				return errors.Join(wrapErr, err)
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Join(wrapErr, errCommitTx, err)
+	}
+	return nil
+}
+
+// DeleteEpisode - удаление серии из БД.
+func (d dbProcessor) DeleteEpisode(id int) error {
+	return d.deleteSmth(removeEpisode, fmt.Sprintf("error while deleting episode %d", id), ErrUnknownEpisode, id)
+}
+
+// GetEpisode - получение серии вместе с приглашёнными актёрами из БД.
+func (d dbProcessor) GetEpisode(id int) (models.EpisodeOut, error) {
+	wrapErr := fmt.Errorf("error while getting episode %d", id)
+	var episode models.EpisodeOut
+	if err := d.db.Get(&episode, getEpisode, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = errors.Join(err, ErrUnknownEpisode)
+		}
+		return models.EpisodeOut{}, errors.Join(wrapErr, err)
+	}
+
+	episodes := []models.EpisodeOut{episode}
+	if err := d.fillEpisodes(episodes); err != nil {
+		return models.EpisodeOut{}, errors.Join(wrapErr, err)
+	}
+	return episodes[0], nil
+}
+
+// episodeActor - строка роли приглашённого актёра в серии.
+type episodeActor struct {
+	EpisodeId int `db:"episode_id"`
+	models.CastMember
+}
+
+// fillSeasons - заполнение сезонов сериями вместе с приглашёнными актёрами.
+//
+// Серии всех сезонов загружаются одним запросом, независимо от количества сезонов.
+func (d dbProcessor) fillSeasons(seasons []models.SeasonOut) error {
+	if len(seasons) == 0 {
+		return nil
+	}
+	ids := make([]int, len(seasons))
+	index := make(map[int]int, len(seasons))
+	for i := range seasons {
+		ids[i] = seasons[i].Id
+		index[seasons[i].Id] = i
+		seasons[i].Episodes = []models.EpisodeOut{}
+	}
+
+	var episodes []models.EpisodeOut
+	if err := d.db.Select(&episodes, getSeasonsEpisodes, pq.Array(ids)); err != nil {
+		return errors.Join(errors.New("error while getting seasons' episodes"), err)
+	}
+	if err := d.fillEpisodes(episodes); err != nil {
+		return err
+	}
+	for _, e := range episodes {
+		if i, ok := index[e.SeasonId]; ok {
+			seasons[i].Episodes = append(seasons[i].Episodes, e)
+		}
+	}
+	return nil
+}
+
+// fillEpisodes - заполнение серий приглашёнными актёрами.
+//
+// Актёры всех серий загружаются одним запросом, независимо от количества серий.
+func (d dbProcessor) fillEpisodes(episodes []models.EpisodeOut) error {
+	if len(episodes) == 0 {
+		return nil
+	}
+	ids := make([]int, len(episodes))
+	index := make(map[int]int, len(episodes))
+	for i := range episodes {
+		ids[i] = episodes[i].Id
+		index[episodes[i].Id] = i
+		episodes[i].Cast = []models.CastMember{}
+	}
+
+	var cast []episodeActor
+	if err := d.db.Select(&cast, getEpisodesCast, pq.Array(ids)); err != nil {
+		return errors.Join(errors.New("error while getting episodes' cast"), err)
+	}
+	for _, c := range cast {
+		if i, ok := index[c.EpisodeId]; ok {
+			episodes[i].Cast = append(episodes[i].Cast, c.CastMember)
+		}
+	}
+	return nil
+}
+
+// addCastToEpisode - добавление приглашённого актёра в серию.
+func (d dbProcessor) addCastToEpisode(tx *sqlx.Tx, c models.CastMember, episodeId int) error {
+	_, err := tx.Exec(addCastToEpisode, episodeId, c.ActorId, c.Character, c.Billing, c.Cameo, c.Voice)
+	if err != nil {
+		switch {
+		case isForeignKeyViolation(err) && strings.Contains(violatedConstraint(err), "episode_id"):
+			err = errors.Join(err, ErrUnknownEpisode)
+		case isForeignKeyViolation(err):
+			err = errors.Join(err, newKindError(ErrForeignKey, fmt.Sprintf("actor %d does not exist", c.ActorId)))
+		default:
+			err = classify(err)
+		}
+		return errors.Join(fmt.Errorf("error while adding actor %d to episode %d", c.ActorId, episodeId), err)
+	}
+	return nil
+}
+
+// seasonError - добавление к ошибке добавления или изменения сезона её причины.
+func seasonError(err error) error {
+	switch {
+	case isUniqueViolation(err):
+		return errors.Join(err, ErrSeasonExists)
+	case isForeignKeyViolation(err):
+		return errors.Join(err, ErrUnknownSeries)
+	default:
+		return err
+	}
+}
+
+// episodeError - добавление к ошибке добавления или изменения серии её причины.
+func episodeError(err error) error {
+	switch {
+	case isUniqueViolation(err):
+		return errors.Join(err, ErrEpisodeExists)
+	case isForeignKeyViolation(err):
+		return errors.Join(err, ErrUnknownSeason)
+	default:
+		return err
+	}
+}
+
+// nullDate - получение аргумента запроса для даты, пустая дата передаётся как NULL.
+func nullDate(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
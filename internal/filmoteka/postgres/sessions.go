@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+)
+
+// AddSession - добавление сессии в БД.
+//
+// Вместе с добавлением удаляет истёкшие сессии.
+func (d dbProcessor) AddSession(s models.Session) error {
+	wrapErr := errors.New("error while inserting session")
+	tx, err := d.db.Begin()
+	if err != nil {
+		return errors.Join(wrapErr, errBeginTx, err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec(removeExpiredSessions); err != nil {
+		return errors.Join(wrapErr, err)
+	}
+	if _, err = tx.Exec(addSession, s.Id, s.Nickname, s.Superuser, s.ExpiresAt); err != nil {
+		return errors.Join(wrapErr, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Join(wrapErr, errCommitTx, err)
+	}
+	return nil
+}
+
+// GetSession - получение действующей сессии из БД.
+func (d dbProcessor) GetSession(id string) (models.Session, error) {
+	var s models.Session
+	if err := d.db.Get(&s, getSession, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrUnknownSession
+		}
+		return models.Session{}, errors.Join(errors.New("error while getting session"), err)
+	}
+	return s, nil
+}
+
+// DeleteSession - удаление сессии из БД.
+func (d dbProcessor) DeleteSession(id string) error {
+	return d.deleteSmth(removeSession, "error while deleting session", ErrUnknownSession, id)
+}
@@ -0,0 +1,92 @@
+package filmoteka
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+)
+
+// requestIdHeader - заголовок с id запроса.
+const requestIdHeader = "X-Request-Id"
+
+// withRequestId - обёртка обработчика, присваивающая запросу id.
+//
+// Id берётся из заголовка X-Request-Id, а если его нет - генерируется. Id возвращается в том же заголовке ответа.
+func (app *App) withRequestId(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIdHeader)
+		if id == "" || len(id) > 128 {
+			id = newRequestId()
+		}
+		w.Header().Set(requestIdHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIdKey, id)))
+	})
+}
+
+// newRequestId - генерация случайного id запроса.
+func newRequestId() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// requestIdFrom - получение id запроса из контекста запроса.
+func requestIdFrom(r *http.Request) string {
+	id, _ := r.Context().Value(requestIdKey).(string)
+	return id
+}
+
+// handleError - обработка ошибок.
+//
+// Принимает: ResponseWriter, запрос, ошибку и http-статус.
+//
+// Полная ошибка пишется в лог ошибок, а клиенту отправляется описание проблемы (RFC 7807).
+// Для статусов 5xx подробности клиенту не отправляются.
+func (app *App) handleError(w http.ResponseWriter, r *http.Request, err error, status int) {
+	reqId := requestIdFrom(r)
+	app.errorLog.Printf("request %s %s %s: %d: %v\n", reqId, r.Method, r.URL.Path, status, err)
+
+	p := models.Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Instance:  r.URL.Path,
+		RequestId: reqId,
+	}
+	if status < http.StatusInternalServerError {
+		p.Detail, p.Errors = problemDetail(err)
+	}
+
+	writeProblem(w, p)
+}
+
+// problemDetail - получение описания ошибки, которое можно показать клиенту.
+//
+// Возвращает: описание и ошибки валидации полей.
+func problemDetail(err error) (string, []models.FieldError) {
+	if fields := models.FieldErrors(err); len(fields) != 0 {
+		return "request validation failed", fields
+	}
+	if public, ok := postgres.PublicError(err); ok {
+		return public.Error(), nil
+	}
+	return err.Error(), nil
+}
+
+// writeProblem - отправка описания проблемы.
+func writeProblem(w http.ResponseWriter, p models.Problem) {
+	js, err := json.Marshal(p)
+	if err != nil {
+		http.Error(w, http.StatusText(p.Status), p.Status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(js)
+}
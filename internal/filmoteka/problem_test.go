@@ -0,0 +1,98 @@
+package filmoteka
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleError(t *testing.T) {
+	handle := func(err error, status int) (*httptest.ResponseRecorder, models.Problem, string) {
+		var buf bytes.Buffer
+		logger := log.New(&buf, "", log.LstdFlags)
+		app := CreateApp(":8080", logger, logger, nil, false, nil, RegistrationPolicy{}, nil)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/movie/1", nil)
+		r.Header.Set(requestIdHeader, "req-1")
+		app.withRequestId(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			app.handleError(w, r, err, status)
+		})).ServeHTTP(w, r)
+
+		var p models.Problem
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+		return w, p, buf.String()
+	}
+
+	t.Run("client error", func(t *testing.T) {
+		w, p, logged := handle(errors.New("id must be an integer"), http.StatusBadRequest)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		assert.Equal(t, models.Problem{
+			Type:      "about:blank",
+			Title:     "Bad Request",
+			Status:    http.StatusBadRequest,
+			Detail:    "id must be an integer",
+			Instance:  "/movie/1",
+			RequestId: "req-1",
+		}, p)
+		assert.Contains(t, logged, "req-1")
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		err := errors.Join(errors.New("error while getting movie 1"), errors.New("sql: no rows in result set"), postgres.ErrUnknownMovie)
+		_, p, logged := handle(err, http.StatusNotFound)
+		assert.Equal(t, "unknown movie", p.Detail)
+		assert.Contains(t, logged, "sql: no rows in result set")
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		actor := models.ActorIn{Name: "name"}
+		_, p, _ := handle(actor.Check(), http.StatusBadRequest)
+		assert.Equal(t, "request validation failed", p.Detail)
+		assert.Equal(t, []models.FieldError{
+			{Field: "gender", Message: "gender must not be null"},
+			{Field: "date_of_birth", Message: "date of birth must not be null"},
+		}, p.Errors)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		_, p, logged := handle(errors.New("pq: password authentication failed"), http.StatusInternalServerError)
+		assert.Equal(t, "Internal Server Error", p.Title)
+		assert.Empty(t, p.Detail)
+		assert.Contains(t, logged, "pq: password authentication failed")
+	})
+}
+
+func TestWithRequestId(t *testing.T) {
+	logger := log.New(io.Discard, "", log.LstdFlags)
+	app := CreateApp(":8080", logger, logger, nil, false, nil, RegistrationPolicy{}, nil)
+	var got string
+	handler := app.withRequestId(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = requestIdFrom(r)
+	}))
+
+	t.Run("generated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Len(t, got, 16)
+		assert.Equal(t, got, w.Header().Get(requestIdHeader))
+	})
+
+	t.Run("forwarded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(requestIdHeader, "req-1")
+		handler.ServeHTTP(w, r)
+		assert.Equal(t, "req-1", got)
+		assert.Equal(t, "req-1", w.Header().Get(requestIdHeader))
+	})
+}
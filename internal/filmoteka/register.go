@@ -0,0 +1,129 @@
+package filmoteka
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// RegistrationMode - режим самостоятельной регистрации пользователей.
+type RegistrationMode string
+
+// Режимы самостоятельной регистрации пользователей.
+const (
+	// RegistrationDisabled - регистрация отключена, пользователей создаёт только администратор.
+	RegistrationDisabled RegistrationMode = "disabled"
+	// RegistrationOpen - зарегистрироваться может любой.
+	RegistrationOpen RegistrationMode = "open"
+	// RegistrationInvite - зарегистрироваться можно только с кодом приглашения.
+	RegistrationInvite RegistrationMode = "invite"
+)
+
+// RegistrationPolicy - настройки самостоятельной регистрации пользователей.
+type RegistrationPolicy struct {
+	Mode       RegistrationMode // Mode - режим регистрации.
+	InviteCode string           // InviteCode - код приглашения для режима RegistrationInvite.
+}
+
+// ParseRegistrationPolicy - создание настроек самостоятельной регистрации.
+//
+// Принимает: название режима и код приглашения.
+//
+// Возвращает: настройки и ошибку.
+func ParseRegistrationPolicy(mode, inviteCode string) (RegistrationPolicy, error) {
+	p := RegistrationPolicy{Mode: RegistrationMode(mode), InviteCode: inviteCode}
+	switch p.Mode {
+	case RegistrationDisabled, RegistrationOpen:
+	case RegistrationInvite:
+		if inviteCode == "" {
+			return RegistrationPolicy{}, errors.New("invite code must be set for invite registration")
+		}
+	default:
+		return RegistrationPolicy{}, fmt.Errorf("unknown registration mode %q", mode)
+	}
+	return p, nil
+}
+
+// errWrongInvite - ошибка неверного кода приглашения.
+var errWrongInvite = errors.New("wrong invite code")
+
+// allows - проверка того, что регистрация разрешена.
+//
+// Принимает: код приглашения из запроса.
+//
+// Возвращает: ошибку.
+func (p RegistrationPolicy) allows(inviteCode string) error {
+	switch p.Mode {
+	case RegistrationOpen:
+		return nil
+	case RegistrationInvite:
+		if subtle.ConstantTimeCompare([]byte(inviteCode), []byte(p.InviteCode)) != 1 {
+			return errWrongInvite
+		}
+		return nil
+	default:
+		return errors.New("registration is disabled")
+	}
+}
+
+// Register - обрабатывает http запрос на самостоятельную регистрацию пользователя.
+//
+// @Summary      Registers a new user.
+// @Description  Create a viewer account and get it's ID. Available if registration is open or by invite code.
+// @Description  Nickname must be 3-32 latin letters, digits, '_', '-' or '.'; password must be 8-72 bytes with a letter and a digit.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        user body models.Registration true "Registration data"
+// @Success      200 {object} int
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      403 {object} models.Problem "Wrong invite code"
+// @Failure      404 {object} models.Problem "Registration is disabled"
+// @Failure      409 {object} models.Problem "Nickname is taken"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /auth/register [post]
+func (app *App) Register(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to register a new user")
+	var reg models.Registration
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&reg); err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.registration.allows(reg.InviteCode); err != nil {
+		status := http.StatusNotFound
+		if errors.Is(err, errWrongInvite) {
+			status = http.StatusForbidden
+		}
+		app.handleError(w, r, err, status)
+		return
+	}
+
+	user := reg.User()
+	if err := user.Check(); err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), 8)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+	user.Password = string(hashedPassword)
+
+	id, err := app.dbHandler.AddUser(user)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendJson(w, r, id)
+	app.infoLog.Printf("user %d is registered\n", id)
+}
@@ -0,0 +1,107 @@
+package filmoteka
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRegistrationPolicy(t *testing.T) {
+	t.Run("open", func(t *testing.T) {
+		p, err := ParseRegistrationPolicy("open", "")
+		assert.NoError(t, err)
+		assert.Equal(t, RegistrationOpen, p.Mode)
+	})
+
+	t.Run("invite", func(t *testing.T) {
+		p, err := ParseRegistrationPolicy("invite", "code")
+		assert.NoError(t, err)
+		assert.Equal(t, RegistrationPolicy{Mode: RegistrationInvite, InviteCode: "code"}, p)
+	})
+
+	t.Run("invite without code", func(t *testing.T) {
+		_, err := ParseRegistrationPolicy("invite", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown mode", func(t *testing.T) {
+		_, err := ParseRegistrationPolicy("closed", "")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown registration mode")
+	})
+}
+
+func TestRegister(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+	dbHandler, _ := postgres.GetHandler(mockDB, false)
+
+	register := func(policy RegistrationPolicy, body string) *httptest.ResponseRecorder {
+		app := CreateApp(":8080", logger, logger, dbHandler, false, nil, policy, nil)
+		w := httptest.NewRecorder()
+		app.Register(w, httptest.NewRequest(http.MethodPost, "/auth/register", strings.NewReader(body)))
+		return w
+	}
+	open := RegistrationPolicy{Mode: RegistrationOpen}
+	invite := RegistrationPolicy{Mode: RegistrationInvite, InviteCode: "code"}
+
+	t.Run("open", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO users").WithArgs("JohnDoe", sqlmock.AnyArg(), false).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+		mock.ExpectExec("INSERT INTO user_roles").WithArgs(7, models.RoleViewer).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		w := register(open, `{"nickname": "JohnDoe", "password": "passw0rd"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "7", strings.TrimSpace(w.Body.String()))
+	})
+
+	t.Run("invite", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO users").WithArgs("JohnDoe", sqlmock.AnyArg(), false).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+		mock.ExpectExec("INSERT INTO user_roles").WithArgs(8, models.RoleViewer).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		w := register(invite, `{"nickname": "JohnDoe", "password": "passw0rd", "invite_code": "code"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("wrong invite code", func(t *testing.T) {
+		w := register(invite, `{"nickname": "JohnDoe", "password": "passw0rd", "invite_code": "other"}`)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		w := register(RegistrationPolicy{Mode: RegistrationDisabled}, `{"nickname": "JohnDoe", "password": "passw0rd"}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("weak password", func(t *testing.T) {
+		w := register(open, `{"nickname": "JohnDoe", "password": "password"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("roles are ignored", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO users").WithArgs("JohnDoe", sqlmock.AnyArg(), false).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+		mock.ExpectExec("INSERT INTO user_roles").WithArgs(9, models.RoleViewer).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		w := register(open, `{"nickname": "JohnDoe", "password": "passw0rd", "roles": ["admin"], "is_admin": true}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
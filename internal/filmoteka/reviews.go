@@ -0,0 +1,221 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+)
+
+// AddReview - обрабатывает http запрос на добавление оценки и отзыва текущего пользователя о фильме.
+//
+// @Summary      Rates and reviews movie.
+// @Description  Add rating and optional text review of the movie by the authenticated user and get it's ID. A user can review a movie only once, use PUT /reviews/{id} to change the review.
+// @Tags         Review
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID of the movie to be reviewed"
+// @Param        review body models.ReviewIn true "Rating and review"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {integer} int "ID of the added review"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Built-in admin has no personal data"
+// @Failure      404 {object} models.Problem "Movie or user not found"
+// @Failure      409 {object} models.Problem "Movie is already reviewed by user"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /movie/{id}/reviews [post]
+func (app *App) AddReview(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to add a new review")
+	user := principalFrom(r)
+
+	var review models.ReviewIn
+	d := json.NewDecoder(r.Body)
+	err := d.Decode(&review)
+	if err == nil {
+		err = review.Check()
+	}
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	movieId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	id, err := app.dbHandler.AddReview(user.nickname, movieId, review)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendJson(w, r, id)
+	app.infoLog.Printf("review %d of movie %d is added by user %s\n", id, movieId, user.nickname)
+}
+
+// UpdateReview - обрабатывает http запрос на изменение отзыва текущего пользователя.
+//
+// @Summary      Updates review.
+// @Description  Replace rating and text of the review. Users can update only their own reviews.
+// @Tags         Review
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID of the review to be updated"
+// @Param        review body models.ReviewIn true "Rating and review"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Built-in admin has no personal data"
+// @Failure      404 {object} models.Problem "Review not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /reviews/{id} [put]
+func (app *App) UpdateReview(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to update a review")
+	user := principalFrom(r)
+
+	var review models.ReviewIn
+	d := json.NewDecoder(r.Body)
+	err := d.Decode(&review)
+	if err == nil {
+		err = review.Check()
+	}
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.dbHandler.UpdateReview(user.nickname, id, review); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("review %d is updated\n", id)
+}
+
+// DeleteReview - обрабатывает http запрос на удаление отзыва текущего пользователя.
+//
+// @Summary      Deletes review.
+// @Description  Delete the review together with its rating. Users can delete only their own reviews.
+// @Tags         Review
+// @Produce      json
+// @Param        id path int true "ID of the review to be deleted"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Built-in admin has no personal data"
+// @Failure      404 {object} models.Problem "Review not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /reviews/{id} [delete]
+func (app *App) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to delete a review")
+	user := principalFrom(r)
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.dbHandler.DeleteReview(user.nickname, id); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("review %d is deleted\n", id)
+}
+
+// GetMovieReviews - обрабатывает http запрос на получение отзывов о фильме.
+//
+// @Summary      Get reviews of movie.
+// @Description  Get reviews of the movie, most recently updated first. User should have the catalog:read permission.
+// @Tags         Review
+// @Produce      json
+// @Param        id path int true "ID of the movie"
+// @Param        limit query int false "Page size, 1 - 500" default(50)
+// @Param        offset query int false "Number of items to skip" default(0)
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {array} models.ReviewOut
+// @Header       200 {integer} X-Total-Count "Total number of items"
+// @Header       200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Movie not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /movie/{id}/reviews [get]
+func (app *App) GetMovieReviews(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get reviews of a movie")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	reviews, total, err := app.dbHandler.GetMovieReviews(id, page)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendPage(w, r, page, total, reviews)
+	app.infoLog.Printf("reviews of movie %d are getted\n", id)
+}
+
+// GetMyReviews - обрабатывает http запрос на получение отзывов текущего пользователя.
+//
+// @Summary      Get reviews of the current user.
+// @Description  Get reviews of the authenticated user, most recently updated first.
+// @Tags         Review
+// @Produce      json
+// @Param        limit query int false "Page size, 1 - 500" default(50)
+// @Param        offset query int false "Number of items to skip" default(0)
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {array} models.ReviewOut
+// @Header       200 {integer} X-Total-Count "Total number of items"
+// @Header       200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Built-in admin has no personal data"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /users/me/reviews [get]
+func (app *App) GetMyReviews(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get reviews of the current user")
+	user := principalFrom(r)
+	page, err := parsePage(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	reviews, total, err := app.dbHandler.GetUserReviews(user.nickname, page)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendPage(w, r, page, total, reviews)
+	app.infoLog.Printf("reviews of user %s are getted\n", user.nickname)
+}
@@ -0,0 +1,126 @@
+package filmoteka
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviews(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+	dbHandler, _ := postgres.GetHandler(mockDB, false)
+	app := CreateApp(":8080", logger, logger, dbHandler, false, nil, RegistrationPolicy{}, nil)
+
+	asUser := func(r *http.Request) *http.Request {
+		return r.WithContext(context.WithValue(r.Context(), principalKey, principal{nickname: "user"}))
+	}
+	addReview := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := asUser(httptest.NewRequest(http.MethodPost, "/movie/15/reviews", strings.NewReader(body)))
+		r.SetPathValue("id", "15")
+		app.AddReview(w, r)
+		return w
+	}
+
+	t.Run("add review", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO reviews").WithArgs("user", 15, 8, "Отличный фильм").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+		mock.ExpectCommit()
+
+		w := addReview(`{"rating": 8, "text": "Отличный фильм"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "4", w.Body.String())
+	})
+
+	t.Run("add second review", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO reviews").WithArgs("user", 15, 8, "").WillReturnError(&pq.Error{Code: "23505"})
+		mock.ExpectRollback()
+
+		w := addReview(`{"rating": 8}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "movie is already reviewed by user")
+	})
+
+	t.Run("add review of unknown movie", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO reviews").WithArgs("user", 15, 8, "").WillReturnError(&pq.Error{Code: "23503"})
+		mock.ExpectRollback()
+
+		w := addReview(`{"rating": 8}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "unknown movie")
+	})
+
+	t.Run("add invalid review", func(t *testing.T) {
+		w := addReview(`{"rating": 11}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"rating"`)
+	})
+
+	t.Run("update review of another user", func(t *testing.T) {
+		mock.ExpectExec("UPDATE reviews").WithArgs(4, "user", 5, "").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		w := httptest.NewRecorder()
+		r := asUser(httptest.NewRequest(http.MethodPut, "/reviews/4", strings.NewReader(`{"rating": 5}`)))
+		r.SetPathValue("id", "4")
+		app.UpdateReview(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "unknown review")
+	})
+
+	t.Run("delete review", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM reviews").WithArgs(4, "user").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		w := httptest.NewRecorder()
+		r := asUser(httptest.NewRequest(http.MethodDelete, "/reviews/4", nil))
+		r.SetPathValue("id", "4")
+		app.DeleteReview(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("get my reviews", func(t *testing.T) {
+		created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		mock.ExpectQuery("WHERE u.name = \\$1").WithArgs("user", 50, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "movie_id", "nickname", "rating", "text", "created_at", "updated_at"}).
+				AddRow(4, 15, "user", 8, "Отличный фильм", created, created))
+
+		w := httptest.NewRecorder()
+		app.GetMyReviews(w, asUser(httptest.NewRequest(http.MethodGet, "/users/me/reviews", nil)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", w.Header().Get("X-Total-Count"))
+		assert.JSONEq(t, `[{"id": 4, "movie_id": 15, "nickname": "user", "rating": 8, "text": "Отличный фильм",
+			"created_at": "2024-03-01T12:00:00Z", "updated_at": "2024-03-01T12:00:00Z"}]`, w.Body.String())
+	})
+
+	t.Run("get reviews of unknown movie", func(t *testing.T) {
+		mock.ExpectQuery("WHERE r.movie_id = \\$1").WithArgs(7, 50, 0).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "movie_id", "nickname", "rating", "text", "created_at", "updated_at"}))
+		mock.ExpectQuery("SELECT EXISTS").WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/movie/7/reviews", nil)
+		r.SetPathValue("id", "7")
+		app.GetMovieReviews(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
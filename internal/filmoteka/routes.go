@@ -3,30 +3,91 @@ package filmoteka
 import (
 	"net/http"
 
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 )
 
 // routes - создание маршрутов.
+//
+// Каждый маршрут, кроме документации, файлов изображений и входа, оборачивается проверкой прав пользователя,
+// а каждому запросу присваивается id, который возвращается в заголовке X-Request-Id и в описаниях ошибок.
 func (app *App) routes() http.Handler {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("GET /swagger/", httpSwagger.WrapHandler)
+	mux.HandleFunc("GET /media/{key...}", app.GetMedia)
 
-	mux.HandleFunc("POST /actor", app.AddActor)
-	mux.HandleFunc("PUT /actor/{id}", app.UpdateActor)
-	mux.HandleFunc("DELETE /actor/{id}", app.DeleteActor)
-	mux.HandleFunc("GET /actor/{id}", app.GetActor)
-	mux.HandleFunc("GET /actors", app.GetActors)
+	mux.HandleFunc("POST /actor", app.require(models.PermCatalogWrite, app.AddActor))
+	mux.HandleFunc("PUT /actor/{id}", app.require(models.PermCatalogWrite, app.UpdateActor))
+	mux.HandleFunc("DELETE /actor/{id}", app.require(models.PermCatalogWrite, app.DeleteActor))
+	mux.HandleFunc("GET /actor/{id}", app.require(models.PermCatalogRead, app.GetActor))
+	mux.HandleFunc("PUT /actor/{id}/photo", app.require(models.PermCatalogWrite, app.UploadActorPhoto))
+	mux.HandleFunc("DELETE /actor/{id}/photo", app.require(models.PermCatalogWrite, app.DeleteActorPhoto))
+	mux.HandleFunc("GET /actors", app.require(models.PermCatalogRead, app.GetActors))
+	mux.HandleFunc("GET /people/{id}/movies", app.require(models.PermCatalogRead, app.GetFilmography))
 
-	mux.HandleFunc("POST /movie", app.AddMovie)
-	mux.HandleFunc("DELETE /movie/{id}", app.DeleteMovie)
-	mux.HandleFunc("PUT /movie/{id}", app.UpdateMovie)
+	mux.HandleFunc("POST /movie", app.require(models.PermCatalogWrite, app.AddMovie))
+	mux.HandleFunc("DELETE /movie/{id}", app.require(models.PermCatalogWrite, app.DeleteMovie))
+	mux.HandleFunc("PUT /movie/{id}", app.require(models.PermCatalogWrite, app.UpdateMovie))
+	mux.HandleFunc("GET /movie/{id}", app.require(models.PermCatalogRead, app.GetMovie))
+	mux.HandleFunc("PUT /movie/{id}/poster", app.require(models.PermCatalogWrite, app.UploadMoviePoster))
+	mux.HandleFunc("DELETE /movie/{id}/poster", app.require(models.PermCatalogWrite, app.DeleteMoviePoster))
 
-	mux.HandleFunc("GET /movies", app.GetMovies)
-	mux.HandleFunc("GET /movies/name/{name}", app.GetMoviesByName)
-	mux.HandleFunc("GET /movies/actor/{actor}", app.GetMoviesByActor)
+	mux.HandleFunc("POST /movie/{id}/reviews", app.personal(app.AddReview))
+	mux.HandleFunc("GET /movie/{id}/reviews", app.require(models.PermCatalogRead, app.GetMovieReviews))
+	mux.HandleFunc("PUT /reviews/{id}", app.personal(app.UpdateReview))
+	mux.HandleFunc("DELETE /reviews/{id}", app.personal(app.DeleteReview))
 
-	mux.HandleFunc("POST /users", app.AddUser)
+	mux.HandleFunc("GET /movies", app.require(models.PermCatalogRead, app.GetMovies))
+	mux.HandleFunc("GET /movies/name/{name}", app.require(models.PermCatalogRead, app.GetMoviesByName))
+	mux.HandleFunc("GET /movies/actor/{actor}", app.require(models.PermCatalogRead, app.GetMoviesByActor))
 
-	return mux
+	mux.HandleFunc("POST /genres", app.require(models.PermCatalogWrite, app.AddGenre))
+	mux.HandleFunc("PUT /genres/{id}", app.require(models.PermCatalogWrite, app.UpdateGenre))
+	mux.HandleFunc("DELETE /genres/{id}", app.require(models.PermCatalogWrite, app.DeleteGenre))
+	mux.HandleFunc("GET /genres/{id}", app.require(models.PermCatalogRead, app.GetGenre))
+	mux.HandleFunc("GET /genres", app.require(models.PermCatalogRead, app.GetGenres))
+
+	mux.HandleFunc("POST /collections", app.personal(app.AddCollection))
+	mux.HandleFunc("PUT /collections/{id}", app.authenticated(app.UpdateCollection))
+	mux.HandleFunc("PUT /collections/{id}/movies", app.authenticated(app.SetCollectionMovies))
+	mux.HandleFunc("DELETE /collections/{id}", app.authenticated(app.DeleteCollection))
+	mux.HandleFunc("GET /collections/{id}", app.require(models.PermCatalogRead, app.GetCollection))
+	mux.HandleFunc("GET /collections", app.require(models.PermCatalogRead, app.GetCollections))
+
+	mux.HandleFunc("POST /series", app.require(models.PermCatalogWrite, app.AddSeries))
+	mux.HandleFunc("PUT /series/{id}", app.require(models.PermCatalogWrite, app.UpdateSeries))
+	mux.HandleFunc("DELETE /series/{id}", app.require(models.PermCatalogWrite, app.DeleteSeries))
+	mux.HandleFunc("GET /series/{id}", app.require(models.PermCatalogRead, app.GetSeries))
+	mux.HandleFunc("GET /series", app.require(models.PermCatalogRead, app.GetSeriesList))
+	mux.HandleFunc("POST /series/{id}/seasons", app.require(models.PermCatalogWrite, app.AddSeason))
+	mux.HandleFunc("PUT /seasons/{id}", app.require(models.PermCatalogWrite, app.UpdateSeason))
+	mux.HandleFunc("DELETE /seasons/{id}", app.require(models.PermCatalogWrite, app.DeleteSeason))
+	mux.HandleFunc("GET /seasons/{id}", app.require(models.PermCatalogRead, app.GetSeason))
+	mux.HandleFunc("POST /seasons/{id}/episodes", app.require(models.PermCatalogWrite, app.AddEpisode))
+	mux.HandleFunc("PUT /episodes/{id}", app.require(models.PermCatalogWrite, app.UpdateEpisode))
+	mux.HandleFunc("DELETE /episodes/{id}", app.require(models.PermCatalogWrite, app.DeleteEpisode))
+	mux.HandleFunc("GET /episodes/{id}", app.require(models.PermCatalogRead, app.GetEpisode))
+
+	mux.HandleFunc("GET /search", app.require(models.PermCatalogRead, app.Search))
+	mux.HandleFunc("GET /suggest", app.require(models.PermCatalogRead, app.Suggest))
+
+	mux.HandleFunc("POST /users", app.require(models.PermUsersManage, app.AddUser))
+	mux.HandleFunc("GET /users", app.require(models.PermUsersManage, app.GetUsers))
+	mux.HandleFunc("GET /users/{id}", app.require(models.PermUsersManage, app.GetUser))
+	mux.HandleFunc("PATCH /users/{id}", app.require(models.PermUsersManage, app.UpdateUser))
+	mux.HandleFunc("DELETE /users/{id}", app.require(models.PermUsersManage, app.DeleteUser))
+	mux.HandleFunc("PUT /users/me/password", app.authenticated(app.ChangePassword))
+	mux.HandleFunc("GET /users/me/reviews", app.personal(app.GetMyReviews))
+	mux.HandleFunc("GET /users/me/lists/{list}", app.personal(app.GetList))
+	mux.HandleFunc("PUT /users/me/lists/{list}/{id}", app.personal(app.AddToList))
+	mux.HandleFunc("DELETE /users/me/lists/{list}/{id}", app.personal(app.RemoveFromList))
+	mux.HandleFunc("GET /roles", app.require(models.PermUsersManage, app.GetRoles))
+
+	mux.HandleFunc("POST /auth/register", app.Register)
+	mux.HandleFunc("POST /auth/login", app.Login)
+	mux.HandleFunc("POST /auth/refresh", app.Refresh)
+	mux.HandleFunc("POST /auth/logout", app.Logout)
+
+	return app.withRequestId(mux)
 }
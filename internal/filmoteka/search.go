@@ -0,0 +1,120 @@
+package filmoteka
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+)
+
+// Search - обрабатывает http запрос на полнотекстовый поиск фильмов и актёров в фильмотеке.
+//
+// @Summary      Full-text search of movies and actors.
+// @Description  Search movies by name, description and cast and actors by name with Russian and English stemming.
+// @Description  Hits are ordered by rank, highlights are HTML: the catalog text is HTML-escaped and matches are wrapped in <b></b>. User should have the catalog:read permission.
+// @Tags         Search
+// @Produce      json
+// @Param        q query string true "Search query, supports quotes, OR and -"
+// @Param        limit query int false "Maximum number of hits of each type, 1 - 50" default(10)
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {object} models.SearchResult
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /search [get]
+func (app *App) Search(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to search movies and actors")
+	query := models.SearchQuery{Query: r.URL.Query().Get("q"), Limit: models.DefaultSearchLimit}
+	if s := r.URL.Query().Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil {
+			app.handleError(w, r, &models.FieldError{Field: "limit", Message: "limit must be an integer"}, http.StatusBadRequest)
+			return
+		}
+		query.Limit = limit
+	}
+	if err := query.Check(); err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := app.dbHandler.Search(query)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	app.sendJson(w, r, res)
+	app.infoLog.Println("movies and actors are searched")
+}
+
+// Suggest - обрабатывает http запрос на получение подсказок актёров или фильмов по префиксу.
+//
+// @Summary      Suggest actors or movies by prefix.
+// @Description  Get id and name pairs of actors or movies, whose name or a word in the name starts with the prefix.
+// @Description  Name prefix matches go first. User should have the catalog:read permission.
+// @Tags         Search
+// @Produce      json
+// @Param        prefix query string true "Beginning of the name"
+// @Param        type query string true "Type of suggestions" Enums(actor, movie)
+// @Param        limit query int false "Maximum number of suggestions, 1 - 50" default(10)
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {array} models.Suggestion
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /suggest [get]
+func (app *App) Suggest(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get suggestions")
+	query := models.SuggestQuery{
+		Prefix: r.URL.Query().Get("prefix"),
+		Type:   r.URL.Query().Get("type"),
+		Limit:  models.DefaultSearchLimit,
+	}
+	if s := r.URL.Query().Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil {
+			app.handleError(w, r, &models.FieldError{Field: "limit", Message: "limit must be an integer"}, http.StatusBadRequest)
+			return
+		}
+		query.Limit = limit
+	}
+	if err := query.Check(); err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	res, err := app.dbHandler.Suggest(query)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	app.sendJson(w, r, res)
+	app.infoLog.Printf("%s suggestions are getted\n", query.Type)
+}
+
+// parseNameSearch - получение поиска по имени из имени и query параметра similarity запроса.
+//
+// Принимает: запрос и искомое имя.
+//
+// Возвращает: поиск по имени и ошибку.
+func parseNameSearch(r *http.Request, name string) (models.NameSearch, error) {
+	search := models.NameSearch{Name: name, Similarity: models.DefaultSimilarity}
+	if s := r.URL.Query().Get("similarity"); s != "" {
+		similarity, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return models.NameSearch{}, &models.FieldError{Field: "similarity", Message: "similarity must be a number"}
+		}
+		search.Similarity = similarity
+	}
+
+	if err := search.Check(); err != nil {
+		return models.NameSearch{}, err
+	}
+	return search, nil
+}
@@ -0,0 +1,127 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+	dbHandler, _ := postgres.GetHandler(mockDB, false)
+	app := CreateApp(":8080", logger, logger, dbHandler, false, nil, RegistrationPolicy{}, nil)
+
+	search := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		app.Search(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery("websearch_to_tsquery(.+) FROM q, movies").WithArgs("матрица", 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "rating", "rank", "name_highlight", "description_highlight"}).
+				AddRow(1, "Матрица", 9, 0.6, "<b>Матрица</b>", "..."))
+		mock.ExpectQuery("websearch_to_tsquery(.+) FROM q, actors").WithArgs("матрица", 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "rank", "name_highlight"}))
+
+		w := search("/search?q=%D0%BC%D0%B0%D1%82%D1%80%D0%B8%D1%86%D0%B0&limit=5")
+		assert.Equal(t, http.StatusOK, w.Code)
+		var res models.SearchResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		assert.Equal(t, []models.MovieHit{{Id: 1, Name: "Матрица", Rating: 9, Rank: 0.6, NameHighlight: "<b>Матрица</b>", DescriptionHighlight: "..."}}, res.Movies)
+		assert.Equal(t, []models.ActorHit{}, res.Actors)
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		w := search("/search?q=+")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"q"`)
+	})
+
+	t.Run("wrong limit", func(t *testing.T) {
+		w := search("/search?q=matrix&limit=many")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"limit"`)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestParseNameSearch(t *testing.T) {
+	t.Run("default similarity", func(t *testing.T) {
+		search, err := parseNameSearch(httptest.NewRequest(http.MethodGet, "/movies/actor/Bezrukov", nil), "Bezrukov")
+		assert.NoError(t, err)
+		assert.Equal(t, models.NameSearch{Name: "Bezrukov", Similarity: models.DefaultSimilarity}, search)
+	})
+
+	t.Run("custom similarity", func(t *testing.T) {
+		search, err := parseNameSearch(httptest.NewRequest(http.MethodGet, "/actors?name=Bezrukov&similarity=0.7", nil), "Bezrukov")
+		assert.NoError(t, err)
+		assert.Equal(t, 0.7, search.Similarity)
+	})
+
+	t.Run("wrong similarity", func(t *testing.T) {
+		_, err := parseNameSearch(httptest.NewRequest(http.MethodGet, "/actors?name=Bezrukov&similarity=high", nil), "Bezrukov")
+		assert.Equal(t, []models.FieldError{{Field: "similarity", Message: "similarity must be a number"}}, models.FieldErrors(err))
+
+		_, err = parseNameSearch(httptest.NewRequest(http.MethodGet, "/actors?name=Bezrukov&similarity=2", nil), "Bezrukov")
+		assert.Equal(t, "similarity", models.FieldErrors(err)[0].Field)
+	})
+}
+
+func TestSuggest(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+	dbHandler, _ := postgres.GetHandler(mockDB, false)
+	app := CreateApp(":8080", logger, logger, dbHandler, false, nil, RegistrationPolicy{}, nil)
+
+	suggest := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		app.Suggest(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	t.Run("actors", func(t *testing.T) {
+		mock.ExpectQuery("FROM actors").WithArgs("bra%", "% Bra%", 3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "Brad Pitt").AddRow(5, "Jordana Brewster"))
+
+		w := suggest("/suggest?prefix=Bra&type=actor&limit=3")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"id": 2, "name": "Brad Pitt"}, {"id": 5, "name": "Jordana Brewster"}]`, w.Body.String())
+	})
+
+	t.Run("movies", func(t *testing.T) {
+		mock.ExpectQuery("FROM movies").WithArgs("ma%", "% ma%", models.DefaultSearchLimit).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+		w := suggest("/suggest?prefix=ma&type=movie")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "[]", w.Body.String())
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		w := suggest("/suggest?type=series")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"prefix"`)
+		assert.Contains(t, w.Body.String(), `"field":"type"`)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
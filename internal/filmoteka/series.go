@@ -0,0 +1,532 @@
+package filmoteka
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/models"
+)
+
+// AddSeries - обрабатывает http запрос на добавление сериала в фильмотеку.
+//
+// @Summary      Adds series to the System.
+// @Description  Add series to the System and get it's ID. User should have the catalog:write permission.
+// @Tags         Series
+// @Accept       json
+// @Produce      json
+// @Param        series body models.SeriesIn true "Series to be added"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {integer} int "ID of the added series"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /series [post]
+func (app *App) AddSeries(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to add a new series")
+	var series models.SeriesIn
+	d := json.NewDecoder(r.Body)
+	err := d.Decode(&series)
+	if err == nil {
+		err = series.Check()
+	}
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := app.dbHandler.AddSeries(series)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendJson(w, r, id)
+	app.infoLog.Printf("series %d is added\n", id)
+}
+
+// UpdateSeries - обрабатывает http запрос на обновление сериала в фильмотеке.
+//
+// @Summary      Updates series in the System.
+// @Description  Update series in the System, empty fields are left unchanged. User should have the catalog:write permission.
+// @Tags         Series
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID of the series to be updated"
+// @Param        series body models.SeriesIn true "Series data to be updated"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Series not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /series/{id} [put]
+func (app *App) UpdateSeries(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to update a series")
+	var series models.SeriesIn
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&series); err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := series.CheckPatch(); err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.dbHandler.UpdateSeries(id, series); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("series %d is updated\n", id)
+}
+
+// DeleteSeries - обрабатывает http запрос на удаление сериала из фильмотеки.
+//
+// @Summary      Deletes series from the System.
+// @Description  Delete series from the System together with its seasons and episodes. User should have the catalog:write permission.
+// @Tags         Series
+// @Produce      json
+// @Param        id path int true "ID of the series to be deleted"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Series not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /series/{id} [delete]
+func (app *App) DeleteSeries(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to delete a series")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.dbHandler.DeleteSeries(id); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("series %d is deleted\n", id)
+}
+
+// GetSeries - обрабатывает http запрос на получение сериала из фильмотеки.
+//
+// @Summary      Get series from the System.
+// @Description  Get series from the System with its seasons and episodes ordered by number. User should have the catalog:read permission.
+// @Tags         Series
+// @Produce      json
+// @Param        id path int true "ID of the series to be getted"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {object} models.SeriesOut
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Series not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /series/{id} [get]
+func (app *App) GetSeries(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get a series")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	series, err := app.dbHandler.GetSeries(id)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendJson(w, r, series)
+	app.infoLog.Printf("series %d is getted\n", id)
+}
+
+// GetSeriesList - обрабатывает http запрос на получение списка сериалов из фильмотеки.
+//
+// @Summary      Get series from the System.
+// @Description  Get series from the System without seasons. User should have the catalog:read permission.
+// @Tags         Series
+// @Produce      json
+// @Param        sort query string false "Comma separated sort fields (id, name, release_date, rating), prefixed with - for descending order" default(-rating)
+// @Param        limit query int false "Page size, 1 - 500" default(50)
+// @Param        offset query int false "Number of items to skip" default(0)
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {array} models.SeriesOut
+// @Header       200 {integer} X-Total-Count "Total number of items"
+// @Header       200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /series [get]
+func (app *App) GetSeriesList(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get list of series")
+	sort, err := parseSort(r, models.SeriesSortFields, models.DefaultSeriesSort)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	series, total, err := app.dbHandler.GetSeriesList(sort, page)
+	if err != nil {
+		app.handleError(w, r, err, http.StatusInternalServerError)
+		return
+	}
+
+	app.sendPage(w, r, page, total, series)
+	app.infoLog.Println("list of series is getted")
+}
+
+// AddSeason - обрабатывает http запрос на добавление сезона в сериал.
+//
+// @Summary      Adds season to the series.
+// @Description  Add season to the series and get it's ID. User should have the catalog:write permission. Numbers are unique within the series.
+// @Tags         Series
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID of the series"
+// @Param        season body models.SeasonIn true "Season to be added"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {integer} int "ID of the added season"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Series not found"
+// @Failure      409 {object} models.Problem "Season with the number already exists"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /series/{id}/seasons [post]
+func (app *App) AddSeason(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to add a new season")
+	var season models.SeasonIn
+	d := json.NewDecoder(r.Body)
+	err := d.Decode(&season)
+	if err == nil {
+		err = season.Check()
+	}
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	parentId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	id, err := app.dbHandler.AddSeason(parentId, season)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendJson(w, r, id)
+	app.infoLog.Printf("season %d is added\n", id)
+}
+
+// UpdateSeason - обрабатывает http запрос на обновление сезона в фильмотеке.
+//
+// @Summary      Updates season in the System.
+// @Description  Update season of the series, empty fields are left unchanged. User should have the catalog:write permission.
+// @Tags         Series
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID of the season to be updated"
+// @Param        season body models.SeasonIn true "Season data to be updated"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Season not found"
+// @Failure      409 {object} models.Problem "Season with the number already exists"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /seasons/{id} [put]
+func (app *App) UpdateSeason(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to update a season")
+	var season models.SeasonIn
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&season); err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := season.CheckPatch(); err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.dbHandler.UpdateSeason(id, season); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("season %d is updated\n", id)
+}
+
+// DeleteSeason - обрабатывает http запрос на удаление сезона из фильмотеки.
+//
+// @Summary      Deletes season from the System.
+// @Description  Delete season from the System together with its episodes. User should have the catalog:write permission.
+// @Tags         Series
+// @Produce      json
+// @Param        id path int true "ID of the season to be deleted"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Season not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /seasons/{id} [delete]
+func (app *App) DeleteSeason(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to delete a season")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.dbHandler.DeleteSeason(id); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("season %d is deleted\n", id)
+}
+
+// GetSeason - обрабатывает http запрос на получение сезона из фильмотеки.
+//
+// @Summary      Get season from the System.
+// @Description  Get season from the System with its episodes ordered by number. User should have the catalog:read permission.
+// @Tags         Series
+// @Produce      json
+// @Param        id path int true "ID of the season to be getted"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {object} models.SeasonOut
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Season not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /seasons/{id} [get]
+func (app *App) GetSeason(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get a season")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	season, err := app.dbHandler.GetSeason(id)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendJson(w, r, season)
+	app.infoLog.Printf("season %d is getted\n", id)
+}
+
+// AddEpisode - обрабатывает http запрос на добавление серии в сезон.
+//
+// @Summary      Adds episode to the season.
+// @Description  Add episode to the season and get it's ID. User should have the catalog:write permission. Numbers are unique within the season.
+// @Tags         Series
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID of the season"
+// @Param        episode body models.EpisodeIn true "Episode to be added"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {integer} int "ID of the added episode"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Season not found"
+// @Failure      409 {object} models.Problem "Episode with the number already exists"
+// @Failure      422 {object} models.Problem "Actor not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /seasons/{id}/episodes [post]
+func (app *App) AddEpisode(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to add a new episode")
+	var episode models.EpisodeIn
+	d := json.NewDecoder(r.Body)
+	err := d.Decode(&episode)
+	if err == nil {
+		err = episode.Check()
+	}
+	if err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	parentId, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	id, err := app.dbHandler.AddEpisode(parentId, episode)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendJson(w, r, id)
+	app.infoLog.Printf("episode %d is added\n", id)
+}
+
+// UpdateEpisode - обрабатывает http запрос на обновление серии в фильмотеке.
+//
+// @Summary      Updates episode in the System.
+// @Description  Update episode of the season, empty fields are left unchanged. User should have the catalog:write permission. Cast replaces all guest actors of the episode.
+// @Tags         Series
+// @Accept       json
+// @Produce      json
+// @Param        id path int true "ID of the episode to be updated"
+// @Param        episode body models.EpisodeIn true "Episode data to be updated"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Episode not found"
+// @Failure      409 {object} models.Problem "Episode with the number already exists"
+// @Failure      422 {object} models.Problem "Actor not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /episodes/{id} [put]
+func (app *App) UpdateEpisode(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to update a episode")
+	var episode models.EpisodeIn
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&episode); err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	if err := episode.CheckPatch(); err != nil {
+		app.handleError(w, r, err, http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.dbHandler.UpdateEpisode(id, episode); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("episode %d is updated\n", id)
+}
+
+// DeleteEpisode - обрабатывает http запрос на удаление серии из фильмотеки.
+//
+// @Summary      Deletes episode from the System.
+// @Description  Delete episode from the System. User should have the catalog:write permission.
+// @Tags         Series
+// @Produce      json
+// @Param        id path int true "ID of the episode to be deleted"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {string} string "OK"
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Episode not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /episodes/{id} [delete]
+func (app *App) DeleteEpisode(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to delete a episode")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	if err := app.dbHandler.DeleteEpisode(id); err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	app.infoLog.Printf("episode %d is deleted\n", id)
+}
+
+// GetEpisode - обрабатывает http запрос на получение серии из фильмотеки.
+//
+// @Summary      Get episode from the System.
+// @Description  Get episode from the System with its guest cast. User should have the catalog:read permission.
+// @Tags         Series
+// @Produce      json
+// @Param        id path int true "ID of the episode to be getted"
+// @Security BasicAuth
+// @Security BearerAuth
+// @Success      200 {object} models.EpisodeOut
+// @Failure      400 {object} models.Problem "Bad request"
+// @Failure      401 {object} models.Problem "User is not authenticated"
+// @Failure      403 {object} models.Problem "Permission denied"
+// @Failure      404 {object} models.Problem "Episode not found"
+// @Failure      500 {object} models.Problem "Internal server error"
+// @Router       /episodes/{id} [get]
+func (app *App) GetEpisode(w http.ResponseWriter, r *http.Request) {
+	app.infoLog.Println("trying to get a episode")
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		app.handleError(w, r, errIdNotInteger, http.StatusBadRequest)
+		return
+	}
+
+	episode, err := app.dbHandler.GetEpisode(id)
+	if err != nil {
+		app.handleError(w, r, err, dbErrorStatus(err))
+		return
+	}
+
+	app.sendJson(w, r, episode)
+	app.infoLog.Printf("episode %d is getted\n", id)
+}
@@ -0,0 +1,126 @@
+package filmoteka
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeries(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+	dbHandler, _ := postgres.GetHandler(mockDB, false)
+	app := CreateApp(":8080", logger, logger, dbHandler, false, nil, RegistrationPolicy{}, nil)
+
+	t.Run("add series", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO series").WithArgs("Dark", "Time travel", sqlmock.AnyArg(), 9).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		w := httptest.NewRecorder()
+		body := `{"name": "Dark", "description": "Time travel", "release_date": "2017-12-01T00:00:00Z", "rating": 9}`
+		app.AddSeries(w, httptest.NewRequest(http.MethodPost, "/series", strings.NewReader(body)))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", strings.TrimSpace(w.Body.String()))
+	})
+
+	t.Run("add invalid series", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		app.AddSeries(w, httptest.NewRequest(http.MethodPost, "/series", strings.NewReader(`{"name": "Dark", "rating": 11}`)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"field":"rating"`)
+	})
+
+	t.Run("add season with taken number", func(t *testing.T) {
+		mock.ExpectQuery("INSERT INTO seasons").WithArgs(1, 2, "").
+			WillReturnError(&pq.Error{Code: "23505", Constraint: "seasons_series_id_number_key"})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/series/1/seasons", strings.NewReader(`{"number": 2}`))
+		r.SetPathValue("id", "1")
+		app.AddSeason(w, r)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "season with this number already exists")
+	})
+
+	t.Run("add episode to unknown season", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO episodes").WithArgs(5, 1, "Secrets", "", sqlmock.AnyArg(), 8).
+			WillReturnError(&pq.Error{Code: "23503", Constraint: "episodes_season_id_fkey"})
+		mock.ExpectRollback()
+
+		w := httptest.NewRecorder()
+		body := `{"number": 1, "name": "Secrets", "release_date": "2017-12-01T00:00:00Z", "rating": 8}`
+		r := httptest.NewRequest(http.MethodPost, "/seasons/5/episodes", strings.NewReader(body))
+		r.SetPathValue("id", "5")
+		app.AddEpisode(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "unknown season")
+	})
+
+	t.Run("update episode cast", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE episodes").WithArgs(3, 0, "", "", nil, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM episode_actors").WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO episode_actors").WithArgs(3, 4, "Jonas", 1, false, false).
+			WillReturnError(&pq.Error{Code: "23503", Constraint: "episode_actors_actor_id_fkey"})
+		mock.ExpectRollback()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/episodes/3", strings.NewReader(`{"cast": [{"actor_id": 4, "character": "Jonas"}]}`))
+		r.SetPathValue("id", "3")
+		app.UpdateEpisode(w, r)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "actor 4 does not exist")
+	})
+
+	t.Run("get season", func(t *testing.T) {
+		date := time.Date(2017, 12, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery("FROM seasons WHERE id").WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "series_id", "number", "name"}).AddRow(2, 1, 1, ""))
+		mock.ExpectQuery("FROM episodes WHERE season_id").WithArgs(pq.Array([]int{2})).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "season_id", "number", "name", "description", "release_date", "rating"}).
+				AddRow(3, 2, 1, "Secrets", "", date, 8))
+		mock.ExpectQuery("FROM episode_actors").WithArgs(pq.Array([]int{3})).
+			WillReturnRows(sqlmock.NewRows([]string{"episode_id", "actor_id", "character_name", "billing", "cameo", "voice"}).
+				AddRow(3, 4, "Jonas", 1, false, false))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/seasons/2", nil)
+		r.SetPathValue("id", "2")
+		app.GetSeason(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id": 2, "series_id": 1, "number": 1, "name": "", "episodes": [{"id": 3, "season_id": 2, "number": 1,
+			"name": "Secrets", "description": "", "release_date": "2017-12-01T00:00:00Z", "rating": 8,
+			"cast": [{"actor_id": 4, "character": "Jonas", "billing": 1, "cameo": false, "voice": false}]}]}`, w.Body.String())
+	})
+
+	t.Run("delete unknown series", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM series").WithArgs(9).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodDelete, "/series/9", nil)
+		r.SetPathValue("id", "9")
+		app.DeleteSeries(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "unknown series")
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,152 @@
+// Пакет storage реализует хранение файлов изображений фильмотеки.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// ErrNotFound - ошибка отсутствия файла с указанным ключом.
+	ErrNotFound = errors.New("file not found")
+	// ErrInvalidKey - ошибка некорректного ключа файла.
+	ErrInvalidKey = errors.New("invalid file key")
+)
+
+// Storage - хранилище файлов.
+//
+// Файлы адресуются ключами вида "movies/1/poster.jpg", разделённых символом /.
+type Storage interface {
+	// Save - сохраняет файл, заменяя существующий файл с тем же ключом.
+	//
+	// Принимает: ключ файла и его содержимое.
+	//
+	// Возвращает: ошибку (ErrInvalidKey, если ключ некорректен).
+	Save(key string, r io.Reader) error
+
+	// Open - открывает файл для чтения.
+	//
+	// Принимает: ключ файла.
+	//
+	// Возвращает: содержимое файла и ошибку (ErrNotFound, если файла нет).
+	Open(key string) (io.ReadCloser, error)
+
+	// Delete - удаляет файл, отсутствие файла ошибкой не считается.
+	//
+	// Принимает: ключ файла.
+	//
+	// Возвращает: ошибку.
+	Delete(key string) error
+
+	// URL - возвращает адрес, по которому клиент может получить файл.
+	//
+	// Принимает: ключ файла.
+	//
+	// Возвращает: адрес файла.
+	URL(key string) string
+}
+
+// FS - хранилище файлов в каталоге файловой системы.
+type FS struct {
+	dir     string // dir - корневой каталог хранилища.
+	baseURL string // baseURL - адрес, к которому добавляется ключ файла.
+}
+
+// NewFS - создание хранилища файлов в каталоге файловой системы.
+//
+// Принимает: каталог, создаваемый при отсутствии, и адрес, по которому раздаются файлы (например, "/media/").
+//
+// Возвращает: хранилище и ошибку.
+func NewFS(dir, baseURL string) (*FS, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Join(fmt.Errorf("error while creating storage directory %q", dir), err)
+	}
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	return &FS{dir: dir, baseURL: baseURL}, nil
+}
+
+// Save - сохранение файла в каталог.
+//
+// Файл сначала записывается во временный файл, поэтому читатели не видят частично записанного содержимого.
+func (s *FS) Save(key string, r io.Reader) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	wrapErr := fmt.Errorf("error while saving file %q", key)
+	if err = os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return errors.Join(wrapErr, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return errors.Join(wrapErr, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return errors.Join(wrapErr, err)
+	}
+	if err = tmp.Close(); err != nil {
+		return errors.Join(wrapErr, err)
+	}
+	if err = os.Rename(tmp.Name(), name); err != nil {
+		return errors.Join(wrapErr, err)
+	}
+	return nil
+}
+
+// Open - открытие файла из каталога.
+func (s *FS) Open(key string) (io.ReadCloser, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = errors.Join(err, ErrNotFound)
+		}
+		return nil, errors.Join(fmt.Errorf("error while opening file %q", key), err)
+	}
+	if info, err := f.Stat(); err == nil && info.IsDir() {
+		f.Close()
+		return nil, errors.Join(fmt.Errorf("error while opening file %q", key), ErrNotFound)
+	}
+	return f, nil
+}
+
+// Delete - удаление файла из каталога.
+func (s *FS) Delete(key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.Join(fmt.Errorf("error while deleting file %q", key), err)
+	}
+	return nil
+}
+
+// URL - получение адреса файла.
+func (s *FS) URL(key string) string {
+	return s.baseURL + (&url.URL{Path: key}).EscapedPath()
+}
+
+// path - получение пути к файлу по ключу.
+//
+// Возвращает: путь внутри корневого каталога и ошибку (ErrInvalidKey, если ключ пуст или выходит за пределы каталога).
+func (s *FS) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return "", errors.Join(fmt.Errorf("error while resolving file %q", key), ErrInvalidKey)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFS(t *testing.T) {
+	s, err := NewFS(t.TempDir(), "/media")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("save, open and delete", func(t *testing.T) {
+		assert.NoError(t, s.Save("movies/1/poster.jpg", strings.NewReader("image")))
+		f, err := s.Open("movies/1/poster.jpg")
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(f)
+		f.Close()
+		assert.Equal(t, "image", string(data))
+
+		assert.NoError(t, s.Delete("movies/1/poster.jpg"))
+		_, err = s.Open("movies/1/poster.jpg")
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NoError(t, s.Delete("movies/1/poster.jpg"))
+	})
+
+	t.Run("directory is not a file", func(t *testing.T) {
+		assert.NoError(t, s.Save("actors/2/photo.png", strings.NewReader("image")))
+		_, err := s.Open("actors/2")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		for _, key := range []string{"", "/etc/passwd", "../secret", "movies/../../secret", "movies//1", `movies\1`} {
+			assert.ErrorIs(t, s.Save(key, strings.NewReader("")), ErrInvalidKey, key)
+			_, err := s.Open(key)
+			assert.ErrorIs(t, err, ErrInvalidKey, key)
+		}
+	})
+
+	t.Run("url", func(t *testing.T) {
+		assert.Equal(t, "/media/movies/1/poster%20new.jpg", s.URL("movies/1/poster new.jpg"))
+	})
+}
@@ -0,0 +1,70 @@
+package filmoteka
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/famusovsky/VkTestTask/internal/filmoteka/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateUnknown(t *testing.T) {
+	logger := log.New(io.Discard, "test", log.LstdFlags)
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+	dbHandler, _ := postgres.GetHandler(mockDB, false)
+	app := CreateApp(":8080", logger, logger, dbHandler, false, nil, RegistrationPolicy{}, nil)
+
+	t.Run("empty actor patch", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/actor/9", strings.NewReader(`{}`))
+		r.SetPathValue("id", "9")
+		app.UpdateActor(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "nothing to update")
+	})
+
+	t.Run("empty movie patch", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/movie/9", strings.NewReader(`{}`))
+		r.SetPathValue("id", "9")
+		app.UpdateMovie(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "nothing to update")
+	})
+
+	t.Run("genres of unknown movie", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM movies").WithArgs(9).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/movie/9", strings.NewReader(`{"genres": [1]}`))
+		r.SetPathValue("id", "9")
+		app.UpdateMovie(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "unknown movie")
+	})
+
+	t.Run("cast of unknown movie", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT id FROM movies").WithArgs(9).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/movie/9", strings.NewReader(`{"cast": [{"actor_id": 2}]}`))
+		r.SetPathValue("id", "9")
+		app.UpdateMovie(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
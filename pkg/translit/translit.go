@@ -0,0 +1,121 @@
+// Пакет для транслитерации между кириллицей и латиницей
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+// toLatin - таблица транслитерации строчных букв кириллицы в латиницу.
+var toLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+}
+
+// toCyrillicDigraphs - сочетания латинских букв, транслитерируемые в одну букву кириллицы,
+// в порядке убывания длины.
+var toCyrillicDigraphs = []struct {
+	latin    string
+	cyrillic string
+}{
+	{"shch", "щ"}, {"sch", "щ"}, {"zh", "ж"}, {"kh", "х"}, {"ts", "ц"}, {"ch", "ч"},
+	{"sh", "ш"}, {"yo", "ё"}, {"yu", "ю"}, {"ya", "я"}, {"ye", "е"},
+}
+
+// toCyrillic - таблица транслитерации строчных букв латиницы в кириллицу.
+var toCyrillic = map[rune]string{
+	'a': "а", 'b': "б", 'c': "к", 'd': "д", 'e': "е", 'f': "ф", 'g': "г", 'h': "х",
+	'i': "и", 'j': "дж", 'k': "к", 'l': "л", 'm': "м", 'n': "н", 'o': "о", 'p': "п",
+	'q': "к", 'r': "р", 's': "с", 't': "т", 'u': "у", 'v': "в", 'w': "в", 'x': "кс",
+	'z': "з",
+}
+
+// ToLatin - транслитерация кириллицы в латиницу.
+//
+// Принимает: строку.
+//
+// Возвращает: строку, в которой буквы кириллицы заменены латинскими, остальные символы не меняются.
+func ToLatin(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		lat, ok := toLatin[unicode.ToLower(r)]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+		writeCase(&b, lat, unicode.IsUpper(r))
+	}
+	return b.String()
+}
+
+// ToCyrillic - транслитерация латиницы в кириллицу.
+//
+// Принимает: строку.
+//
+// Возвращает: строку, в которой латинские буквы заменены кириллицей, остальные символы не меняются.
+//
+// Буквосочетания (zh, kh, shch и т.д.) заменяются одной буквой,
+// y после гласной считается й, в остальных случаях - ы.
+func ToCyrillic(s string) string {
+	runes := []rune(s)
+	lower := []rune(strings.ToLower(s))
+	if len(lower) != len(runes) {
+		lower = runes
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		upper := unicode.IsUpper(runes[i])
+		if cyr, n := digraph(lower[i:]); n != 0 {
+			writeCase(&b, cyr, upper)
+			i += n
+			continue
+		}
+
+		switch r := lower[i]; {
+		case r == 'y':
+			if i > 0 && isVowel(lower[i-1]) {
+				writeCase(&b, "й", upper)
+			} else {
+				writeCase(&b, "ы", upper)
+			}
+		case toCyrillic[r] != "":
+			writeCase(&b, toCyrillic[r], upper)
+		default:
+			b.WriteRune(runes[i])
+		}
+		i++
+	}
+	return b.String()
+}
+
+// digraph - поиск буквосочетания в начале строки.
+//
+// Возвращает: букву кириллицы и длину буквосочетания (0, если его нет).
+func digraph(s []rune) (string, int) {
+	for _, d := range toCyrillicDigraphs {
+		if len(s) >= len(d.latin) && string(s[:len(d.latin)]) == d.latin {
+			return d.cyrillic, len(d.latin)
+		}
+	}
+	return "", 0
+}
+
+// isVowel - проверка того, что латинская буква гласная.
+func isVowel(r rune) bool {
+	return strings.ContainsRune("aeiou", r)
+}
+
+// writeCase - запись замены с сохранением регистра исходной буквы.
+func writeCase(b *strings.Builder, s string, upper bool) {
+	if !upper || s == "" {
+		b.WriteString(s)
+		return
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	b.WriteString(string(r))
+}
@@ -0,0 +1,38 @@
+package translit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToLatin(t *testing.T) {
+	t.Run("cyrillic", func(t *testing.T) {
+		assert.Equal(t, "Bezrukov", ToLatin("Безруков"))
+		assert.Equal(t, "Sergey Shchukin", ToLatin("Сергей Щукин"))
+		assert.Equal(t, "Yuliya Khvostova", ToLatin("Юлия Хвостова"))
+	})
+
+	t.Run("other symbols", func(t *testing.T) {
+		assert.Equal(t, "Brad Pitt 1963", ToLatin("Brad Pitt 1963"))
+		assert.Equal(t, "", ToLatin(""))
+	})
+}
+
+func TestToCyrillic(t *testing.T) {
+	t.Run("latin", func(t *testing.T) {
+		assert.Equal(t, "Безруков", ToCyrillic("Bezrukov"))
+		assert.Equal(t, "Сергей Щукин", ToCyrillic("Sergey Shchukin"))
+		assert.Equal(t, "Юлия Хвостова", ToCyrillic("Yuliya Khvostova"))
+		assert.Equal(t, "Жанна Цыганова", ToCyrillic("Zhanna Tsyganova"))
+	})
+
+	t.Run("other symbols", func(t *testing.T) {
+		assert.Equal(t, "Безруков 1973", ToCyrillic("Безруков 1973"))
+		assert.Equal(t, "", ToCyrillic(""))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		assert.Equal(t, "Константин Хабенский", ToCyrillic(ToLatin("Константин Хабенский")))
+	})
+}